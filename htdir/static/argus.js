@@ -32,7 +32,10 @@ var gizmoConfig = [
     { el: 'listnotify',   url: '/api/listnotify', args: {}, freq: 30000 },
     { el: 'listunacked',   url: '/api/listnotify', args: {}, freq: 30000 },
     { el: 'listdown',     url: '/api/listdown',   args: {}, freq: 30000 },
-    { el: 'listoverride', url: '/api/listov',     args: {}, freq: 30000 }
+    { el: 'listoverride', url: '/api/listov',     args: {}, freq: 30000 },
+    { el: 'listsilence',  url: '/api/listsilence', args: {}, freq: 30000 },
+    { el: 'listincident', url: '/api/listincident', args: {}, freq: 30000 },
+    { el: 'listtokens',   url: '/api/tokens',     args: {} }
 ]
 
 
@@ -147,6 +150,41 @@ function build_page_ok(d){
 
     configure_graphs('pagegraph')
     setInterval( build_page, 30000 )
+    live_stream(dataarg.obj)
+}
+
+// ****************************************************************
+// server-sent events - update as things happen, instead of waiting for the next poll
+
+var livestream
+var livetimer
+
+function live_stream(obj){
+
+    if( !window.EventSource || livestream ) return
+
+    var url = '/api/stream?obj=' + encodeURIComponent(obj) + '&types=transition,override,annotation,ack,snooze,notify'
+    livestream = new EventSource(url)
+
+    var types = ['transition', 'override', 'annotation', 'ack', 'snooze', 'notify']
+    for(var i=0; i<types.length; i++){
+        livestream.addEventListener(types[i], live_event)
+    }
+}
+
+function live_event(e){
+
+    argus.log("live event " + e.type)
+
+    // several events often arrive together, only update once
+    if( livetimer ) return
+    livetimer = setTimeout(function(){
+        livetimer = null
+        build_page()
+        for(var k in gizmo){
+            gizmo[k].FetchNow()
+        }
+    }, 500)
 }
 
 // ****************************************************************
@@ -305,6 +343,180 @@ function override_success(r){
 }
 
 
+// ****************************************************************
+
+function silence_save(){
+
+    var args = { xtok: token }
+
+    args.path     = $('#silenceform input[name=path]').val();
+    args.tag      = $('#silenceform input[name=tag]').val();
+    args.regex    = $('#silenceform input[name=regex]').val();
+    args.comment  = $('#silenceform input[name=comment]').val();
+    args.duration = $('#silenceform select[name=duration]').val();
+    argus.log("save silence " + args )
+
+    silence_post(args)
+}
+
+function silence_remove(id){
+
+    var args = { remove: id, xtok: token }
+    silence_post(args)
+}
+
+function silence_post(args){
+
+    spinner_on()
+
+    $.ajax({
+        type:	    'POST',
+        url:	    '/api/silence',
+        data:       args,
+        dataType:   'json',
+        timeout:    5000,
+        success:    silence_success,
+        error:      ajax_fail,
+    });
+}
+
+function silence_success(r){
+
+    spinner_off()
+    if( gizmo['listsilence'] ){
+        gizmo['listsilence'].gotData(r)
+    }
+}
+
+// ****************************************************************
+
+function token_new(){
+
+    var args = { xtok: token }
+
+    args.label   = $('#tokenform input[name=label]').val();
+    args.scope   = $('#tokenform select[name=scope]').val();
+    args.expires = $('#tokenform select[name=expires]').val();
+
+    spinner_on()
+
+    $.ajax({
+        type:	    'POST',
+        url:	    '/api/tokennew',
+        data:       args,
+        dataType:   'json',
+        timeout:    5000,
+        success:    function(r){
+            spinner_off()
+            $('#newtokentext').text(r.Token)
+            $('#newtoken').show()
+            $('#tokenform input[name=label]').val("")
+            gizmo['listtokens'].FetchNow()
+        },
+        error:      ajax_fail,
+    });
+}
+
+function token_revoke(id){
+
+    spinner_on()
+    $('#newtoken').hide()
+
+    $.ajax({
+        type:	    'POST',
+        url:	    '/api/tokenrevoke',
+        data:       { id: id, xtok: token },
+        dataType:   'json',
+        timeout:    5000,
+        success:    function(r){ gizmo['listtokens'].gotData(r) },
+        error:      ajax_fail,
+    });
+}
+
+// ****************************************************************
+
+function incident_act(id, act){
+
+    spinner_on()
+
+    $.ajax({
+        type:	    'POST',
+        url:	    '/api/incidentupdate',
+        data:       { id: id, act: act, xtok: token },
+        dataType:   'json',
+        timeout:    5000,
+        success:    incident_success,
+        error:      ajax_fail,
+    });
+}
+
+function incident_success(r){
+
+    spinner_off()
+    if( gizmo['listincident'] ){
+        gizmo['listincident'].FetchNow()
+    }
+}
+
+// ****************************************************************
+
+function sla_args(){
+
+    var args = { obj: objname }
+
+    args.period   = $('#slaform select[name=period]').val()
+    args.severity = $('#slaform select[name=severity]').val()
+    args.subtree  = $('#slaform input[name=subtree]').is(':checked') ? 'yes' : 'no'
+
+    var start = $('#slaform input[name=start]').val()
+    var end   = $('#slaform input[name=end]').val()
+
+    // a named period in the start box. eg. 2026-Q3
+    if( start && !end && !start.match(/^\d{4}-\d\d-\d\d/) ){
+        args.period = start
+    }else if( start ){
+        args.start = start
+        args.end   = end
+    }
+    return args
+}
+
+function sla_run(){
+
+    var args = sla_args()
+
+    $('#slacsv').attr('href', '/api/sla?' + $.param( $.extend({fmt: 'csv'}, args) ))
+
+    if( gizmo['slareport'] ){
+        gizmo['slareport'].urlArgs = args
+        gizmo['slareport'].FetchNow()
+        return
+    }
+    gizmo['slareport'] = new Gizmo('#slareport', '/api/sla', args)
+}
+
+// ****************************************************************
+
+function history_run(){
+
+    var args = {}
+    var i, k
+    var keys = ['start', 'end', 'path', 'tag', 'user']
+
+    for(i=0; i<keys.length; i++){
+        k = keys[i]
+        args[k] = $('#historyform input[name=' + k + ']').val()
+    }
+    args.type = $('#historyform select[name=type]').val()
+
+    if( gizmo['listhistory'] ){
+        gizmo['listhistory'].urlArgs = args
+        gizmo['listhistory'].FetchNow()
+        return
+    }
+    gizmo['listhistory'] = new Gizmo('#listhistory', '/api/history', args)
+}
+
 // ****************************************************************
 
 function annotate_edit(){
@@ -461,13 +673,20 @@ function notify_dismiss(){
     $('#notifydetailouter').fadeOut()
 }
 
-function notify_ack(idno){
+function notify_ack(idno, opts){
 
     argus.log("ack: " + idno)
     notify_dismiss()
 
+    var args = { idno: idno, xtok: token }
+    var snooze = false
+    if( opts ){
+        if( opts.snooze ){ args.snooze = opts.snooze; snooze = true }
+        if( opts.for ){ args.for = opts.for }
+    }
+
     // update notify list gizmo?
-    if( gizmo['listnotify'] ){
+    if( gizmo['listnotify'] && !snooze ){
         var n = gizmo['listnotify'].data.list
         var i;
 
@@ -479,8 +698,6 @@ function notify_ack(idno){
         }
     }
 
-    var args = { idno: idno, xtok: token }
-
    $.ajax({
        type:	   'POST',
        url:	   '/api/notifyack',
@@ -705,7 +922,7 @@ function convert_data(o){
         }
 
         if( typeof(c) == "number" ){
-            if( (k == "Status") || (k == "OvStatus") || (k == "Sev") || (k == "status") || (k == "ovstatus") ){
+            if( (k == "Status") || (k == "OvStatus") || (k == "Sev") || (k == "Severity") || (k == "status") || (k == "ovstatus") ){
                 o[k + "_fmt"] = argus.status(c)
                 o[k + "_sev"] = argus.sev(c)
                 o[k + "_sevf"] = argus.sev(c) + "-f" // reverse color
@@ -34,26 +34,31 @@ function configure_graphs(cname){
         var which = el.attr("data-which") || 'samples'
         var darp  = el.attr("data-darp")  // empty == all
         var ctls  = el.attr("data-ctls")  // should we display controls?
+        var cmp   = {
+            offsets: el.attr("data-offsets"), // comparison choices, eg. "1d,7d,28d"
+            also:    el.attr("data-also")     // other objects to compare against
+        }
 
         var wid   = el.width()
-        new Graph(el.get(0), obj, which, darp, ctls, wid)
+        new Graph(el.get(0), obj, which, darp, ctls, wid, cmp)
     })
 }
 
 var graphd	// for debugging
 
-function Graph(el, obj, which, darp, ctls, width){
+function Graph(el, obj, which, darp, ctls, width, cmp){
 
     argus.log("new graph " + obj)
     this.el       = el
     this.obj      = obj
     this.ctls     = ctls
     this.width    = width
+    this.cmp      = cmp || {}
     this.pending  = {}
     this.datasets = {}
     this.grctlid  = 'grctl' + gcount
     this.selected = {}
-    this.select   = { which: which, darp: {}, supplement: '', obj: {} }
+    this.select   = { which: which, darp: {}, supplement: '', obj: {}, offset: 0 }
     if( darp ) this.select.darp[ darp ] = 1
 
     // info{}, cs, darptags{}
@@ -73,10 +78,14 @@ function Graph(el, obj, which, darp, ctls, width){
     p.fetchGraphInfo = function(){
 
         var g = this
+        var args = {obj: this.obj}
+        if( this.cmp.offsets ) args.offsets = this.cmp.offsets
+        if( this.cmp.also )    args.also    = this.cmp.also
+
         $.ajax({
             dataType: "json",
             url: '/api/graph',
-            data: {obj: this.obj},
+            data: args,
             success: function(r){   g.gotGraphInfo(r) },
             error: function(a,b,c){ g.ajaxFail(a,b,c) }
         });
@@ -95,7 +104,7 @@ function Graph(el, obj, which, darp, ctls, width){
             return
         }
 
-        // Title, YLabel, MyId, List[]{Obj, Label, Hwab, Tags[]
+        // Title, YLabel, MyId, List[]{Obj, Label, Hwab, Tags[]}, Offsets[]{Label, Offset}
         this.info = r.graph
 
         // create chart
@@ -162,6 +171,15 @@ function Graph(el, obj, which, darp, ctls, width){
                                             ['minmax', 'Min/Max'], ['stdev', 'Std Dev']]) +
                 '</div>' + "\n"
 
+            // compare with the past
+            if( this.info.Offsets && this.info.Offsets.length ){
+                var cmp = [['0', 'No Compare']]
+                for(i=0; i<this.info.Offsets.length; i++){
+                    cmp.push( [this.info.Offsets[i].Offset, this.info.Offsets[i].Label] )
+                }
+                html += '<div class=graphcompare>' + this.radioButtons('compare', cmp) + '</div>' + "\n"
+            }
+
             if( this.darptags.length > 1 ){
                 html += '<div class=graphdarp>'
 
@@ -190,6 +208,7 @@ function Graph(el, obj, which, darp, ctls, width){
         // update range selector
         $('#' + ctlid + ' input[name=range][value='+this.select.which+']').attr('checked', 'checked')
         $('#' + ctlid + ' input[name=extra][value=""]').attr('checked', 'checked')
+        $('#' + ctlid + ' input[name=compare][value="0"]').attr('checked', 'checked')
 
         // resize labels so they line up nicely
         var maxw = Math.max.apply(Math, $('#' + ctlid + ' .graphlabel').map(function(){ return $(this).width(); }).get());
@@ -202,6 +221,7 @@ function Graph(el, obj, which, darp, ctls, width){
         var g = this
         $( '#' + ctlid + ' input[name=range]').change( function(){ g.controlChanged(this) })
         $( '#' + ctlid + ' input[name=extra]').change( function(){ g.controlChanged(this) })
+        $( '#' + ctlid + ' input[name=compare]').change( function(){ g.controlChanged(this) })
         $( '#' + ctlid + ' .graphlabel').click( function(){ g.labelClicked(this) })
 
         this.updateSupplementDpy()
@@ -221,9 +241,11 @@ function Graph(el, obj, which, darp, ctls, width){
     p.controlChanged = function(el){
         var which = $('#' + this.grctlid + ' input[name=range]:checked').val()
         var extra = $('#' + this.grctlid + ' input[name=extra]:checked').val()
+        var cmp   = $('#' + this.grctlid + ' input[name=compare]:checked').val()
         argus.log('control ' + which)
         this.select.which = which
         this.select.supplement = extra
+        this.select.offset = parseInt(cmp) || 0
         this.updateSelection()
     }
     p.labelClicked = function(el){
@@ -288,8 +310,10 @@ function Graph(el, obj, which, darp, ctls, width){
         $('#' + this.grctlid + ' .graphsupplement').show()
     }
 
-    p.Id = function(which, darp, obj){
-        return which + " " + darp + " " + obj
+    p.Id = function(which, darp, obj, offset){
+        var id = which + " " + darp + " " + obj
+        if( offset ) id += " @" + offset
+        return id
     }
 
     // Nor long the sun his daily course withheld,
@@ -342,33 +366,55 @@ function Graph(el, obj, which, darp, ctls, width){
                 // fetch it, if we don't already have it
                 id = this.Id(which, darp, obj)
                 if( ! this.datasets[id] )
-                    this.fetchData(which, darp, obj)
+                    this.fetchData(which, darp, obj, 0)
                 this.selected[ id ] = 1
+
+                // and the comparison overlay
+                if( this.select.offset ){
+                    id = this.Id(which, darp, obj, this.select.offset)
+                    if( ! this.datasets[id] )
+                        this.fetchData(which, darp, obj, this.select.offset)
+                    this.selected[ id ] = 1
+                }
             }
         }
         this.updateSupplementDpy()
         this.maybeBuild()
     }
 
-    p.fetchData = function(which, darp, obj){
+    p.fetchData = function(which, darp, obj, offset){
         var g = this
 
-        this.pending[ this.Id(which, darp, obj) ] = 1
+        this.pending[ this.Id(which, darp, obj, offset) ] = 1
 
         // fetch the graph data
         $.ajax({
             dataType: "json",
             url: '/api/graphd',
-            data: {obj: obj, which: which, tag: darp, width: this.width },
-            success: function(r){ g.gotData(which, darp, obj, r)},
-            error:   function(r){ g.gotFail(which, darp, obj, r) }
+            data: {obj: obj, which: which, darp: darp, width: this.width, offset: offset },
+            success: function(r){ g.gotData(which, darp, obj, offset, r)},
+            error:   function(r){ g.gotFail(which, darp, obj, offset, r) }
         });
     }
 
-    p.gotData = function(which, darp, obj, r){
-        var id = this.Id(which, darp, obj)
+    p.gotData = function(which, darp, obj, offset, r){
+        var id = this.Id(which, darp, obj, offset)
         delete this.pending[id]
-        this.datasets[id] = { data: r.data, which: which, darp: darp, obj: obj }
+        this.datasets[id] = { data: r.data, which: which, darp: darp, obj: obj, offset: offset }
+
+        if( offset ){
+            // the past, shifted to line up with now. no status colors or supplements
+            this.cs.Add( r.data, {
+                id:	id,
+                color:	this.objs[obj].color,
+                smooth:	1,
+                type:	'line',
+                thick:	1,
+                dashed:	[4, 3]
+            })
+            this.maybeBuild()
+            return
+        }
 
         // add to chart
         this.cs.Add( r.data, {
@@ -404,8 +450,8 @@ function Graph(el, obj, which, darp, ctls, width){
 
         this.maybeBuild()
     }
-    p.gotFail = function(which, darp, obj, r){
-        var id = this.Id(which, darp, obj)
+    p.gotFail = function(which, darp, obj, offset, r){
+        var id = this.Id(which, darp, obj, offset)
         delete this.pending[id]
 
         this.maybeBuild()
@@ -423,11 +469,17 @@ function Graph(el, obj, which, darp, ctls, width){
         this.cs.HideAll()
 
         var sel = Object.keys(this.selected)
+        var nbase = 0
+        for(i=0; i<sel.length; i++){
+            if( ! this.datasets[ sel[i] ] || ! this.datasets[ sel[i] ].offset ) nbase ++
+        }
+
         for(i=0; i<sel.length; i++){
             argus.log("selected: " + sel[i] )
             this.cs.Show( sel[i] )
 
-            if( sel.length == 1 && this.select.supplement)
+            // supplements only on the present, not the overlay
+            if( nbase == 1 && this.select.supplement && this.datasets[ sel[i] ] && ! this.datasets[ sel[i] ].offset )
                 this.cs.Show( this.select.supplement + ' ' + sel[i] )
         }
 
@@ -460,7 +512,7 @@ function Graph(el, obj, which, darp, ctls, width){
                 $.ajax({
                     dataType: "json",
                     url: '/api/graphd',
-                    data: {obj: set.obj, darp: set.darp, which: set.which, since: maxt, width: g.width},
+                    data: {obj: set.obj, darp: set.darp, which: set.which, since: maxt, width: g.width, offset: set.offset},
                     success: function(r){ g.gotUpdate(id, r)},
                     error:   function(r){ argus.log("update graph failed") }
                 });
@@ -77,6 +77,52 @@ func (c *Conn) GetMap(method string, args map[string]string, timeout time.Durati
 
 func (c *Conn) Get(method string, args map[string]string, timeout time.Duration) (*Response, error) {
 
+	resp, err := c.request(method, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// get content lines
+	for {
+		line, _, _ := c.bfd.ReadLine()
+		if len(line) == 0 {
+			break
+		}
+		resp.Lines = append(resp.Lines, string(line))
+	}
+
+	return resp, nil
+}
+
+// Stream passes content lines to fnc as they arrive, until fnc returns false,
+// the response ends, or the connection closes. for long running requests (tail)
+func (c *Conn) Stream(method string, args map[string]string, fnc func(string) bool) (*Response, error) {
+
+	resp, err := c.request(method, args)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		line, _, err := c.bfd.ReadLine()
+		if err != nil || len(line) == 0 {
+			break
+		}
+		if resp.Code != 200 {
+			resp.Lines = append(resp.Lines, string(line))
+			continue
+		}
+		if !fnc(string(line)) {
+			break
+		}
+	}
+
+	return resp, nil
+}
+
+// send the request, read + parse the response line
+func (c *Conn) request(method string, args map[string]string) (*Response, error) {
+
 	// send request line
 	fmt.Fprintf(c.C, "GET %s %s\n", method, PROTOCOL)
 
@@ -103,15 +149,6 @@ func (c *Conn) Get(method string, args map[string]string, timeout time.Duration)
 		Msg:  flds[2],
 	}
 
-	// get content lines
-	for {
-		line, _, _ := c.bfd.ReadLine()
-		if len(line) == 0 {
-			break
-		}
-		resp.Lines = append(resp.Lines, string(line))
-	}
-
 	return resp, nil
 }
 
@@ -6,6 +6,7 @@
 package argus
 
 import (
+	"math"
 	"strings"
 )
 
@@ -17,14 +18,15 @@ const (
 	GRAV_VOTE Gravity = 2
 	GRAV_IETF Gravity = 3
 	GRAV_SELF Gravity = 4
+	GRAV_PCT  Gravity = 5
 )
 
 var gravityname = []string{
-	"up", "down", "vote", "ietf", "self",
+	"up", "down", "vote", "ietf", "self", "percent",
 }
 
 func (g Gravity) String() string {
-	if g < GRAV_UP || g > GRAV_SELF {
+	if g < GRAV_UP || g > GRAV_PCT {
 		return "invalid"
 	}
 	return gravityname[int(g)]
@@ -43,7 +45,84 @@ func GravityValue(name string) Gravity {
 		return GRAV_IETF
 	case "self":
 		return GRAV_SELF
+	case "percent", "percentile", "pct":
+		return GRAV_PCT
 	}
 
 	return GRAV_UP
 }
+
+// percent gravity thresholds, per severity. NaN if not set
+// the unadorned value (UNKNOWN) applies to CRITICAL
+type GravityPct struct {
+	Down []float64 // more than this percent (by weight) at this severity or worse
+	Up   []float64 // fewer than this many (by weight) up
+}
+
+func (g *GravityPct) IsSet() bool {
+
+	for sev := UNKNOWN; sev <= CRITICAL; sev++ {
+		if !math.IsNaN(g.threshold(g.Down, sev)) || !math.IsNaN(g.threshold(g.Up, sev)) {
+			return true
+		}
+	}
+	return false
+}
+
+// weight is indexed by status
+func (g *GravityPct) Status(max Status, weight []float64) Status {
+
+	if max <= CRITICAL {
+		return g.status(weight, 0, 0)
+	}
+
+	// overridden + depends are neither up nor down.
+	// if it would be worse without them, say so
+	other := weight[int(OVERRIDE)] + weight[int(DEPENDS)]
+	st := g.status(weight, other, 0)
+	if g.status(weight, 0, other) <= st {
+		return st
+	}
+	if weight[int(OVERRIDE)] > 0 {
+		return OVERRIDE
+	}
+	return DEPENDS
+}
+
+func (g *GravityPct) status(weight []float64, xup float64, xdn float64) Status {
+
+	up := weight[int(CLEAR)] + xup
+	tot := up + xdn
+	for sev := WARNING; sev <= CRITICAL; sev++ {
+		tot += weight[int(sev)]
+	}
+
+	if tot == 0 {
+		return UNKNOWN
+	}
+
+	down := xdn
+	for sev := CRITICAL; sev >= WARNING; sev-- {
+		down += weight[int(sev)]
+
+		if pct := g.threshold(g.Down, sev); !math.IsNaN(pct) && down*100 > pct*tot {
+			return sev
+		}
+		if min := g.threshold(g.Up, sev); !math.IsNaN(min) && up < min {
+			return sev
+		}
+	}
+
+	return CLEAR
+}
+
+func (g *GravityPct) threshold(v []float64, sev Status) float64 {
+
+	if int(sev) >= len(v) {
+		return math.NaN()
+	}
+	if sev == CRITICAL && math.IsNaN(v[int(sev)]) {
+		return v[int(UNKNOWN)]
+	}
+	return v[int(sev)]
+}
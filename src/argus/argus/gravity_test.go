@@ -0,0 +1,46 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 16:10 (EDT)
+// Function:
+
+package argus
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func gravityExpect(t *testing.T, g *GravityPct, max Status, weight []float64, exp Status) {
+
+	st := g.Status(max, weight)
+	if st != exp {
+		fmt.Printf("%v -> %s != %s\n", weight, st, exp)
+		t.Fail()
+	}
+}
+
+func TestGravityPct(t *testing.T) {
+
+	nan := math.NaN()
+	g := &GravityPct{
+		Down: []float64{nan, nan, 10, nan, nan, 30},
+		Up:   []float64{3, nan, nan, nan, nan, nan}, // unadorned => critical
+	}
+
+	//                        unk clear warn minor major crit ovrd deps
+	gravityExpect(t, g, CRITICAL, []float64{0, 40, 0, 0, 0, 0, 0, 0}, CLEAR)
+	gravityExpect(t, g, CRITICAL, []float64{0, 36, 4, 0, 0, 0, 0, 0}, CLEAR)
+	gravityExpect(t, g, CRITICAL, []float64{0, 35, 3, 0, 0, 2, 0, 0}, WARNING)
+	gravityExpect(t, g, CRITICAL, []float64{0, 27, 0, 0, 13, 0, 0, 0}, WARNING)
+	gravityExpect(t, g, CRITICAL, []float64{0, 27, 0, 0, 0, 13, 0, 0}, CRITICAL)
+	gravityExpect(t, g, CRITICAL, []float64{0, 2, 0, 0, 0, 0, 0, 0}, CRITICAL)
+	gravityExpect(t, g, CRITICAL, []float64{5, 0, 0, 0, 0, 0, 0, 0}, UNKNOWN)
+
+	// weighted: primary (weight 10) down, 2 replicas up
+	gravityExpect(t, g, CRITICAL, []float64{0, 2, 0, 0, 0, 10, 0, 0}, CRITICAL)
+
+	// the down ones are overridden
+	gravityExpect(t, g, MAXSTATUS, []float64{0, 27, 0, 0, 0, 0, 13, 0}, OVERRIDE)
+	gravityExpect(t, g, MAXSTATUS, []float64{0, 38, 0, 0, 0, 0, 2, 0}, CLEAR)
+}
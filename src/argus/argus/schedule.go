@@ -27,8 +27,11 @@ func ScheduleAlways(val string) *Schedule {
 }
 
 func (s *Schedule) ResultNow(def string) string {
+	return s.ResultAt(time.Now(), def)
+}
+
+func (s *Schedule) ResultAt(now time.Time, def string) string {
 
-	now := time.Now()
 	dow := int(now.Weekday())
 	hrs, min, _ := now.Clock()
 	tim := hrs*100 + min
@@ -56,6 +59,10 @@ func (s *Schedule) PermitNow(def string) bool {
 	return CheckBool(s.ResultNow(def))
 }
 
+func (s *Schedule) PermitAt(now time.Time, def string) bool {
+	return CheckBool(s.ResultAt(now, def))
+}
+
 func (s *Schedule) Append(dow int, start int, end int, value string) {
 
 	s.Sched = append(s.Sched, ScheduleItem{dow, start, end, value})
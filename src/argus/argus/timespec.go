@@ -8,6 +8,7 @@ package argus
 import (
 	"fmt"
 	"strconv"
+	"time"
 )
 
 // convert friendly time specifiers to seconds
@@ -80,3 +81,42 @@ func Elapsed(e int64) string {
 	}
 	return fmt.Sprintf("%0.2d:%0.2d:%0.2d", hrs, min, sec)
 }
+
+// unix time, a date, a time today, or a timespec ago (eg. 7d)
+func ParseWhen(v string, now int64, def int64) (int64, error) {
+
+	if v == "" {
+		return def, nil
+	}
+
+	if t, err := strconv.ParseInt(v, 10, 64); err == nil && t > 1000000000 {
+		return t, nil
+	}
+
+	for _, f := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(f, v, time.Local); err == nil {
+			return t.Unix(), nil
+		}
+	}
+
+	// time of day, today
+	for _, f := range []string{"15:04:05", "15:04"} {
+		if t, err := time.ParseInLocation(f, v, time.Local); err == nil {
+			base := time.Now()
+			if now != 0 {
+				base = time.Unix(now, 0)
+			}
+			y, m, d := base.Date()
+			return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, time.Local).Unix(), nil
+		}
+	}
+
+	if now != 0 {
+		dt, err := Timespec(v, 1)
+		if err == nil {
+			return now - dt, nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid time '%s'", v)
+}
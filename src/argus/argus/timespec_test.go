@@ -8,6 +8,7 @@ package argus
 import (
 	"fmt"
 	"testing"
+	"time"
 )
 
 func timespecExpect(t *testing.T, a string, b int64) {
@@ -29,3 +30,34 @@ func TestTimeSpec(t *testing.T) {
 	timespecExpect(t, "10h10m5", 36605)
 	timespecExpect(t, "1M", 30*24*3600)
 }
+
+func TestParseWhen(t *testing.T) {
+
+	now := int64(1800000000)
+
+	tests := map[string]int64{
+		"":           0,
+		"1700000000": 1700000000,
+		"1d":         now - 86400,
+		"90m":        now - 5400,
+	}
+
+	for in, exp := range tests {
+		got, err := ParseWhen(in, now, 0)
+		if err != nil || got != exp {
+			fmt.Printf("ParseWhen %s => %d %v, expected %d\n", in, got, err, exp)
+			t.Fail()
+		}
+	}
+
+	y, m, d := time.Unix(now, 0).Date()
+	if got, _ := ParseWhen("02:30", now, 0); got != time.Date(y, m, d, 2, 30, 0, 0, time.Local).Unix() {
+		fmt.Printf("ParseWhen 02:30 => %d\n", got)
+		t.Fail()
+	}
+
+	if _, err := ParseWhen("yesterday", now, 0); err == nil {
+		fmt.Printf("expected error\n")
+		t.Fail()
+	}
+}
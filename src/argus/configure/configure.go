@@ -61,10 +61,10 @@ func (cf *CF) InitFromConfig(dst interface{}, section string, prefix string) {
 
 		conv := tags.Get("cfconv")
 
-		// currently the only tags known are cfconv:{dotsev,timespec}
+		// currently the only tags known are cfconv:{dotsev,timespec,"dotsev,timespec"}
 
-		if conv == "dotsev" && kind == reflect.Array {
-			cf.initDotSev(name, v)
+		if strings.HasPrefix(conv, "dotsev") && kind == reflect.Array {
+			cf.initDotSev(name, v, strings.TrimPrefix(strings.TrimPrefix(conv, "dotsev"), ","))
 			continue
 		}
 
@@ -73,7 +73,7 @@ func (cf *CF) InitFromConfig(dst interface{}, section string, prefix string) {
 }
 
 // configure thing.severity -> thing[sev]
-func (cf *CF) initDotSev(name string, v reflect.Value) {
+func (cf *CF) initDotSev(name string, v reflect.Value, conv string) {
 
 	dl.Debug("dot sev %s", name)
 	for sev := argus.CLEAR; sev <= argus.CRITICAL; sev++ {
@@ -84,10 +84,10 @@ func (cf *CF) initDotSev(name string, v reflect.Value) {
 
 		fname := name + "." + sev.String()
 
-		cf.setValue(v.Index(int(sev)), "", fname)
+		cf.setValue(v.Index(int(sev)), conv, fname)
 	}
 
-	cf.setValue(v.Index(int(argus.UNKNOWN)), "", name)
+	cf.setValue(v.Index(int(argus.UNKNOWN)), conv, name)
 }
 
 func (cf *CF) setValue(v reflect.Value, conv string, name string) {
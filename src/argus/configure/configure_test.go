@@ -22,6 +22,7 @@ type Conf struct {
 	Kf [8]float64 `cfconv:"dotsev"`
 	Kg *argus.Schedule
 	Kh [8]*argus.Schedule `cfconv:"dotsev"`
+	Ki [8]int64           `cfconv:"dotsev,timespec"`
 }
 
 func TestConfigure(t *testing.T) {
@@ -38,6 +39,8 @@ func TestConfigure(t *testing.T) {
 			"kh":       &CFV{Value: &argus.Schedule{}},
 			"kh.major": &CFV{Value: &argus.Schedule{}},
 			"kh.minor": &CFV{Value: "yes"},
+			"ki":       &CFV{Value: "5m"},
+			"ki.minor": &CFV{Value: "30"},
 		},
 	}
 
@@ -76,5 +79,10 @@ func TestConfigure(t *testing.T) {
 		t.Fail()
 	}
 
+	if cf.Ki[0] != 300 || cf.Ki[3] != 30 {
+		fmt.Printf("I %#v\n", cf.Ki)
+		t.Fail()
+	}
+
 	fmt.Printf("%#v\n", cf.Kh)
 }
@@ -10,6 +10,7 @@ import (
 	//"github.com/jaw0/acdiag"
 	"argus.domain/argus/alias"
 	"argus.domain/argus/darp"
+	"argus.domain/argus/graph"
 	"argus.domain/argus/group"
 	"argus.domain/argus/monel"
 	"argus.domain/argus/monitor/agent"
@@ -53,6 +54,24 @@ func Make(cf *configure.CF, parent *monel.M) *monel.M {
 			cf.Error("%v", err)
 		}
 
+	case "rule":
+		err := notify.NewRule(cf)
+		if err != nil {
+			cf.Error("%v", err)
+		}
+
+	case "ticket":
+		err := notify.NewTicket(cf)
+		if err != nil {
+			cf.Error("%v", err)
+		}
+
+	case "grapher":
+		err := graph.NewGrapher(cf)
+		if err != nil {
+			cf.Error("%v", err)
+		}
+
 	case "darp":
 		err := darp.New(cf)
 		if err != nil {
@@ -10,7 +10,9 @@ import (
 
 	"argus.domain/argus/argus"
 	"argus.domain/argus/configure"
+	"argus.domain/argus/history"
 	"github.com/jaw0/acdiag"
+	"argus.domain/argus/maint"
 	"argus.domain/argus/monel"
 	"argus.domain/argus/notify"
 	"argus.domain/argus/service"
@@ -28,12 +30,15 @@ type readConf struct {
 var dl = diag.Logger("dozer")
 
 var confconf = map[string]*readConf{
-	"top":     &readConf{narg: 1, level: 2, permit: map[string]bool{"method": true, "snmpoid": true, "group": true, "host": true, "darp": true, "agent": true}},
+	"top":     &readConf{narg: 1, level: 2, permit: map[string]bool{"method": true, "rule": true, "ticket": true, "grapher": true, "snmpoid": true, "group": true, "host": true, "darp": true, "agent": true}},
 	"group":   &readConf{narg: 1, level: 2, permit: map[string]bool{"group": true, "host": true, "service": true, "alias": true}},
 	"host":    &readConf{narg: 1, level: 2, permit: map[string]bool{"group": true, "host": true, "service": true, "alias": true}},
 	"alias":   &readConf{narg: 2, onel: true, level: 2},
 	"service": &readConf{narg: 1, onel: true, level: 2},
 	"method":  &readConf{narg: 1, onel: true, level: 1, isInfo: true},
+	"rule":    &readConf{narg: 1, level: 1, isInfo: true},
+	"ticket":  &readConf{narg: 1, level: 1, isInfo: true},
+	"grapher": &readConf{narg: 1, level: 1, isInfo: true},
 	"snmpoid": &readConf{onel: true, level: 1, isInfo: true},
 	"darp":    &readConf{narg: 1, level: 1, isInfo: true},
 	"agent":   &readConf{narg: 2, level: 1, isInfo: true},
@@ -53,6 +58,10 @@ func ReadConfig(file string) []string {
 	dl.Debug("done %v", f)
 
 	notify.Configure(cf)
+	maint.Configure(cf)
+	monel.ConfigureIncidents(cf)
+	monel.ConfigureSLA(cf)
+	history.Configure(cf)
 	web.Configure(cf)
 	service.GraphConfig(cf)
 	// other.Configure(cf)
@@ -17,6 +17,7 @@ import (
 	"argus.domain/argus/clock"
 	"argus.domain/argus/config"
 	"argus.domain/argus/configure"
+	"argus.domain/argus/history"
 	"github.com/jaw0/acdiag"
 	"argus.domain/argus/resolv"
 	"argus.domain/argus/sec"
@@ -199,6 +200,9 @@ func (x *DarpServerer) Connected(name string) {
 	if s == nil {
 		return
 	}
+	if !s.IsUp {
+		history.Add(&history.Event{Type: "darp", Tags: name, Msg: "peer " + name + " connected"})
+	}
 	s.IsUp = true
 	s.Lastt = now
 }
@@ -213,6 +217,9 @@ func (x *DarpServerer) Disco(name string) {
 		return
 	}
 
+	if s.IsUp {
+		history.Add(&history.Event{Type: "darp", Tags: name, Msg: "peer " + name + " disconnected"})
+	}
 	s.IsUp = false
 }
 
@@ -9,7 +9,8 @@ import (
 	"argus.domain/argus/argus"
 )
 
-func AggrStatus(gravity argus.Gravity, mystatus argus.Status, statuses map[string]argus.Status) argus.Status {
+// pct is only used by gravity percent
+func AggrStatus(gravity argus.Gravity, pct *argus.GravityPct, mystatus argus.Status, statuses map[string]argus.Status) argus.Status {
 
 	dl.Debug("grav %s; %s; %v", gravity, mystatus, statuses)
 	if gravity == argus.GRAV_SELF || !iHaveSlaves || !IsEnabled {
@@ -18,10 +19,10 @@ func AggrStatus(gravity argus.Gravity, mystatus argus.Status, statuses map[strin
 
 	darps := GetStatuses()
 
-	return CalcAggrStatus(gravity, mystatus, statuses, darps)
+	return CalcAggrStatus(gravity, pct, mystatus, statuses, darps)
 }
 
-func CalcAggrStatus(gravity argus.Gravity, mystatus argus.Status,
+func CalcAggrStatus(gravity argus.Gravity, pct *argus.GravityPct, mystatus argus.Status,
 	statuses map[string]argus.Status, darps map[string]bool) argus.Status {
 
 	var count [argus.MAXSTATUS + 1]int
@@ -73,6 +74,13 @@ func CalcAggrStatus(gravity argus.Gravity, mystatus argus.Status,
 			}
 		}
 		return argus.CLEAR
+
+	case argus.GRAV_PCT:
+		var weight [argus.MAXSTATUS + 1]float64
+		for i, c := range count {
+			weight[i] = float64(c)
+		}
+		return pct.Status(argus.CRITICAL, weight[:])
 	}
 	return argus.UNKNOWN
 }
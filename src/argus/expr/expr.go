@@ -6,7 +6,6 @@
 package expr
 
 import (
-	"errors"
 	"fmt"
 	"math"
 	"math/rand"
@@ -20,44 +19,102 @@ import (
 
 type OP struct {
 	prec int
+	narg int
 	rry  bool // true => all services must be ready
 	fop  func(*exprStack) (float64, bool)
 	fagg func(*exprStack, bool) (float64, string, bool)
+	fraw func(*exprStack) (string, bool) // operates on strings
 }
 
 var ops = map[string]OP{
-	"time":      {50, true, fop_time, nil}, // unix time, seconds
-	"rand":      {50, true, fop_rand, nil}, // [0..1)
-	"SUM":       {20, true, nil, fop_sum},  // group aggregate ops
-	"AVE":       {20, true, nil, fop_ave},  //   SUM(Top:Foo:Bar)
-	"AVG":       {20, true, nil, fop_ave},  // test will wait until all services are ready
-	"MIN":       {20, true, nil, fop_min},
-	"MAX":       {20, true, nil, fop_max},
-	"COUNT":     {20, true, nil, fop_count},
-	"NSUM":      {20, false, nil, fop_sum}, // group aggregate ops
-	"NAVE":      {20, false, nil, fop_ave}, // tests will run ignoring any services not ready
-	"NAVG":      {20, false, nil, fop_ave},
-	"NMIN":      {20, false, nil, fop_min},
-	"NMAX":      {20, false, nil, fop_max},
-	"NCOUNT":    {20, false, nil, fop_count},
-	"NUP":       {20, false, nil, fop_up},   // count of services that are up
-	"NDOWN":     {20, false, nil, fop_down}, // ... or down
-	"NOVERRIDE": {20, false, nil, fop_over}, // ... or overridden
-	"ceil":      {20, true, fop_ceil, nil},  // standard math functions
-	"floor":     {20, true, fop_floor, nil},
-	"abs":       {20, true, fop_abs, nil},
-	"sin":       {20, true, fop_sin, nil},
-	"tan":       {20, true, fop_sin, nil},
-	"cos":       {20, true, fop_cos, nil},
-	"log":       {20, true, fop_log, nil}, // natural log
-	"exp":       {20, true, fop_exp, nil}, // e ^ x
-	"sqrt":      {20, true, fop_sqrt, nil},
-	"^":         {16, true, fop_pow, nil}, // basic arithmetic
-	"*":         {15, true, fop_mul, nil},
-	"/":         {14, true, fop_div, nil},
-	"%":         {13, true, fop_mod, nil},
-	"+":         {12, true, fop_add, nil},
-	"-":         {12, true, fop_sub, nil},
+	"time":      {50, 0, true, fop_time, nil, nil},                  // unix time, seconds
+	"rand":      {50, 0, true, fop_rand, nil, nil},                  // [0..1)
+	"UNKNOWN":   {50, 0, true, fop_status(argus.UNKNOWN), nil, nil}, // status values
+	"CLEAR":     {50, 0, true, fop_status(argus.CLEAR), nil, nil},
+	"WARNING":   {50, 0, true, fop_status(argus.WARNING), nil, nil},
+	"MINOR":     {50, 0, true, fop_status(argus.MINOR), nil, nil},
+	"MAJOR":     {50, 0, true, fop_status(argus.MAJOR), nil, nil},
+	"CRITICAL":  {50, 0, true, fop_status(argus.CRITICAL), nil, nil},
+	"OVERRIDE":  {50, 0, true, fop_status(argus.OVERRIDE), nil, nil},
+	"DEPENDS":   {50, 0, true, fop_status(argus.DEPENDS), nil, nil},
+	"SUM":       {20, 1, true, nil, fop_sum, nil}, // group aggregate ops
+	"AVE":       {20, 1, true, nil, fop_ave, nil}, //   SUM(Top:Foo:Bar)
+	"AVG":       {20, 1, true, nil, fop_ave, nil}, // test will wait until all services are ready
+	"MIN":       {20, 1, true, nil, fop_min, nil},
+	"MAX":       {20, 1, true, nil, fop_max, nil},
+	"COUNT":     {20, 1, true, nil, fop_count, nil},
+	"NSUM":      {20, 1, false, nil, fop_sum, nil}, // group aggregate ops
+	"NAVE":      {20, 1, false, nil, fop_ave, nil}, // tests will run ignoring any services not ready
+	"NAVG":      {20, 1, false, nil, fop_ave, nil},
+	"NMIN":      {20, 1, false, nil, fop_min, nil},
+	"NMAX":      {20, 1, false, nil, fop_max, nil},
+	"NCOUNT":    {20, 1, false, nil, fop_count, nil},
+	"NUP":       {20, 1, false, nil, fop_up, nil},        // count of services that are up
+	"NDOWN":     {20, 1, false, nil, fop_down, nil},      // ... or down
+	"NOVERRIDE": {20, 1, false, nil, fop_over, nil},      // ... or overridden
+	"STATUS":    {20, 1, false, nil, fop_objstat, nil},   // status of an object
+	"OVSTATUS":  {20, 1, false, nil, fop_objovstat, nil}, // override status of an object
+	"ceil":      {20, 1, true, fop_ceil, nil, nil},       // standard math functions
+	"floor":     {20, 1, true, fop_floor, nil, nil},
+	"abs":       {20, 1, true, fop_abs, nil, nil},
+	"sin":       {20, 1, true, fop_sin, nil, nil},
+	"tan":       {20, 1, true, fop_sin, nil, nil},
+	"cos":       {20, 1, true, fop_cos, nil, nil},
+	"log":       {20, 1, true, fop_log, nil, nil}, // natural log
+	"exp":       {20, 1, true, fop_exp, nil, nil}, // e ^ x
+	"sqrt":      {20, 1, true, fop_sqrt, nil, nil},
+	"!":         {20, 1, true, fop_not, nil, nil}, // logical not
+	"^":         {16, 2, true, fop_pow, nil, nil}, // basic arithmetic
+	"*":         {15, 2, true, fop_mul, nil, nil},
+	"/":         {14, 2, true, fop_div, nil, nil},
+	"%":         {13, 2, true, fop_mod, nil, nil},
+	"+":         {12, 2, true, fop_add, nil, nil},
+	"-":         {12, 2, true, fop_sub, nil, nil},
+	"<":         {10, 2, true, fop_lt, nil, nil}, // comparison => 1 or 0
+	"<=":        {10, 2, true, fop_le, nil, nil},
+	">":         {10, 2, true, fop_gt, nil, nil},
+	">=":        {10, 2, true, fop_ge, nil, nil},
+	"==":        {9, 2, true, nil, nil, fop_eq}, // numeric if both are numbers, otherwise string
+	"!=":        {9, 2, true, nil, nil, fop_ne},
+	"eq":        {9, 2, true, nil, nil, fop_streq}, // string
+	"ne":        {9, 2, true, nil, nil, fop_strne},
+	"&&":        {6, 2, true, fop_and, nil, nil}, // logical
+	"||":        {5, 2, true, fop_or, nil, nil},
+	"?":         {3, 2, true, nil, nil, fop_cond}, // c ? a : b
+	":":         {2, 2, true, nil, nil, fop_else}, //   NB - the : needs spaces around it, Top:Foo is a name
+}
+
+// functions, constants, unary ops - everything but the binary ops
+func (o OP) prefix() bool {
+	return o.prec >= 20
+}
+
+// characters that are operators, and end a word
+const opchars = "()+-*/%^<>=!&|?,"
+
+// the false side of c ? a : b
+const noValue = "\x00"
+
+type token struct {
+	t   string
+	pos int
+}
+
+type ParseError struct {
+	Pos int // position of the offending token, starting at 1
+	Tok string
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	if e.Tok == "" {
+		return fmt.Sprintf("syntax error at position %d: %s", e.Pos, e.Msg)
+	}
+	return fmt.Sprintf("syntax error at position %d, near '%s': %s", e.Pos, e.Tok, e.Msg)
+}
+
+func parseError(t token, msg string) error {
+	return &ParseError{Pos: t.pos + 1, Tok: t.t, Msg: msg}
 }
 
 // calculate value of expression
@@ -87,6 +144,30 @@ func RunExprF(pt []string, vars map[string]string) (float64, string, error) {
 	return v, "", err
 }
 
+// run pre-compiled expr - return a status
+// the expr may produce a number (eg. 2 or WARNING) or a name ('warning')
+func RunExprS(pt []string, vars map[string]string) (argus.Status, string, error) {
+
+	res, nrdy := RunExpr(pt, vars)
+	if nrdy != "" {
+		return argus.UNKNOWN, nrdy, nil
+	}
+
+	if v, err := strconv.ParseFloat(res, 64); err == nil {
+		st := argus.Status(int(v))
+		if st < argus.UNKNOWN || st > argus.CRITICAL {
+			return argus.UNKNOWN, "", fmt.Errorf("invalid status %v", v)
+		}
+		return st, "", nil
+	}
+
+	st := argus.StatusValue(res)
+	if st == argus.UNKNOWN && strings.ToLower(res) != "unknown" {
+		return argus.UNKNOWN, "", fmt.Errorf("invalid status '%s'", res)
+	}
+	return st, "", nil
+}
+
 func Parse(expr string) ([]string, map[string]bool, error) {
 
 	t, err := tokenize(expr)
@@ -96,77 +177,123 @@ func Parse(expr string) ([]string, map[string]bool, error) {
 	return parse(t)
 }
 
-func tokenize(expr string) ([]string, error) {
+func tokenize(expr string) ([]token, error) {
+
+	var tok []token
+	i := 0
 
-	var tok []string
+	for i < len(expr) {
+		c := expr[i]
 
-	expr = strings.TrimSpace(expr)
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
 
-	for expr != "" {
-		if expr[0] == '{' {
+		case c == '{':
 			// {Top:Foo+Bar}
-			e := strings.IndexByte(expr, '}')
+			e := strings.IndexByte(expr[i:], '}')
 			if e == -1 {
-				// unbalanced {}
-				return nil, errors.New("syntax error: unbalanced {}")
+				return nil, parseError(token{"{", i}, "unbalanced {}")
 			}
-			tok = append(tok, strings.TrimSpace(expr[1:e]))
-			expr = strings.TrimSpace(expr[e+1:])
-			continue
-		}
-		if expr[0] == '"' || expr[0] == '\'' {
+			tok = append(tok, token{strings.TrimSpace(expr[i+1 : i+e]), i})
+			i += e + 1
+
+		case c == '"' || c == '\'':
 			// "Top:Foo+Bar"
-			e := strings.IndexByte(expr[1:], expr[0])
+			e := strings.IndexByte(expr[i+1:], c)
 			if e == -1 {
-				return nil, errors.New("syntax error: unbalanced quotes")
+				return nil, parseError(token{expr[i : i+1], i}, "unbalanced quotes")
 			}
-			tok = append(tok, strings.TrimSpace(expr[1:e+1]))
-			expr = strings.TrimSpace(expr[e+2:])
-			continue
-		}
-		e := strings.IndexAny(expr, "()+-*/%^ \t")
-		if e == -1 {
-			// slurp up remaining
-			tok = append(tok, strings.TrimSpace(expr))
-			break
-		}
-		if e == 0 {
-			// single char op
-			tok = append(tok, expr[0:1])
-			expr = strings.TrimSpace(expr[e+1:])
-			continue
-		}
+			tok = append(tok, token{strings.TrimSpace(expr[i+1 : i+1+e]), i})
+			i += e + 2
+
+		case strings.IndexByte(opchars, c) != -1:
+			// two char op?
+			if i+1 < len(expr) {
+				if _, ok := ops[expr[i:i+2]]; ok {
+					tok = append(tok, token{expr[i : i+2], i})
+					i += 2
+					continue
+				}
+			}
+			if _, ok := ops[expr[i:i+1]]; !ok && c != '(' && c != ')' && c != ',' {
+				return nil, parseError(token{expr[i : i+1], i}, "unknown operator")
+			}
+			tok = append(tok, token{expr[i : i+1], i})
+			i++
 
-		tok = append(tok, strings.TrimSpace(expr[:e])) // word
-		if expr[e] != ' ' && expr[e] != '\t' {
-			tok = append(tok, expr[e:e+1]) // op
+		default:
+			// word
+			e := strings.IndexAny(expr[i:], opchars+" \t\n")
+			if e == -1 {
+				e = len(expr) - i
+			}
+			tok = append(tok, token{expr[i : i+e], i})
+			i += e
 		}
-		expr = strings.TrimSpace(expr[e+1:])
 	}
 
 	return tok, nil
 }
 
 // Dijkstra
-func parse(tok []string) ([]string, map[string]bool, error) {
+func parse(tok []token) ([]string, map[string]bool, error) {
 
-	var oq []string
-	var op []string
+	var oq []token
+	var op []token
+	var last token
+	var argc []int // number of args, per (
 	objs := make(map[string]bool)
+	expect := true // an operand, not an operator
+	zarg := false  // previous was a zero arg function
+
+	if len(tok) == 0 {
+		return nil, nil, parseError(last, "empty expression")
+	}
 
 	//while there are tokens to be read:
 	//	read a token.
 	for len(tok) != 0 {
 		t := tok[0]
 		tok = tok[1:]
-
-		if t == "(" {
+		last = t
+
+		if t.t == "(" {
+			if !expect {
+				if zarg && len(tok) != 0 && tok[0].t == ")" {
+					// time()
+					tok = tok[1:]
+					continue
+				}
+				return nil, nil, parseError(t, "missing operator")
+			}
 			// if the token is a left bracket (i.e. "("), then:
 			//   push it onto the operator stack.
 			op = append(op, t)
+			argc = append(argc, 1)
+			continue
+		}
+		zarg = false
+
+		if t.t == "," {
+			// function argument separator: FUNC(a, b)
+			// pop operators until the left bracket
+			if expect {
+				return nil, nil, parseError(t, "missing operand")
+			}
+			for len(op) != 0 && op[len(op)-1].t != "(" {
+				oq = append(oq, op[len(op)-1])
+				op = op[:len(op)-1]
+			}
+			if len(op) == 0 {
+				return nil, nil, parseError(t, "',' outside of function")
+			}
+			argc[len(argc)-1]++
+			expect = true
 			continue
 		}
-		if t == ")" {
+
+		if t.t == ")" {
 			//if the token is a right bracket (i.e. ")"), then:
 			//	while the operator at the top of the operator stack is not a left bracket:
 			//		pop operators from the operator stack onto the output queue.
@@ -174,30 +301,52 @@ func parse(tok []string) ([]string, map[string]bool, error) {
 			//	/* if the stack runs out without finding a left bracket, then there are
 			//	mismatched parentheses. */
 
+			if expect {
+				return nil, nil, parseError(t, "missing operand")
+			}
+
 			matched := false
 			for len(op) != 0 {
 				nt := op[len(op)-1]
 				op = op[:len(op)-1]
 
-				if nt == "(" {
+				if nt.t == "(" {
 					matched = true
 					break
 				}
 				oq = append(oq, nt)
 			}
 			if !matched {
-				return nil, nil, errors.New("syntax error: mismatched ()")
+				return nil, nil, parseError(t, "mismatched ()")
+			}
+
+			// FUNC(a, b) - right number of args?
+			n := argc[len(argc)-1]
+			argc = argc[:len(argc)-1]
+			fn := token{}
+			if len(op) != 0 && ops[op[len(op)-1].t].prefix() {
+				fn = op[len(op)-1]
+			}
+			if fn.t == "" && n != 1 {
+				return nil, nil, parseError(t, "',' outside of function")
+			}
+			if fn.t != "" && n != ops[fn.t].narg {
+				return nil, nil, parseError(fn, fmt.Sprintf("needs %d arguments", ops[fn.t].narg))
 			}
 			continue
 		}
 
-		opp, ok := ops[t]
+		opp, ok := ops[t.t]
 
 		// if the token is a number, then push it to the output queue.
 		if !ok {
+			if !expect {
+				return nil, nil, parseError(t, "missing operator")
+			}
+			expect = false
 			oq = append(oq, t)
-			if strings.HasPrefix(t, "Top") {
-				objs[t] = true
+			if strings.HasPrefix(t.t, "Top") {
+				objs[t.t] = true
 			}
 			continue
 		}
@@ -207,10 +356,20 @@ func parse(tok []string) ([]string, map[string]bool, error) {
 		//		greater than or equal to precedence and the operator is left associative:
 		//			pop operators from the operator stack, onto the output queue.
 		//	push the read operator onto the operator stack.
+		//  (functions + unary ops are prefix, and do not pop)
 
-		for len(op) != 0 {
+		switch {
+		case !opp.prefix() && expect:
+			return nil, nil, parseError(t, "missing operand")
+		case opp.prefix() && !expect:
+			return nil, nil, parseError(t, "missing operator")
+		}
+		expect = !opp.prefix() || opp.narg != 0
+		zarg = opp.prefix() && opp.narg == 0
+
+		for len(op) != 0 && !opp.prefix() {
 			o := op[len(op)-1]
-			top := ops[o]
+			top := ops[o.t]
 			if top.prec < opp.prec {
 				break
 			}
@@ -226,13 +385,54 @@ func parse(tok []string) ([]string, map[string]bool, error) {
 	//		there are mismatched parentheses. */
 	//		pop the operator onto the output queue.
 
+	if expect {
+		return nil, nil, parseError(last, "missing operand")
+	}
+
 	for len(op) != 0 {
 		o := op[len(op)-1]
 		op = op[:len(op)-1]
+		if o.t == "(" {
+			return nil, nil, parseError(o, "mismatched ()")
+		}
 		oq = append(oq, o)
 	}
 
-	return oq, objs, nil
+	err := checkArgs(oq)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prog := make([]string, len(oq))
+	for i, t := range oq {
+		prog[i] = t.t
+	}
+
+	return prog, objs, nil
+}
+
+// does every function have the right number of arguments?
+func checkArgs(oq []token) error {
+
+	depth := 0
+
+	for _, t := range oq {
+		opp, ok := ops[t.t]
+		if !ok {
+			depth++
+			continue
+		}
+		if depth < opp.narg {
+			return parseError(t, fmt.Sprintf("needs %d arguments", opp.narg))
+		}
+		depth += 1 - opp.narg
+	}
+
+	if depth != 1 {
+		return parseError(oq[len(oq)-1], "too many arguments")
+	}
+
+	return nil
 }
 
 // ################################################################
@@ -260,6 +460,15 @@ func RunExpr(pt []string, vars map[string]string) (string, string) {
 			continue
 		}
 
+		if opp.fraw != nil {
+			res, ok := opp.fraw(es)
+			if !ok {
+				return "", ""
+			}
+			es.push(res)
+			continue
+		}
+
 		var res float64
 		var nrdy string
 
@@ -280,7 +489,11 @@ func RunExpr(pt []string, vars map[string]string) (string, string) {
 		es.pushf(res)
 	}
 
-	return es.pop(), ""
+	res, ok := es.popv()
+	if !ok || res == noValue {
+		return "", ""
+	}
+	return res, ""
 }
 
 func (es *exprStack) push(x string) {
@@ -299,7 +512,8 @@ func (es *exprStack) pop() string {
 	return x
 }
 
-func (es *exprStack) popf() (float64, bool) {
+// pop + resolve objects, vars
+func (es *exprStack) popv() (string, bool) {
 
 	x := es.pop()
 
@@ -308,7 +522,7 @@ func (es *exprStack) popf() (float64, bool) {
 		m := monel.Find(x)
 
 		if m == nil {
-			return 0, false
+			return "", false
 		}
 
 		x = m.GetResult()
@@ -318,6 +532,16 @@ func (es *exprStack) popf() (float64, bool) {
 		x = v
 	}
 
+	return x, true
+}
+
+func (es *exprStack) popf() (float64, bool) {
+
+	x, ok := es.popv()
+	if !ok {
+		return 0, false
+	}
+
 	// convert constant
 	v, err := strconv.ParseFloat(x, 64)
 	if err != nil {
@@ -702,3 +926,203 @@ func fop_mod(es *exprStack) (float64, bool) {
 	}
 	return float64(int64(b) % ia), ok
 }
+
+// ****************************************************************
+
+func fop_status(st argus.Status) func(*exprStack) (float64, bool) {
+
+	return func(es *exprStack) (float64, bool) {
+		return float64(st), true
+	}
+}
+
+func fop_objstat(es *exprStack, rry bool) (float64, string, bool) {
+
+	m := monel.Find(es.pop())
+	if m == nil {
+		return 0, "", false
+	}
+	st, _ := m.Status()
+	return float64(st), "", true
+}
+
+func fop_objovstat(es *exprStack, rry bool) (float64, string, bool) {
+
+	m := monel.Find(es.pop())
+	if m == nil {
+		return 0, "", false
+	}
+	_, ov := m.Status()
+	return float64(ov), "", true
+}
+
+// ****************************************************************
+
+func boolf(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func fop_not(es *exprStack) (float64, bool) {
+
+	a, ok := es.popf()
+	return boolf(a == 0), ok
+}
+
+func fop_and(es *exprStack) (float64, bool) {
+
+	a, ok := es.popf()
+	if !ok {
+		return 0, false
+	}
+	b, ok := es.popf()
+
+	return boolf(b != 0 && a != 0), ok
+}
+
+func fop_or(es *exprStack) (float64, bool) {
+
+	a, ok := es.popf()
+	if !ok {
+		return 0, false
+	}
+	b, ok := es.popf()
+
+	return boolf(b != 0 || a != 0), ok
+}
+
+func fop_lt(es *exprStack) (float64, bool) {
+
+	a, ok := es.popf()
+	if !ok {
+		return 0, false
+	}
+	b, ok := es.popf()
+
+	return boolf(b < a), ok
+}
+
+func fop_le(es *exprStack) (float64, bool) {
+
+	a, ok := es.popf()
+	if !ok {
+		return 0, false
+	}
+	b, ok := es.popf()
+
+	return boolf(b <= a), ok
+}
+
+func fop_gt(es *exprStack) (float64, bool) {
+
+	a, ok := es.popf()
+	if !ok {
+		return 0, false
+	}
+	b, ok := es.popf()
+
+	return boolf(b > a), ok
+}
+
+func fop_ge(es *exprStack) (float64, bool) {
+
+	a, ok := es.popf()
+	if !ok {
+		return 0, false
+	}
+	b, ok := es.popf()
+
+	return boolf(b >= a), ok
+}
+
+// ****************************************************************
+
+// numbers compare as numbers ("1.0" == "1"), everything else as strings
+func equal(es *exprStack) (bool, bool) {
+
+	a, ok := es.popv()
+	if !ok {
+		return false, false
+	}
+	b, ok := es.popv()
+	if !ok {
+		return false, false
+	}
+
+	fa, erra := strconv.ParseFloat(a, 64)
+	fb, errb := strconv.ParseFloat(b, 64)
+	if erra == nil && errb == nil {
+		return fa == fb, true
+	}
+
+	return a == b, true
+}
+
+func fop_eq(es *exprStack) (string, bool) {
+
+	eq, ok := equal(es)
+	return fmt.Sprintf("%f", boolf(eq)), ok
+}
+
+func fop_ne(es *exprStack) (string, bool) {
+
+	eq, ok := equal(es)
+	return fmt.Sprintf("%f", boolf(!eq)), ok
+}
+
+func fop_streq(es *exprStack) (string, bool) {
+
+	a, ok := es.popv()
+	if !ok {
+		return "", false
+	}
+	b, ok := es.popv()
+
+	return fmt.Sprintf("%f", boolf(a == b)), ok
+}
+
+func fop_strne(es *exprStack) (string, bool) {
+
+	a, ok := es.popv()
+	if !ok {
+		return "", false
+	}
+	b, ok := es.popv()
+
+	return fmt.Sprintf("%f", boolf(a != b)), ok
+}
+
+// c ? a : b  =>  c a ? b :
+// ? leaves a, or noValue for : to replace
+func fop_cond(es *exprStack) (string, bool) {
+
+	a, ok := es.popv()
+	if !ok {
+		return "", false
+	}
+	c, ok := es.popf()
+	if !ok {
+		return "", false
+	}
+
+	if c != 0 {
+		return a, true
+	}
+	return noValue, true
+}
+
+func fop_else(es *exprStack) (string, bool) {
+
+	b, ok := es.popv()
+	if !ok {
+		return "", false
+	}
+	a := es.pop()
+
+	if a == noValue {
+		return b, true
+	}
+	return a, true
+}
@@ -7,7 +7,10 @@ package expr
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+
+	"argus.domain/argus/argus"
 )
 
 func TestCompute(t *testing.T) {
@@ -34,3 +37,96 @@ func TestCompute(t *testing.T) {
 	}
 
 }
+
+func exprExpect(t *testing.T, e string, vars map[string]string, exp string) {
+
+	pt, _, err := Parse(e)
+	if err != nil {
+		fmt.Printf("%s -> %v\n", e, err)
+		t.Fail()
+		return
+	}
+	res, _ := RunExpr(pt, vars)
+	if res != exp {
+		fmt.Printf("%s -> %v -> '%s' != '%s'\n", e, pt, res, exp)
+		t.Fail()
+	}
+}
+
+func TestLogical(t *testing.T) {
+
+	vars := map[string]string{"a": "7", "b": "2", "s": "up"}
+
+	exprExpect(t, "a > 5 && b < 5", vars, "1.000000")
+	exprExpect(t, "a > 5 && !(b < 5)", vars, "0.000000")
+	exprExpect(t, "a <= 5 || b >= 2", vars, "1.000000")
+	exprExpect(t, "a + 1 == 8", vars, "1.000000")
+	exprExpect(t, "s == 'up'", vars, "1.000000")
+	exprExpect(t, "s ne up", vars, "0.000000")
+	exprExpect(t, "a > 5 ? CRITICAL : CLEAR", vars, "5.000000")
+	exprExpect(t, "a > 10 ? 'major' : b > 1 ? 'minor' : 'clear'", vars, "minor")
+	exprExpect(t, "a > 10 ? 1 : b > 5 ? 2 : 3", vars, "3")
+	exprExpect(t, "time() > 0", vars, "1.000000")
+}
+
+func TestParseError(t *testing.T) {
+
+	for e, pos := range map[string]int{
+		"a + (b * 2":  5,
+		"a + b) * 2":  6,
+		"a + * 2":     5,
+		"a = 2":       3,
+		"a + 'b":      5,
+		"a b":         3,
+		"sqrt() + 1":  6,
+		"x > 1 ? 2 :": 11,
+	} {
+		_, _, err := Parse(e)
+		perr, ok := err.(*ParseError)
+		if !ok || perr.Pos != pos {
+			fmt.Printf("%s -> %v (expected position %d)\n", e, err, pos)
+			t.Fail()
+		}
+	}
+}
+
+func TestStatusExpr(t *testing.T) {
+
+	pt, _, _ := Parse("x > 90 ? 'critical' : x > 80 ? WARNING : CLEAR")
+
+	for x, exp := range map[string]argus.Status{"95": argus.CRITICAL, "85": argus.WARNING, "5": argus.CLEAR} {
+		st, _, err := RunExprS(pt, map[string]string{"x": x})
+		if err != nil || st != exp {
+			fmt.Printf("x %s -> %s (%v) != %s\n", x, st, err, exp)
+			t.Fail()
+		}
+	}
+}
+
+func TestHistoryParse(t *testing.T) {
+
+	pt, objs, err := Parse("Top:Web:rps > AGO(Top:Web:rps, 7d) * 1.5 && PCTL_OVER(Top:Web:lat, 95, 1h) > 2")
+	exp := "Top:Web:rps Top:Web:rps 7d AGO 1.5 * > Top:Web:lat 95 1h PCTL_OVER 2 > &&"
+
+	if err != nil || strings.Join(pt, " ") != exp || !objs["Top:Web:lat"] {
+		fmt.Printf("%v -> %v\n", pt, err)
+		t.Fail()
+	}
+
+	pt, _, err = Parse("PCTL_DAY(Top:Web:lat, 95) > 800")
+	if err != nil || strings.Join(pt, " ") != "Top:Web:lat 95 PCTL_DAY 800 >" {
+		fmt.Printf("%v -> %v\n", pt, err)
+		t.Fail()
+	}
+
+	_, _, err = Parse("1 + PCTL_OVER(Top:Web:lat, 1h)")
+	if perr, ok := err.(*ParseError); !ok || perr.Pos != 5 {
+		fmt.Printf("args -> %v\n", err)
+		t.Fail()
+	}
+
+	if p := percentile([]float64{5, 1, 4, 2, 3}, 75); p != 4 {
+		fmt.Printf("percentile %f\n", p)
+		t.Fail()
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 18:05 (EDT)
+// Function: historical + windowed expression functions, from the graph data
+
+package expr
+
+import (
+	"math"
+	"sort"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/graph"
+	"argus.domain/argus/monel"
+)
+
+func init() {
+	ops["AGO"] = OP{20, 2, false, nil, fop_ago, nil}            // AGO(Top:Foo, 7d) - value a week ago
+	ops["AVG_OVER"] = OP{20, 2, false, nil, fop_avgover, nil}   // AVG_OVER(Top:Foo, 1h)
+	ops["MIN_OVER"] = OP{20, 2, false, nil, fop_minover, nil}   //
+	ops["MAX_OVER"] = OP{20, 2, false, nil, fop_maxover, nil}   //
+	ops["PCTL_OVER"] = OP{20, 3, false, nil, fop_pctlover, nil} // PCTL_OVER(Top:Foo, 95, 1d)
+	ops["RATE"] = OP{20, 1, false, nil, fop_rate, nil}          // RATE(Top:Foo) - change per second
+	ops["PCTL_HOUR"] = OP{20, 2, false, nil, fop_pctlhour, nil} // PCTL_HOUR(Top:Foo, 95) - this hour, from the sketch
+	ops["PCTL_DAY"] = OP{20, 2, false, nil, fop_pctlday, nil}   // PCTL_DAY(Top:Foo, 95) - today
+}
+
+// graph data for an object
+func history(obj string, since int64) ([]int64, []float64) {
+
+	m := monel.Find(obj)
+	if m == nil || !m.Cf.Graph {
+		return nil, nil
+	}
+
+	return graph.History(m.Pathname("", ""), since)
+}
+
+func (es *exprStack) popTimespec() (int64, bool) {
+
+	x, ok := es.popv()
+	if !ok {
+		return 0, false
+	}
+
+	t, err := argus.Timespec(x, 1)
+	if err != nil || t <= 0 {
+		return 0, false
+	}
+	return t, true
+}
+
+// values over the past period
+func (es *exprStack) popWindow() ([]float64, bool) {
+
+	dt, ok := es.popTimespec()
+	if !ok {
+		return nil, false
+	}
+
+	_, vs := history(es.pop(), clock.Unix()-dt)
+	if len(vs) == 0 {
+		return nil, false
+	}
+	return vs, true
+}
+
+// ****************************************************************
+
+func fop_ago(es *exprStack, rry bool) (float64, string, bool) {
+
+	dt, ok := es.popTimespec()
+	if !ok {
+		return 0, "", false
+	}
+
+	when := clock.Unix() - dt
+	ts, vs := history(es.pop(), when-3600)
+
+	// nothing that old?
+	if len(ts) == 0 || ts[0] > when {
+		return 0, "", false
+	}
+
+	// closest
+	best := 0
+	for i, t := range ts {
+		if abs64(t-when) < abs64(ts[best]-when) {
+			best = i
+		}
+	}
+
+	return vs[best], "", true
+}
+
+func fop_avgover(es *exprStack, rry bool) (float64, string, bool) {
+
+	vs, ok := es.popWindow()
+	if !ok {
+		return 0, "", false
+	}
+
+	sum := 0.0
+	for _, v := range vs {
+		sum += v
+	}
+	return sum / float64(len(vs)), "", true
+}
+
+func fop_minover(es *exprStack, rry bool) (float64, string, bool) {
+
+	vs, ok := es.popWindow()
+	if !ok {
+		return 0, "", false
+	}
+
+	min := vs[0]
+	for _, v := range vs {
+		if v < min {
+			min = v
+		}
+	}
+	return min, "", true
+}
+
+func fop_maxover(es *exprStack, rry bool) (float64, string, bool) {
+
+	vs, ok := es.popWindow()
+	if !ok {
+		return 0, "", false
+	}
+
+	max := vs[0]
+	for _, v := range vs {
+		if v > max {
+			max = v
+		}
+	}
+	return max, "", true
+}
+
+func fop_pctlover(es *exprStack, rry bool) (float64, string, bool) {
+
+	dt, ok := es.popTimespec()
+	if !ok {
+		return 0, "", false
+	}
+	pct, ok := es.popf()
+	if !ok || pct < 0 || pct > 100 {
+		return 0, "", false
+	}
+
+	_, vs := history(es.pop(), clock.Unix()-dt)
+	if len(vs) == 0 {
+		return 0, "", false
+	}
+
+	return percentile(vs, pct), "", true
+}
+
+// between the two most recent samples
+func fop_rate(es *exprStack, rry bool) (float64, string, bool) {
+
+	ts, vs := history(es.pop(), clock.Unix()-3600)
+
+	n := len(ts)
+	if n < 2 || ts[n-1] == ts[n-2] {
+		return 0, "", false
+	}
+
+	return (vs[n-1] - vs[n-2]) / float64(ts[n-1]-ts[n-2]), "", true
+}
+
+func fop_pctlhour(es *exprStack, rry bool) (float64, string, bool) {
+	return es.sketchQuantile("hours")
+}
+
+func fop_pctlday(es *exprStack, rry bool) (float64, string, bool) {
+	return es.sketchQuantile("days")
+}
+
+func (es *exprStack) sketchQuantile(which string) (float64, string, bool) {
+
+	pct, ok := es.popf()
+	if !ok || pct < 0 || pct > 100 {
+		return 0, "", false
+	}
+
+	m := monel.Find(es.pop())
+	if m == nil || !m.Cf.Graph {
+		return 0, "", false
+	}
+
+	v, ok := graph.Quantile(m.Pathname("", ""), which, pct/100)
+	return v, "", ok
+}
+
+// ****************************************************************
+
+func abs64(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// linear interpolation between closest ranks
+func percentile(vals []float64, pct float64) float64 {
+
+	v := append([]float64(nil), vals...)
+	sort.Float64s(v)
+
+	r := pct / 100 * float64(len(v)-1)
+	lo := int(math.Floor(r))
+	hi := int(math.Ceil(r))
+
+	return v[lo] + (v[hi]-v[lo])*(r-float64(lo))
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-24 18:15 (EDT)
+// Function:
+
+package expr
+
+import (
+	"os"
+	"testing"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/config"
+	"argus.domain/argus/configure"
+	"argus.domain/argus/graph"
+	"argus.domain/argus/graph/graphd"
+	"argus.domain/argus/monel"
+)
+
+// a minimal graphed object
+type histMon struct {
+	m *monel.M
+}
+
+func (h *histMon) Persist(map[string]interface{})                {}
+func (h *histMon) Restore(map[string]interface{})                {}
+func (h *histMon) WebJson(map[string]interface{})                {}
+func (h *histMon) WebMeta(map[string]interface{})                {}
+func (h *histMon) Config(*configure.CF) error                    { return nil }
+func (h *histMon) Dump(argus.Dumper)                             {}
+func (h *histMon) CheckNow()                                     {}
+func (h *histMon) Init() error                                   { return nil }
+func (h *histMon) DoneConfig()                                   {}
+func (h *histMon) Recycle()                                      {}
+func (h *histMon) Children() []*monel.M                          { return nil }
+func (h *histMon) Self() *monel.M                                { return h.m }
+func (h *histMon) GraphList(string, []interface{}) []interface{} { return nil }
+
+func histObj(unique string, file string) {
+
+	h := &histMon{}
+	m := monel.New(h, nil)
+	h.m = m
+	m.Cf.Unique = unique
+	m.Cf.Graph = true
+	m.DirName = "H"
+	m.Filename = file
+	m.Init()
+}
+
+func TestHistoryEval(t *testing.T) {
+
+	dir, err := os.MkdirTemp("", "expr")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer os.RemoveAll(dir)
+	os.MkdirAll(dir+"/gdata/H", 0777)
+	config.Cf().Datadir = dir
+	graphd.Init()
+
+	histObj("Top:Hist:rps", "rps")
+	histObj("Top:Hist:empty", "empty")
+
+	// every 10 minutes, for the past 50 minutes: 10, 20, ... 60
+	now := clock.Unix()
+	lay := &graph.Layout{Samples: 100, Hours: 24, Days: 7}
+	for i := 0; i < 6; i++ {
+		graph.Add(&graph.Sample{Path: "H/rps", When: now - 3000 + int64(i)*600, Status: argus.CLEAR, Value: float64(10 * (i + 1)), Layout: lay})
+	}
+
+	// normal values
+	exprExpect(t, "AGO(Top:Hist:rps, 30m)", nil, "30.000000")
+	exprExpect(t, "AVG_OVER(Top:Hist:rps, 1h)", nil, "35.000000")
+	exprExpect(t, "MIN_OVER(Top:Hist:rps, 1h)", nil, "10.000000")
+	exprExpect(t, "MAX_OVER(Top:Hist:rps, 15m)", nil, "60.000000")
+	exprExpect(t, "MIN_OVER(Top:Hist:rps, 15m)", nil, "50.000000")
+	exprExpect(t, "PCTL_OVER(Top:Hist:rps, 50, 1h)", nil, "35.000000")
+	exprExpect(t, "RATE(Top:Hist:rps)", nil, "0.016667")
+
+	// missing data
+	exprExpect(t, "AVG_OVER(Top:Hist:empty, 1h)", nil, "")
+	exprExpect(t, "AGO(Top:Hist:empty, 30m)", nil, "")
+	exprExpect(t, "RATE(Top:Hist:empty)", nil, "")
+	exprExpect(t, "AVG_OVER(Top:Hist:nonesuch, 1h)", nil, "")
+
+	// out of range offsets
+	exprExpect(t, "AGO(Top:Hist:rps, 7d)", nil, "")
+	exprExpect(t, "AGO(Top:Hist:rps, 0)", nil, "")
+	exprExpect(t, "AGO(Top:Hist:rps, 0 - 3600)", nil, "")
+	exprExpect(t, "AVG_OVER(Top:Hist:rps, xyz)", nil, "")
+	exprExpect(t, "PCTL_OVER(Top:Hist:rps, 101, 1h)", nil, "")
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-21 14:05 (EDT)
+// Function: time-shifted data, for week-over-week comparisons
+
+package graph
+
+import (
+	"fmt"
+
+	"argus.domain/argus/clock"
+	"argus.domain/argus/graph/graphd"
+)
+
+// data from offset seconds ago, moved forward to line up with the present
+// since is in present (shifted) time
+func GetShifted(file string, which string, since int64, width int, offset int64) []*Point {
+
+	if offset == 0 {
+		return graphd.Get(file, which, since, width)
+	}
+	if since > 0 {
+		since -= offset
+	}
+
+	return shift(graphd.Get(file, which, since, width), offset, clock.Unix())
+}
+
+// copy, do not modify the originals
+func shift(data []*Point, offset int64, now int64) []*Point {
+
+	var res []*Point
+
+	for _, e := range data {
+		if e.Time+offset > now {
+			continue
+		}
+		c := *e
+		c.Time += offset
+		res = append(res, &c)
+	}
+	return res
+}
+
+// 86400 => "1 day ago"
+func OffsetLabel(offset int64) string {
+
+	unit := func(n int64, name string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s ago", name)
+		}
+		return fmt.Sprintf("%d %ss ago", n, name)
+	}
+
+	switch {
+	case offset >= 7*86400 && offset%(7*86400) == 0:
+		return unit(offset/(7*86400), "week")
+	case offset >= 86400 && offset%86400 == 0:
+		return unit(offset/86400, "day")
+	case offset >= 3600 && offset%3600 == 0:
+		return unit(offset/3600, "hour")
+	}
+	return unit(offset, "second")
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-21 14:40 (EDT)
+// Function:
+
+package graph
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestShift(t *testing.T) {
+
+	var data []*Point
+	for i := int64(0); i < 10; i++ {
+		data = append(data, &Point{Time: 1000 + i*100, Value: float32(i)})
+	}
+
+	res := shift(data, 500, 1900)
+
+	// 1000 - 1400 => 1500 - 1900. the rest are in the future
+	if len(res) != 5 || res[0].Time != 1500 || res[4].Time != 1900 || res[4].Value != 4 {
+		fmt.Printf("shift: %d points\n", len(res))
+		t.Fail()
+	}
+	if data[0].Time != 1000 {
+		fmt.Printf("shift modified the original\n")
+		t.Fail()
+	}
+
+	for off, want := range map[int64]string{86400: "1 day ago", 7 * 86400: "1 week ago", 14 * 86400: "2 weeks ago", 7200: "2 hours ago"} {
+		if l := OffsetLabel(off); l != want {
+			fmt.Printf("label %d: %s != %s\n", off, l, want)
+			t.Fail()
+		}
+	}
+}
@@ -6,19 +6,146 @@
 package graph
 
 import (
+	"sync"
+
 	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
 	"argus.domain/argus/graph/graphd"
 )
 
-// RSN - pluggable graphing subsystems: graphite, grafana, influxdb, ...
-type Grapher interface{}
+// a graphing subsystem. external backends (graphite, influxdb, ...) see grapher.go
+type Grapher interface {
+	Add(*Sample)
+}
+
+// a data point
+type Sample struct {
+	Object string // unique name, Top:Foo:Bar
+	Path   string // graph file
+	Tags   string
+	Darp   string
+	When   int64
+	Status argus.Status
+	Value  float64
+	Yn     float64
+	Dn     float64
+	Layout *Layout // graph file retention
+}
+
+// retention + resolution
+type Layout = graphd.Layout
+
+// local graph files
+type graphdGrapher struct{}
+
+func (graphdGrapher) Add(s *Sample) {
+	graphd.Add(s.Path, s.When, s.Status, s.Value, s.Yn, s.Dn, s.Layout)
+}
+
+var glock sync.RWMutex
+
+// graphd is always first, and can be replaced (eg. to send to the darp master)
+var graphers = []Grapher{graphdGrapher{}}
+
+// send to every grapher
+func Add(s *Sample) {
+
+	glock.RLock()
+	defer glock.RUnlock()
+
+	for _, g := range graphers {
+		g.Add(s)
+	}
+}
+
+// only the first grapher (normally, graphd)
+// for data relayed from other servers, which sent it to the other graphers themselves
+func AddLocal(s *Sample) {
+
+	glock.RLock()
+	defer glock.RUnlock()
+
+	graphers[0].Add(s)
+}
+
+// replace graphd
+func SetLocal(g Grapher) {
+
+	glock.Lock()
+	defer glock.Unlock()
+
+	graphers[0] = g
+}
+
+// change the retention of an existing graph
+func Resize(file string, lay *Layout) (bool, error) {
 
-func Add(file string, when int64, status argus.Status, val float64, yn float64, dn float64) {
+	return graphd.Resize(file, lay)
+}
+
+func ValidConsol(name string) bool {
 
-	graphd.Add(file, when, status, val, yn, dn)
+	return graphd.ValidConsol(name)
 }
 
 func Get(file string, which string, since int64, width int) interface{} {
 
 	return graphd.Get(file, which, since, width)
 }
+
+// a data point or summary
+type Point = graphd.Export
+
+// raw data, in a time range
+func Export(file string, which string, start int64, end int64) []*Point {
+
+	var res []*Point
+
+	for _, e := range graphd.Get(file, which, start, 0) {
+		if end == 0 || e.Time <= end {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// quantile (0 - 1) of the current hour or day
+func Quantile(file string, which string, q float64) (float64, bool) {
+
+	return graphd.Quantile(file, which, q)
+}
+
+// backfill
+func Import(file string, which string, recs []*Point, lay *Layout) (int, error) {
+
+	return graphd.Import(file, which, recs, lay)
+}
+
+// values (or summary averages) since a time - for forecasting, expressions
+func Trend(file string, which string, since int64) ([]int64, []float64) {
+
+	var ts []int64
+	var vs []float64
+
+	for _, e := range graphd.Get(file, which, since, 0) {
+		ts = append(ts, e.Time)
+		vs = append(vs, float64(e.Value))
+	}
+
+	return ts, vs
+}
+
+// values since a time, at a resolution that reaches back that far
+func History(file string, since int64) ([]int64, []float64) {
+
+	which := "samples"
+
+	switch age := clock.Unix() - since; {
+	case age > 30*86400:
+		which = "days"
+	case age > 86400:
+		which = "hours"
+	}
+
+	return Trend(file, which, since)
+}
@@ -7,6 +7,9 @@ package graphd
 
 import (
 	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
 	"math"
 	"os"
 	"sync"
@@ -28,15 +31,32 @@ type graphData struct {
 
 // similar to, but not exactly the same as, argus3.x
 const (
-	MAGIC     = "AGD5"
+	MAGIC     = "AGDF"
+	VERSION   = 2
 	HdrSize   = 1024
 	SampSize  = 20
-	SummySize = 32
+	SummySize = 32 // version 1
+	QSummSize = 48 // version 2, with quantiles
 	SampNMax  = 1024
 	HourNMax  = 1024
 	DayNMax   = 1024
+	MaxNMax   = 1 << 22 // ~ 80MB of samples
+	SampResol = 60      // assumed, if not recorded in the file
 )
 
+// files written before the header was versioned. same layout as version 1
+const LEGACYMAGIC = "AGD5"
+
+// how summaries are consolidated
+const (
+	CONSOL_AVE = iota
+	CONSOL_MIN
+	CONSOL_MAX
+	CONSOL_LAST
+)
+
+var consolNames = []string{"average", "min", "max", "last"}
+
 type HeaderSect struct {
 	Idx    int32
 	NMax   int32
@@ -49,16 +69,32 @@ type HeaderSect struct {
 	Exp    float32
 	Delt   float32
 	Status int32
-	Pad    [84]byte // total size = 128
+	Last   float32
+	Pad    [80]byte // total size = 128
 }
 
 type Header struct {
-	Magic [4]byte
-	Lastt uint32    // times are unix>>2
-	Pad   [120]byte // next section aligned @128
-	Samp  HeaderSect
-	Hour  HeaderSect
-	Day   HeaderSect
+	Magic   [4]byte
+	Lastt   uint32 // times are unix>>2
+	Version uint16
+	Consol  uint8
+	Flags   uint8
+	Resol   uint32    // seconds between samples
+	Pad     [112]byte // next section aligned @128
+	Samp    HeaderSect
+	Hour    HeaderSect
+	Day     HeaderSect
+	HourQ   Sketch // version 2
+	DayQ    Sketch
+}
+
+// retention + resolution, from the config
+type Layout struct {
+	Samples    int // number of each to keep
+	Hours      int
+	Days       int
+	Resolution int
+	Consol     string // average, min, max, last
 }
 
 type SampleData struct {
@@ -74,12 +110,20 @@ type SummyData struct {
 	Status int32
 	Min    float32
 	Max    float32
-	Ave    float32
+	Ave    float32 // consolidated value. the average, by default
 	Stdev  float32
 	Exp    float32
 	Delt   float32
 	// total size = 32
 }
+type QSummyData struct {
+	SummyData
+	P50 float32
+	P90 float32
+	P95 float32
+	P99 float32
+	// total size = 48
+}
 type Export struct {
 	Time   int64
 	Status argus.Status
@@ -89,6 +133,10 @@ type Export struct {
 	Stdev  float32
 	Exp    float32
 	Delt   float32
+	P50    float32 // summaries in version 2 files. older files are upgraded at the next roll
+	P90    float32
+	P95    float32
+	P99    float32
 }
 
 const NLOCK = 251 // prime
@@ -106,7 +154,7 @@ func init() {
 	if binary.Size(h.Samp) != 128 {
 		dl.Fatal("headerSection botched (%d)", binary.Size(h.Samp))
 	}
-	if binary.Size(h) != 512 {
+	if binary.Size(h) != HdrSize {
 		dl.Fatal("header size botched (%d)", binary.Size(h))
 	}
 
@@ -118,7 +166,7 @@ func Init() {
 	// RSN - other graphing params, defaults, ...
 }
 
-func Add(file string, when int64, status argus.Status, val float64, yn float64, dn float64) {
+func Add(file string, when int64, status argus.Status, val float64, yn float64, dn float64, lay *Layout) {
 
 	dl.Debug("add graph")
 	if datadir == "" {
@@ -137,17 +185,20 @@ func Add(file string, when int64, status argus.Status, val float64, yn float64,
 	// find or create file
 
 	file = filename(file)
-	g := open(file)
-	if g == nil {
-		dl.Debug("create %s", file)
-		g = create(file)
-	}
+	g := openOrCreate(file, lay)
 	if g == nil {
-		dl.Debug("cannot open")
 		return
 	}
+	if g.h.Version < VERSION && g.rolls(when) {
+		g = g.upgrade(file)
+	}
 
 	defer g.close()
+	if lay != nil {
+		// the sizes can only be changed by Resize, the rest can change anytime
+		g.h.Consol = lay.consol()
+		g.h.Resol = lay.resol()
+	}
 	g.add(when, status, val, yn, dn)
 	g.save()
 }
@@ -183,9 +234,20 @@ func (g *graphData) add(when int64, status argus.Status, val float64, yn float64
 	// update header summaries
 	g.h.Hour.add(status, float32(val), float32(yn), float32(dn))
 	g.h.Day.add(status, float32(val), float32(yn), float32(dn))
+	g.h.HourQ.add(float32(val))
+	g.h.DayQ.add(float32(val))
 	g.h.Lastt = fromSeconds(when)
 }
 
+// will adding a sample at this time write a summary?
+func (g *graphData) rolls(when int64) bool {
+
+	lt := time.Unix(toSeconds(g.h.Lastt), 0).Local()
+	ct := time.Unix(when, 0).Local()
+
+	return lt.Day() != ct.Day() || lt.Hour() != ct.Hour()
+}
+
 // ################################################################
 
 func (hs *HeaderSect) add(status argus.Status, val, exp, delt float32) {
@@ -202,6 +264,7 @@ func (hs *HeaderSect) add(status argus.Status, val, exp, delt float32) {
 	hs.Sigm2 += val * val
 	hs.Exp += exp
 	hs.Delt += delt
+	hs.Last = val
 
 	if int32(status) > hs.Status {
 		hs.Status = int32(status)
@@ -210,24 +273,30 @@ func (hs *HeaderSect) add(status argus.Status, val, exp, delt float32) {
 
 func (g *graphData) rollHour(val float32) {
 
-	g.roll(&g.h.Hour, g.hourStart, val)
+	g.roll(&g.h.Hour, &g.h.HourQ, g.hourStart, val)
 }
 
 func (g *graphData) rollDay(val float32) {
 
-	g.roll(&g.h.Day, g.dayStart, val)
+	g.roll(&g.h.Day, &g.h.DayQ, g.dayStart, val)
 }
 
-func (g *graphData) roll(h *HeaderSect, start int64, val float32) {
+func (g *graphData) roll(h *HeaderSect, q *Sketch, start int64, val float32) {
 
 	if h.NSamp == 0 {
 		return
 	}
 
 	dl.Debug("roll")
-	sum := h.summarize(g.h.Lastt)
-	g.seek(start + SummySize*int64(h.Idx))
-	binary.Write(g.f, binary.BigEndian, sum)
+	sum := &QSummyData{SummyData: *h.summarize(g.h.Lastt, g.h.Consol)}
+	sum.P50 = q.quantile(quantiles[0])
+	sum.P90 = q.quantile(quantiles[1])
+	sum.P95 = q.quantile(quantiles[2])
+	sum.P99 = q.quantile(quantiles[3])
+	q.reset()
+
+	g.seek(start + g.summSize()*int64(h.Idx))
+	g.writeSumm(sum)
 	h.Idx = (h.Idx + 1) % h.NMax
 	h.Count++
 	if h.Count > h.NMax {
@@ -236,7 +305,7 @@ func (g *graphData) roll(h *HeaderSect, start int64, val float32) {
 	h.reset(val)
 }
 
-func (hs *HeaderSect) summarize(lastt uint32) *SummyData {
+func (hs *HeaderSect) summarize(lastt uint32, consol uint8) *SummyData {
 
 	n := float32(hs.NSamp)
 	ave := hs.Sigm / n
@@ -258,6 +327,15 @@ func (hs *HeaderSect) summarize(lastt uint32) *SummyData {
 		Delt:   hs.Delt / n,
 	}
 
+	switch consol {
+	case CONSOL_MIN:
+		s.Ave = hs.Min
+	case CONSOL_MAX:
+		s.Ave = hs.Max
+	case CONSOL_LAST:
+		s.Ave = hs.Last
+	}
+
 	return s
 }
 
@@ -270,6 +348,7 @@ func (hs *HeaderSect) reset(val float32) {
 	hs.Exp = 0
 	hs.Delt = 0
 	hs.Status = 0
+	hs.Last = val
 }
 
 // ################################################################
@@ -280,12 +359,12 @@ func Get(file string, which string, since int64, width int) []*Export {
 		dl.Debug("no datadir")
 		return nil
 	}
-	file = filename(file)
-
 	lno := lockno(file)
 	locks[lno].RLock()
 	defer locks[lno].RUnlock()
 
+	file = filename(file)
+
 	// open
 	g := open(file)
 	if g == nil {
@@ -318,22 +397,13 @@ func (g *graphData) getSamples(since int64, width int) []*Export {
 
 	// estimate start pos from since
 	if since > 0 {
-		minago := int((clock.Unix() - since + 59) / 60)
-		startRec = int(g.h.Samp.Idx) - minago
-
-		if g.h.Samp.Count < g.h.Samp.NMax {
-			if startRec < 0 {
-				startRec = 0
-			}
-		} else {
-			if startRec < 0 {
-				startRec += int(g.h.Samp.NMax)
-				startRec %= int(g.h.Samp.NMax)
-			}
+		res := int64(g.h.Resol)
+		if res == 0 {
+			res = SampResol
 		}
-
-		numRec = minago
-		dl.Debug("since: idx %d, minago %d, start %d, num %d", g.h.Samp.Idx, minago, startRec, numRec)
+		ago := int((clock.Unix() - since + res - 1) / res)
+		startRec, numRec = g.h.Samp.recent(ago)
+		dl.Debug("since: idx %d, ago %d, start %d, num %d", g.h.Samp.Idx, ago, startRec, numRec)
 	}
 
 	r := NewCbufReader(g.f, g.sampStart, int64(g.h.Samp.NMax*SampSize))
@@ -396,18 +466,15 @@ func (g *graphData) getSummy(hs *HeaderSect, start int64, since int64, width int
 	// estimate start pos from since
 	if since > 0 {
 		uago := (int(clock.Unix()-since) + spu - 1) / spu
-		startRec += int(hs.NMax) - uago
-		startRec %= int(g.h.Samp.NMax)
-		numRec = uago
+		startRec, numRec = hs.recent(uago)
 	}
 
-	r := NewCbufReader(g.f, start, int64(hs.NMax*SummySize))
-	r.Seek(int64(startRec * SummySize))
+	r := NewCbufReader(g.f, start, int64(hs.NMax)*g.summSize())
+	r.Seek(int64(startRec) * g.summSize())
 
 	var res []*Export
 	for i := 0; i < numRec; i++ {
-		s := &SummyData{}
-		binary.Read(r, binary.BigEndian, s)
+		s := g.readSumm(r)
 
 		if since > 0 && toSeconds(s.When) <= since {
 			continue
@@ -426,6 +493,10 @@ func (g *graphData) getSummy(hs *HeaderSect, start int64, since int64, width int
 			Max:    s.Max,
 			Exp:    s.Exp,
 			Delt:   s.Delt,
+			P50:    s.P50,
+			P90:    s.P90,
+			P95:    s.P95,
+			P99:    s.P99,
 		}
 		res = append(res, e)
 	}
@@ -433,6 +504,20 @@ func (g *graphData) getSummy(hs *HeaderSect, start int64, since int64, width int
 	return res
 }
 
+// the position + number of the most recent n records
+func (hs *HeaderSect) recent(n int) (int, int) {
+
+	if n <= 0 || n > int(hs.Count) {
+		n = int(hs.Count)
+	}
+
+	start := int(hs.Idx) - n
+	if start < 0 {
+		start += int(hs.NMax)
+	}
+	return start, n
+}
+
 // ################################################################
 
 func filename(file string) string {
@@ -441,31 +526,56 @@ func filename(file string) string {
 
 func open(file string) *graphData {
 
-	// open, read header
-	f, err := os.OpenFile(file, os.O_RDWR, 0666)
+	g, err := openFile(file)
 	if err != nil {
 		dl.Debug("open failed: %v", err)
 		return nil
 	}
+	return g
+}
+
+func openFile(file string) (*graphData, error) {
+
+	// open, read header
+	f, err := os.OpenFile(file, os.O_RDWR, 0666)
+	if err != nil {
+		return nil, err
+	}
 	g := &graphData{f: f}
-	ok := g.readHeader()
-	if !ok {
-		dl.Verbose("corrupt graph data: %s", filename(file))
+	err = g.readHeader()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return g, nil
+}
+
+// only create a file that does not exist. never clobber one we cannot read
+func openOrCreate(file string, lay *Layout) *graphData {
+
+	g, err := openFile(file)
+	if err == nil {
+		return g
+	}
+	if !os.IsNotExist(err) {
+		dl.Verbose("cannot open graph data: %v", err)
 		return nil
 	}
-	return g
+
+	dl.Debug("create %s", file)
+	return create(file, lay)
 }
 
-func create(file string) *graphData {
+func create(file string, lay *Layout) *graphData {
 
-	f, err := os.Create(file)
+	f, err := os.OpenFile(file, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
 	if err != nil {
 		dl.Verbose("cannot save graph data: %v", err)
 		return nil
 	}
 
 	g := &graphData{f: f}
-	g.newHeader()
+	g.newHeader(lay)
 	return g
 }
 
@@ -473,26 +583,37 @@ func (g *graphData) seek(pos int64) {
 	g.f.Seek(pos, 0)
 }
 
-func (g *graphData) readHeader() bool {
+func (g *graphData) readHeader() error {
 
 	g.h = &Header{}
 	g.seek(0)
 	binary.Read(g.f, binary.BigEndian, g.h)
 
-	if string(g.h.Magic[:]) != MAGIC {
-		return false
+	switch string(g.h.Magic[:]) {
+	case MAGIC:
+		if g.h.Version > VERSION {
+			return fmt.Errorf("graph data is newer version (%d)", g.h.Version)
+		}
+	case LEGACYMAGIC:
+		// upgraded in place on the next save
+		copy(g.h.Magic[:], MAGIC)
+		g.h.Version = 1
+	default:
+		return errors.New("corrupt graph data")
 	}
+
 	g.initHeader()
-	return true
+	return nil
 }
 
-func (g *graphData) newHeader() {
+func (g *graphData) newHeader(lay *Layout) {
 
 	h := &Header{
-		Samp: HeaderSect{NMax: SampNMax},
-		Hour: HeaderSect{NMax: HourNMax},
-		Day:  HeaderSect{NMax: DayNMax},
+		Version: VERSION,
+		Consol:  lay.consol(),
+		Resol:   lay.resol(),
 	}
+	h.Samp.NMax, h.Hour.NMax, h.Day.NMax = lay.sizes()
 	copy(h.Magic[:], MAGIC)
 	g.h = h
 	g.initHeader()
@@ -501,7 +622,36 @@ func (g *graphData) newHeader() {
 func (g *graphData) initHeader() {
 	g.sampStart = HdrSize
 	g.hourStart = g.sampStart + SampSize*int64(g.h.Samp.NMax)
-	g.dayStart = g.hourStart + SummySize*int64(g.h.Hour.NMax)
+	g.dayStart = g.hourStart + g.summSize()*int64(g.h.Hour.NMax)
+}
+
+// the summary records grew in version 2
+func (g *graphData) summSize() int64 {
+
+	if g.h.Version < 2 {
+		return SummySize
+	}
+	return QSummSize
+}
+
+func (g *graphData) readSumm(r io.Reader) *QSummyData {
+
+	s := &QSummyData{}
+	if g.h.Version < 2 {
+		binary.Read(r, binary.BigEndian, &s.SummyData)
+	} else {
+		binary.Read(r, binary.BigEndian, s)
+	}
+	return s
+}
+
+func (g *graphData) writeSumm(s *QSummyData) {
+
+	if g.h.Version < 2 {
+		binary.Write(g.f, binary.BigEndian, &s.SummyData)
+	} else {
+		binary.Write(g.f, binary.BigEndian, s)
+	}
 }
 
 func (g *graphData) save() {
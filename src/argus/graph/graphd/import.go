@@ -0,0 +1,114 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-21 09:10 (EDT)
+// Function: backfill graph data
+
+package graphd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// merge records into the file, keeping the most recent that fit.
+// imported records replace existing records with the same time
+func Import(file string, which string, recs []*Export, lay *Layout) (int, error) {
+
+	if datadir == "" {
+		return 0, errors.New("no datadir")
+	}
+
+	lno := lockno(file)
+	locks[lno].Lock()
+	defer locks[lno].Unlock()
+
+	file = filename(file)
+	g := openOrCreate(file, lay)
+	if g == nil {
+		return 0, fmt.Errorf("cannot open %s", file)
+	}
+	defer g.close()
+
+	var hs *HeaderSect
+	var start int64
+	summy := true
+
+	switch which {
+	case "samples":
+		hs, start, summy = &g.h.Samp, g.sampStart, false
+	case "hours":
+		hs, start = &g.h.Hour, g.hourStart
+	case "days":
+		hs, start = &g.h.Day, g.dayStart
+	default:
+		return 0, fmt.Errorf("invalid data '%s'", which)
+	}
+
+	// existing + new, by time
+	merged := make(map[uint32]*Export)
+	var old []*Export
+	if summy {
+		old = g.getSummy(hs, start, 0, 0, 0)
+	} else {
+		old = g.getSamples(0, 0)
+	}
+	for _, e := range old {
+		merged[fromSeconds(e.Time)] = e
+	}
+	for _, e := range recs {
+		merged[fromSeconds(e.Time)] = e
+	}
+
+	all := make([]*Export, 0, len(merged))
+	for _, e := range merged {
+		all = append(all, e)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Time < all[j].Time })
+
+	if len(all) > int(hs.NMax) {
+		all = all[len(all)-int(hs.NMax):]
+	}
+
+	// rewrite the section
+	g.seek(start)
+	for _, e := range all {
+		if summy {
+			g.writeSumm(&QSummyData{
+				SummyData: SummyData{
+					When:   fromSeconds(e.Time),
+					Status: int32(e.Status),
+					Min:    e.Min,
+					Max:    e.Max,
+					Ave:    e.Value,
+					Stdev:  e.Stdev,
+					Exp:    e.Exp,
+					Delt:   e.Delt,
+				},
+				P50: e.P50,
+				P90: e.P90,
+				P95: e.P95,
+				P99: e.P99,
+			})
+		} else {
+			binary.Write(g.f, binary.BigEndian, &SampleData{
+				When:   fromSeconds(e.Time),
+				Status: int32(e.Status),
+				Value:  e.Value,
+				Exp:    e.Exp,
+				Delt:   e.Delt,
+			})
+		}
+	}
+
+	hs.Idx = int32(len(all)) % hs.NMax
+	hs.Count = int32(len(all))
+
+	if n := len(all); n != 0 && fromSeconds(all[n-1].Time) > g.h.Lastt {
+		g.h.Lastt = fromSeconds(all[n-1].Time)
+	}
+	g.save()
+
+	return len(recs), nil
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-21 10:45 (EDT)
+// Function:
+
+package graphd
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"argus.domain/argus/argus"
+)
+
+func TestImport(t *testing.T) {
+
+	dir, err := os.MkdirTemp("", "graphd")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Mkdir(dir+"/gdata", 0777)
+	datadir = dir
+
+	lay := &Layout{Samples: 8}
+	when := int64(1600000000)
+
+	for i := 0; i < 4; i++ {
+		Add("imp", when+int64(i)*600, argus.CLEAR, float64(i), 0, 0, lay)
+	}
+
+	// backfill older data, overlap one
+	var recs []*Export
+	for i := -6; i <= 0; i++ {
+		recs = append(recs, &Export{Time: when + int64(i)*600, Status: argus.CLEAR, Value: 100})
+	}
+
+	n, err := Import("imp", "samples", recs, lay)
+	if err != nil || n != 7 {
+		fmt.Printf("import %d %v\n", n, err)
+		t.FailNow()
+	}
+
+	s := Get("imp", "samples", 0, 0)
+	if len(s) != 8 {
+		fmt.Printf("samples %d\n", len(s))
+		t.FailNow()
+	}
+	// oldest dropped, imported value replaces the existing one
+	if s[0].Time != when-4*600 || s[4].Value != 100 || s[5].Value != 1 || s[7].Value != 3 {
+		for _, e := range s {
+			fmt.Printf("  %+v\n", e)
+		}
+		t.Fail()
+	}
+
+	// summaries into a new file
+	n, err = Import("imp2", "days", []*Export{{Time: when, Value: 5, Min: 1, Max: 9}}, nil)
+	d := Get("imp2", "days", 0, 0)
+	if err != nil || n != 1 || len(d) != 1 || d[0].Max != 9 {
+		fmt.Printf("import days %d %v %d\n", n, err, len(d))
+		t.Fail()
+	}
+}
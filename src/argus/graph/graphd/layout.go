@@ -0,0 +1,204 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 21:05 (EDT)
+// Function: retention settings, resize existing files
+
+package graphd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+func ValidConsol(name string) bool {
+
+	for _, n := range consolNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (lay *Layout) consol() uint8 {
+
+	if lay == nil {
+		return CONSOL_AVE
+	}
+	for i, n := range consolNames {
+		if n == lay.Consol {
+			return uint8(i)
+		}
+	}
+	return CONSOL_AVE
+}
+
+func (lay *Layout) resol() uint32 {
+
+	if lay == nil || lay.Resolution < 0 {
+		return 0
+	}
+	return uint32(lay.Resolution)
+}
+
+// samples, hours, days
+func (lay *Layout) sizes() (int32, int32, int32) {
+
+	if lay == nil {
+		return SampNMax, HourNMax, DayNMax
+	}
+	return size(lay.Samples, SampNMax), size(lay.Hours, HourNMax), size(lay.Days, DayNMax)
+}
+
+func size(n int, def int32) int32 {
+
+	if n <= 0 {
+		return def
+	}
+	if n > MaxNMax {
+		return MaxNMax
+	}
+	return int32(n)
+}
+
+// change the retention of an existing file, keeping the most recent data.
+// returns true if the file was rewritten
+func Resize(file string, lay *Layout) (bool, error) {
+
+	if datadir == "" {
+		return false, errors.New("no datadir")
+	}
+
+	lno := lockno(file)
+	locks[lno].Lock()
+	defer locks[lno].Unlock()
+
+	file = filename(file)
+	g := open(file)
+	if g == nil {
+		return false, fmt.Errorf("cannot open %s", file)
+	}
+	defer g.close()
+
+	h := *g.h
+	h.Version = VERSION
+	h.Consol = lay.consol()
+	h.Resol = lay.resol()
+	h.Samp.NMax, h.Hour.NMax, h.Day.NMax = lay.sizes()
+
+	if h.Samp.NMax == g.h.Samp.NMax && h.Hour.NMax == g.h.Hour.NMax && h.Day.NMax == g.h.Day.NMax && g.h.Version == VERSION {
+		// only the header changes
+		g.h = &h
+		g.save()
+		return false, nil
+	}
+
+	dl.Verbose("resizing %s: %d/%d/%d -> %d/%d/%d", file, g.h.Samp.NMax, g.h.Hour.NMax, g.h.Day.NMax,
+		h.Samp.NMax, h.Hour.NMax, h.Day.NMax)
+
+	err := g.rewrite(file, &h)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// older versions have no percentiles in the summaries - upgrade before they are written
+// returns the new file, or the old one if it cannot be upgraded
+func (g *graphData) upgrade(file string) *graphData {
+
+	h := *g.h
+	h.Version = VERSION
+
+	dl.Verbose("upgrading %s to version %d", file, VERSION)
+	err := g.rewrite(file, &h)
+	if err != nil {
+		dl.Problem("cannot upgrade %s: %v", file, err)
+		return g
+	}
+
+	n, err := openFile(file)
+	if err != nil {
+		dl.Problem("cannot open %s: %v", file, err)
+		return g
+	}
+	g.close()
+	return n
+}
+
+// build a new file, and swap it in
+func (g *graphData) rewrite(file string, h *Header) error {
+
+	tmp := file + ".resize"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	n := &graphData{f: f, h: h}
+	n.initHeader()
+	f.Truncate(n.dayStart + n.summSize()*int64(h.Day.NMax))
+
+	err = g.copySect(&g.h.Samp, g.sampStart, SampSize, n, &h.Samp, n.sampStart)
+	if err == nil {
+		g.copySumm(&g.h.Hour, g.hourStart, n, &h.Hour, n.hourStart)
+		g.copySumm(&g.h.Day, g.dayStart, n, &h.Day, n.dayStart)
+	}
+	if err == nil {
+		n.save()
+		err = f.Sync()
+	}
+	n.close()
+
+	if err == nil {
+		err = os.Rename(tmp, file)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
+
+// copy the most recent records that fit into the new section
+func (g *graphData) copySect(hs *HeaderSect, start int64, size int64, n *graphData, nhs *HeaderSect, nstart int64) error {
+
+	pos, num := hs.recent(int(nhs.NMax))
+
+	r := NewCbufReader(g.f, start, int64(hs.NMax)*size)
+	r.Seek(int64(pos) * size)
+
+	buf := make([]byte, int64(num)*size)
+	_, err := io.ReadFull(r, buf)
+	if err != nil {
+		return err
+	}
+
+	n.seek(nstart)
+	_, err = n.f.Write(buf)
+	if err != nil {
+		return err
+	}
+
+	nhs.Idx = int32(num) % nhs.NMax
+	nhs.Count = int32(num)
+	return nil
+}
+
+// summary records may change size between versions, copy one by one
+func (g *graphData) copySumm(hs *HeaderSect, start int64, n *graphData, nhs *HeaderSect, nstart int64) {
+
+	pos, num := hs.recent(int(nhs.NMax))
+
+	r := NewCbufReader(g.f, start, int64(hs.NMax)*g.summSize())
+	r.Seek(int64(pos) * g.summSize())
+
+	n.seek(nstart)
+	for i := 0; i < num; i++ {
+		n.writeSumm(g.readSumm(r))
+	}
+
+	nhs.Idx = int32(num) % nhs.NMax
+	nhs.Count = int32(num)
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 21:40 (EDT)
+// Function:
+
+package graphd
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"argus.domain/argus/argus"
+)
+
+func TestResize(t *testing.T) {
+
+	dir, err := os.MkdirTemp("", "graphd")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Mkdir(dir+"/gdata", 0777)
+	datadir = dir
+
+	lay := &Layout{Samples: 10, Hours: 5, Days: 5, Resolution: 60, Consol: "max"}
+	when := int64(1600000000)
+
+	for i := 0; i < 25; i++ {
+		Add("test", when+int64(i)*600, argus.CLEAR, float64(i), 0, 0, lay)
+	}
+
+	g := open(filename("test"))
+	if g == nil {
+		fmt.Printf("open failed\n")
+		t.FailNow()
+	}
+	if g.h.Samp.NMax != 10 || g.h.Samp.Count != 10 || g.h.Version != VERSION || g.h.Consol != CONSOL_MAX {
+		fmt.Printf("header %+v\n", g.h)
+		t.Fail()
+	}
+	g.close()
+
+	// grow - keep everything
+	did, err := Resize("test", &Layout{Samples: 100, Hours: 50, Days: 5, Resolution: 60})
+	if !did || err != nil {
+		fmt.Printf("resize %v %v\n", did, err)
+		t.FailNow()
+	}
+
+	s := Get("test", "samples", 0, 0)
+	if len(s) != 10 || s[0].Value != 15 || s[9].Value != 24 {
+		fmt.Printf("samples %d\n", len(s))
+		t.Fail()
+	}
+
+	// shrink - keep the most recent
+	Resize("test", &Layout{Samples: 4, Hours: 50, Days: 5, Resolution: 60})
+	s = Get("test", "samples", 0, 0)
+	if len(s) != 4 || s[0].Value != 21 || s[3].Value != 24 {
+		fmt.Printf("samples %d\n", len(s))
+		t.Fail()
+	}
+
+	// keeps going
+	Add("test", when+25*600, argus.CLEAR, 25, 0, 0, lay)
+	s = Get("test", "samples", 0, 0)
+	if len(s) != 4 || s[3].Value != 25 {
+		fmt.Printf("samples %d\n", len(s))
+		t.Fail()
+	}
+}
+
+func TestLegacyHeader(t *testing.T) {
+
+	f, err := os.CreateTemp("", "graphd")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer os.Remove(f.Name())
+
+	g := &graphData{f: f}
+	g.newHeader(nil)
+	copy(g.h.Magic[:], LEGACYMAGIC)
+	g.h.Version = 0
+	g.save()
+	g.close()
+
+	g = open(f.Name())
+	if g == nil {
+		fmt.Printf("legacy open failed\n")
+		t.FailNow()
+	}
+	defer g.close()
+
+	// same layout as version 1. resize upgrades it further
+	if string(g.h.Magic[:]) != MAGIC || g.h.Version != 1 || g.h.Samp.NMax != SampNMax || g.summSize() != SummySize {
+		fmt.Printf("header %+v\n", g.h)
+		t.Fail()
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+
+	dir, err := os.MkdirTemp("", "graphd")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Mkdir(dir+"/gdata", 0777)
+	datadir = dir
+
+	// a version 1 file, with some hourly summaries
+	g := create(filename("up"), nil)
+	g.h.Version = 1
+	g.initHeader()
+	when := int64(1600000000) / 3600 * 3600
+	for i := 0; i < 5*60; i += 10 {
+		g.add(when+int64(i)*60, argus.CLEAR, float64(i), 0, 0)
+	}
+	g.save()
+	g.close()
+
+	h := Get("up", "hours", 0, 0)
+	if len(h) != 4 || h[0].P95 != 0 {
+		fmt.Printf("v1 hours %d\n", len(h))
+		t.FailNow()
+	}
+
+	did, err := Resize("up", nil)
+	if !did || err != nil {
+		fmt.Printf("resize %v %v\n", did, err)
+		t.FailNow()
+	}
+
+	h2 := Get("up", "hours", 0, 0)
+	if len(h2) != 4 || h2[3].Value != h[3].Value || h2[3].Time != h[3].Time {
+		fmt.Printf("v2 hours %d\n", len(h2))
+		t.Fail()
+	}
+
+	g = open(filename("up"))
+	if g == nil || g.h.Version != VERSION {
+		fmt.Printf("not upgraded\n")
+		t.FailNow()
+	}
+	g.close()
+}
+
+func TestNewerVersion(t *testing.T) {
+
+	dir, err := os.MkdirTemp("", "graphd")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Mkdir(dir+"/gdata", 0777)
+	datadir = dir
+
+	lay := &Layout{Samples: 10, Hours: 5, Days: 5}
+	when := int64(1600000000)
+	Add("new", when, argus.CLEAR, 1, 0, 0, lay)
+
+	// written by a newer daemon
+	g := open(filename("new"))
+	g.h.Version = VERSION + 1
+	g.save()
+	g.close()
+	st, _ := os.Stat(filename("new"))
+
+	Add("new", when+60, argus.CLEAR, 2, 0, 0, lay)
+	Import("new", "samples", []*Export{{Time: when + 120, Value: 3}}, lay)
+
+	st2, err := os.Stat(filename("new"))
+	if err != nil || st2.Size() != st.Size() || !st2.ModTime().Equal(st.ModTime()) {
+		fmt.Printf("newer file was clobbered\n")
+		t.Fail()
+	}
+}
+
+func TestUpgradeOnRoll(t *testing.T) {
+
+	dir, err := os.MkdirTemp("", "graphd")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Mkdir(dir+"/gdata", 0777)
+	datadir = dir
+
+	g := create(filename("roll"), nil)
+	g.h.Version = 1
+	g.initHeader()
+	when := int64(1600000000) / 3600 * 3600
+	g.add(when, argus.CLEAR, 1, 0, 0)
+	g.save()
+	g.close()
+
+	// same hour => not yet
+	Add("roll", when+600, argus.CLEAR, 10, 0, 0, nil)
+	if g = open(filename("roll")); g.h.Version != 1 {
+		fmt.Printf("upgraded early\n")
+		t.Fail()
+	}
+	g.close()
+
+	// next hour => upgraded, with percentiles
+	Add("roll", when+3600, argus.CLEAR, 20, 0, 0, nil)
+	g = open(filename("roll"))
+	g.close()
+	h := Get("roll", "hours", 0, 0)
+	if g.h.Version != VERSION || len(h) != 1 || h[0].P50 == 0 {
+		fmt.Printf("not upgraded: v%d %d\n", g.h.Version, len(h))
+		t.Fail()
+	}
+	if s := Get("roll", "samples", 0, 0); len(s) != 3 {
+		fmt.Printf("samples %d\n", len(s))
+		t.Fail()
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-21 11:20 (EDT)
+// Function: compact streaming quantile sketch - log buckets
+
+package graphd
+
+import (
+	"math"
+)
+
+// bucket i holds values in (gamma^(i-1), gamma^i], +/- 4% accuracy.
+// the window of buckets slides up as needed, lower buckets collapse.
+// intended for non-negative values (latency, sizes, ...)
+const (
+	SKETCHBINS  = 124
+	SKETCHGAMMA = 1.08
+)
+
+var lnGamma = math.Log(SKETCHGAMMA)
+
+type Sketch struct {
+	Base int16  // bucket number of Bins[0]
+	Zero uint16 // values <= 0
+	Pad  [4]byte
+	Bins [SKETCHBINS]uint16
+	// total size = 256
+}
+
+// the usual summaries
+var quantiles = []float64{0.50, 0.90, 0.95, 0.99}
+
+func sketchBin(v float32) int {
+	return int(math.Ceil(math.Log(float64(v)) / lnGamma))
+}
+
+// representative value for the bucket
+func sketchValue(i int) float32 {
+	return float32(2 * math.Pow(SKETCHGAMMA, float64(i)) / (SKETCHGAMMA + 1))
+}
+
+func (k *Sketch) count() int {
+
+	n := int(k.Zero)
+	for _, c := range k.Bins {
+		n += int(c)
+	}
+	return n
+}
+
+func (k *Sketch) add(v float32) {
+
+	if math.IsNaN(float64(v)) {
+		return
+	}
+	if v <= 0 {
+		inc(&k.Zero)
+		return
+	}
+
+	i := sketchBin(v)
+
+	if k.count() == int(k.Zero) {
+		// first value - center the window on it
+		k.Base = int16(i - SKETCHBINS/2)
+	}
+
+	if i >= int(k.Base)+SKETCHBINS {
+		// slide up, collapse the lowest buckets together
+		d := i - (int(k.Base) + SKETCHBINS - 1)
+		var bins [SKETCHBINS]uint16
+
+		for j, c := range k.Bins {
+			n := j - d
+			if n < 0 {
+				n = 0
+			}
+			bins[n] = sat(int(bins[n]) + int(c))
+		}
+		k.Bins = bins
+		k.Base += int16(d)
+	}
+
+	j := i - int(k.Base)
+	if j < 0 {
+		j = 0
+	}
+	inc(&k.Bins[j])
+}
+
+func (k *Sketch) quantile(q float64) float32 {
+
+	n := k.count()
+	if n == 0 {
+		return 0
+	}
+
+	rank := int(q * float64(n-1))
+	if rank < int(k.Zero) {
+		return 0
+	}
+	rank -= int(k.Zero)
+
+	for j, c := range k.Bins {
+		if rank < int(c) {
+			return sketchValue(j + int(k.Base))
+		}
+		rank -= int(c)
+	}
+
+	return sketchValue(SKETCHBINS - 1 + int(k.Base))
+}
+
+func (k *Sketch) reset() {
+	*k = Sketch{}
+}
+
+func inc(c *uint16) {
+	if *c < math.MaxUint16 {
+		*c++
+	}
+}
+
+func sat(n int) uint16 {
+	if n > math.MaxUint16 {
+		return math.MaxUint16
+	}
+	return uint16(n)
+}
+
+// ################################################################
+
+// from the current (in progress) hour or day
+func Quantile(file string, which string, q float64) (float64, bool) {
+
+	if datadir == "" {
+		return 0, false
+	}
+
+	lno := lockno(file)
+	locks[lno].RLock()
+	defer locks[lno].RUnlock()
+
+	g := open(filename(file))
+	if g == nil {
+		return 0, false
+	}
+	defer g.close()
+
+	var k *Sketch
+	switch which {
+	case "hours":
+		k = &g.h.HourQ
+	case "days":
+		k = &g.h.DayQ
+	default:
+		return 0, false
+	}
+
+	if k.count() == 0 {
+		return 0, false
+	}
+	return float64(k.quantile(q)), true
+}
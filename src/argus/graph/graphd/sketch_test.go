@@ -0,0 +1,87 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-21 12:10 (EDT)
+// Function:
+
+package graphd
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"testing"
+
+	"argus.domain/argus/argus"
+)
+
+func TestSketch(t *testing.T) {
+
+	if binary.Size(Sketch{}) != 256 {
+		fmt.Printf("sketch size %d\n", binary.Size(Sketch{}))
+		t.Fail()
+	}
+
+	var k Sketch
+
+	// 1 .. 1000
+	for i := 1; i <= 1000; i++ {
+		k.add(float32(i))
+	}
+
+	for _, q := range []float64{0.5, 0.9, 0.95, 0.99} {
+		exp := q * 1000
+		got := float64(k.quantile(q))
+		if math.Abs(got-exp)/exp > 0.05 {
+			fmt.Printf("q %g => %g, expected %g\n", q, got, exp)
+			t.Fail()
+		}
+	}
+
+	// slide the window up, the upper quantiles are still good
+	k.reset()
+	k.add(1)
+	for i := 0; i < 100; i++ {
+		k.add(1e6)
+	}
+	if got := k.quantile(0.95); math.Abs(float64(got)-1e6)/1e6 > 0.05 {
+		fmt.Printf("slid => %g\n", got)
+		t.Fail()
+	}
+
+	k.reset()
+	k.add(0)
+	k.add(-5)
+	if k.count() != 2 || k.quantile(0.5) != 0 {
+		fmt.Printf("zero => %d %g\n", k.count(), k.quantile(0.5))
+		t.Fail()
+	}
+}
+
+func TestQuantileSummary(t *testing.T) {
+
+	dir, err := os.MkdirTemp("", "graphd")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Mkdir(dir+"/gdata", 0777)
+	datadir = dir
+
+	// 2 hours of samples, once a minute
+	when := int64(1600000000) / 3600 * 3600
+	for i := 0; i < 120; i++ {
+		Add("q", when+int64(i)*60, argus.CLEAR, float64(i%60+1), 0, 0, nil)
+	}
+
+	if p, ok := Quantile("q", "hours", 0.95); !ok || math.Abs(p-57)/57 > 0.05 {
+		fmt.Printf("running p95 %g %v\n", p, ok)
+		t.Fail()
+	}
+
+	h := Get("q", "hours", 0, 0)
+	if len(h) != 1 || math.Abs(float64(h[0].P95)-57)/57 > 0.05 || h[0].P50 > h[0].P90 {
+		fmt.Printf("hours %d %+v\n", len(h), h)
+		t.Fail()
+	}
+}
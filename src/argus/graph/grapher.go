@@ -0,0 +1,253 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 19:10 (EDT)
+// Function: external graphing backends - graphite, influxdb, opentsdb
+
+package graph
+
+import (
+	"bytes"
+	"expvar"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"argus.domain/argus/configure"
+	"github.com/jaw0/acdiag"
+)
+
+// grapher NAME {
+//     type:     graphite
+//     addr:     graphite.example.com:2003
+//     template: argus.{{metric .Object}}
+// }
+
+type GrapherConf struct {
+	Type     string // graphite, influxdb, opentsdb
+	Addr     string // host:port
+	Url      string // influxdb over http
+	Protocol string // tcp, udp, http
+	Template string // metric name
+	Queue    int    // buffer this many samples while the sink is down
+	Batch    int    // send this many at once
+	Backoff  int64  `cfconv:"timespec"` // maximum retry delay
+	Timeout  int64  `cfconv:"timespec"`
+}
+
+// the backend specific part
+type sink interface {
+	format(*Sample, string) string
+	send([]string) error
+	close()
+}
+
+type external struct {
+	Name   string
+	cf     GrapherConf
+	sink   sink
+	tmpl   *template.Template
+	queue  chan *Sample
+	sent   *expvar.Int
+	drops  *expvar.Int
+	errors *expvar.Int
+}
+
+var dl = diag.Logger("graph")
+
+var grapherdefaults = GrapherConf{
+	Queue:   10000,
+	Batch:   100,
+	Backoff: 300,
+	Timeout: 10,
+}
+
+var templFuncs = template.FuncMap{
+	"metric": metricName,
+	"clean":  cleanName,
+}
+
+func NewGrapher(conf *configure.CF) error {
+
+	g := &external{Name: conf.Name, cf: grapherdefaults}
+	conf.InitFromConfig(&g.cf, "grapher", "")
+
+	var err error
+	deftmpl := "argus.{{metric .Object}}"
+
+	switch g.cf.Type {
+	case "graphite":
+		g.sink, err = newGraphite(&g.cf)
+	case "influx", "influxdb":
+		g.sink, err = newInflux(&g.cf)
+		deftmpl = "argus" // measurement. the object is a tag
+	case "opentsdb", "tsdb":
+		g.sink, err = newOpenTSDB(&g.cf)
+	default:
+		return fmt.Errorf("Invalid Grapher - unknown type '%s'", g.cf.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("Invalid Grapher: %v", err)
+	}
+
+	if g.cf.Template == "" {
+		g.cf.Template = deftmpl
+	}
+	g.tmpl, err = template.New(g.Name).Funcs(templFuncs).Parse(g.cf.Template)
+	if err != nil {
+		return fmt.Errorf("Invalid Grapher template: %v", err)
+	}
+
+	if g.cf.Queue < 1 {
+		g.cf.Queue = 1
+	}
+	if g.cf.Batch < 1 {
+		g.cf.Batch = 1
+	}
+
+	glock.Lock()
+	defer glock.Unlock()
+
+	for _, o := range graphers {
+		if x, ok := o.(*external); ok && x.Name == g.Name {
+			return fmt.Errorf("Duplicate Grapher '%s'", g.Name)
+		}
+	}
+
+	conf.CheckTypos()
+	g.queue = make(chan *Sample, g.cf.Queue)
+	g.sent = expvar.NewInt("grapher_" + g.Name + "_sent")
+	g.drops = expvar.NewInt("grapher_" + g.Name + "_drops")
+	g.errors = expvar.NewInt("grapher_" + g.Name + "_errors")
+	graphers = append(graphers, g)
+
+	go g.worker()
+	return nil
+}
+
+func (g *external) Add(s *Sample) {
+
+	// drop if queue full
+	select {
+	case g.queue <- s:
+	default:
+		g.drops.Add(1)
+	}
+}
+
+func (g *external) worker() {
+
+	var batch []string
+	backoff := int64(0)
+
+	for {
+		// wait for something to send
+		if len(batch) == 0 {
+			batch = g.format(batch, <-g.queue)
+		}
+
+		// gather up more
+	gather:
+		for len(batch) < g.cf.Batch {
+			select {
+			case s := <-g.queue:
+				batch = g.format(batch, s)
+			default:
+				break gather
+			}
+		}
+
+		if len(batch) == 0 {
+			continue
+		}
+
+		err := g.sink.send(batch)
+
+		if err == nil {
+			g.sent.Add(int64(len(batch)))
+			batch = nil
+			backoff = 0
+			continue
+		}
+
+		// sink is down - hold on to the batch, and retry after a while
+		g.errors.Add(1)
+		g.sink.close()
+
+		if backoff == 0 {
+			dl.Verbose("grapher %s: %v", g.Name, err)
+		}
+		backoff = nextBackoff(backoff, g.cf.Backoff)
+		dl.Debug("grapher %s: retry in %d sec", g.Name, backoff)
+		time.Sleep(time.Duration(backoff) * time.Second)
+	}
+}
+
+// 1, 2, 4, ... max
+func nextBackoff(prev int64, max int64) int64 {
+
+	next := prev * 2
+	if next == 0 {
+		next = 1
+	}
+	if next > max {
+		next = max
+	}
+	if next < 1 {
+		next = 1
+	}
+	return next
+}
+
+// add the formatted sample to the batch
+func (g *external) format(batch []string, s *Sample) []string {
+
+	var buf bytes.Buffer
+
+	err := g.tmpl.Execute(&buf, s)
+	if err != nil {
+		dl.Debug("grapher %s: template: %v", g.Name, err)
+		return batch
+	}
+
+	return append(batch, g.sink.format(s, buf.String()))
+}
+
+// ################################################################
+
+var reNotMetric = regexp.MustCompile(`[^A-Za-z0-9_\-]+`)
+
+// Top:Foo:Bar baz => Foo.Bar_baz
+func metricName(obj string) string {
+
+	obj = strings.TrimPrefix(obj, "Top:")
+
+	parts := strings.Split(obj, ":")
+	for i, p := range parts {
+		parts[i] = cleanName(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+func cleanName(s string) string {
+	return reNotMetric.ReplaceAllString(s, "_")
+}
+
+// object tags of the form key=value, plus object + darp
+func sampleTags(s *Sample) [][2]string {
+
+	tags := [][2]string{{"object", s.Object}}
+	if s.Darp != "" {
+		tags = append(tags, [2]string{"darp", s.Darp})
+	}
+
+	for _, t := range strings.Fields(s.Tags) {
+		kv := strings.SplitN(t, "=", 2)
+		if len(kv) == 2 && kv[0] != "" && kv[1] != "" {
+			tags = append(tags, [2]string{kv[0], kv[1]})
+		}
+	}
+
+	return tags
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 20:30 (EDT)
+// Function:
+
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestMetricName(t *testing.T) {
+
+	tests := map[string]string{
+		"Top:Foo:Bar":          "Foo.Bar",
+		"Top:Foo:Ping web.com": "Foo.Ping_web_com",
+		"Top":                  "Top",
+	}
+
+	for in, exp := range tests {
+		if got := metricName(in); got != exp {
+			fmt.Printf("metric %s => %s, expected %s\n", in, got, exp)
+			t.Fail()
+		}
+	}
+}
+
+func TestGrapherFormat(t *testing.T) {
+
+	s := &Sample{Object: "Top:Foo:Bar", Tags: "web env=prod x", Darp: "east", When: 1000, Value: 2.5, Status: 2}
+
+	x := &influx{}
+	got := x.format(s, "argus m")
+	exp := `argus\ m,object=Top:Foo:Bar,darp=east,env=prod value=2.5,status=2i 1000000000000`
+	if got != exp {
+		fmt.Printf("influx:\n got %s\n exp %s\n", got, exp)
+		t.Fail()
+	}
+
+	o := &openTSDB{}
+	got = o.format(s, "argus.Foo.Bar")
+	exp = "put argus.Foo.Bar 1000 2.5 object=Top_Foo_Bar darp=east env=prod"
+	if got != exp {
+		fmt.Printf("opentsdb:\n got %s\n exp %s\n", got, exp)
+		t.Fail()
+	}
+
+	g := &graphite{}
+	got = g.format(s, "argus.Foo.Bar")
+	exp = "argus.Foo.Bar 2.5 1000"
+	if got != exp {
+		fmt.Printf("graphite:\n got %s\n exp %s\n", got, exp)
+		t.Fail()
+	}
+}
+
+func TestBackoff(t *testing.T) {
+
+	b := int64(0)
+	for _, exp := range []int64{1, 2, 4, 8, 10, 10} {
+		b = nextBackoff(b, 10)
+		if b != exp {
+			fmt.Printf("backoff %d, expected %d\n", b, exp)
+			t.Fail()
+		}
+	}
+}
+
+func TestGraphiteSend(t *testing.T) {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer ln.Close()
+
+	res := make(chan string, 2)
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		r := bufio.NewScanner(c)
+		for r.Scan() {
+			res <- r.Text()
+		}
+	}()
+
+	gs, err := newGraphite(&GrapherConf{Addr: ln.Addr().String(), Timeout: 5})
+	if err != nil {
+		fmt.Printf("error %v\n", err)
+		t.FailNow()
+	}
+	defer gs.close()
+
+	err = gs.send([]string{"a.b 1 1000", "a.c 2 1000"})
+	if err != nil {
+		fmt.Printf("send %v\n", err)
+		t.FailNow()
+	}
+
+	for _, exp := range []string{"a.b 1 1000", "a.c 2 1000"} {
+		if got := <-res; got != exp {
+			fmt.Printf("got %s, expected %s\n", got, exp)
+			t.Fail()
+		}
+	}
+}
+
+type testGrapher struct {
+	got []*Sample
+}
+
+func (g *testGrapher) Add(s *Sample) {
+	g.got = append(g.got, s)
+}
+
+func TestAddGraphers(t *testing.T) {
+
+	local := &testGrapher{}
+	ext := &testGrapher{}
+
+	SetLocal(local)
+	graphers = append(graphers, ext)
+	defer func() {
+		graphers = []Grapher{graphdGrapher{}}
+	}()
+
+	Add(&Sample{Path: "T/a", Value: 1})
+	AddLocal(&Sample{Path: "T/a", Value: 2})
+
+	if len(local.got) != 2 || len(ext.got) != 1 || ext.got[0].Value != 1 {
+		fmt.Printf("local %d, external %d\n", len(local.got), len(ext.got))
+		t.Fail()
+	}
+}
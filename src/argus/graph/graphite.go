@@ -0,0 +1,110 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 19:40 (EDT)
+// Function: graphite plaintext protocol
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// NB - udp datagrams are kept small enough to not fragment
+const UDPMAX = 1400
+
+// send lines of text over tcp or udp. also used by opentsdb + influx/udp
+type netWriter struct {
+	proto   string
+	addr    string
+	timeout time.Duration
+	conn    net.Conn
+}
+
+type graphite struct {
+	netWriter
+}
+
+func newNetWriter(cf *GrapherConf, defport string) (netWriter, error) {
+
+	w := netWriter{
+		proto:   cf.Protocol,
+		addr:    cf.Addr,
+		timeout: time.Duration(cf.Timeout) * time.Second,
+	}
+
+	if w.proto == "" {
+		w.proto = "tcp"
+	}
+	if w.proto != "tcp" && w.proto != "udp" {
+		return w, fmt.Errorf("invalid protocol '%s'", w.proto)
+	}
+	if w.addr == "" {
+		return w, errors.New("addr not specified")
+	}
+	if _, _, err := net.SplitHostPort(w.addr); err != nil {
+		w.addr = net.JoinHostPort(w.addr, defport)
+	}
+
+	return w, nil
+}
+
+func (w *netWriter) send(lines []string) error {
+
+	if w.conn == nil {
+		conn, err := net.DialTimeout(w.proto, w.addr, w.timeout)
+		if err != nil {
+			return err
+		}
+		w.conn = conn
+	}
+
+	w.conn.SetWriteDeadline(time.Now().Add(w.timeout))
+
+	if w.proto == "tcp" {
+		_, err := w.conn.Write([]byte(strings.Join(lines, "\n") + "\n"))
+		return err
+	}
+
+	// udp - as few datagrams as possible
+	var buf []byte
+	for _, l := range lines {
+		if len(buf) != 0 && len(buf)+len(l)+1 > UDPMAX {
+			if _, err := w.conn.Write(buf); err != nil {
+				return err
+			}
+			buf = nil
+		}
+		buf = append(buf, l...)
+		buf = append(buf, '\n')
+	}
+	_, err := w.conn.Write(buf)
+	return err
+}
+
+func (w *netWriter) close() {
+
+	if w.conn != nil {
+		w.conn.Close()
+		w.conn = nil
+	}
+}
+
+// ################################################################
+
+func newGraphite(cf *GrapherConf) (sink, error) {
+
+	w, err := newNetWriter(cf, "2003")
+	if err != nil {
+		return nil, err
+	}
+	return &graphite{w}, nil
+}
+
+// metric value timestamp
+func (g *graphite) format(s *Sample, name string) string {
+	return fmt.Sprintf("%s %g %d", name, s.Value, s.When)
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 20:10 (EDT)
+// Function: influxdb line protocol, over http or udp
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// url: http://influx.example.com:8086/write?db=argus
+// or   protocol: udp; addr: influx.example.com:8089
+type influx struct {
+	netWriter
+	url    string
+	client *http.Client
+}
+
+var influxEscTag = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+var influxEscName = strings.NewReplacer(",", `\,`, " ", `\ `)
+
+func newInflux(cf *GrapherConf) (sink, error) {
+
+	if cf.Protocol == "udp" {
+		w, err := newNetWriter(cf, "8089")
+		if err != nil {
+			return nil, err
+		}
+		return &influx{netWriter: w}, nil
+	}
+
+	if cf.Protocol != "" && cf.Protocol != "http" {
+		return nil, fmt.Errorf("invalid protocol '%s'", cf.Protocol)
+	}
+	if cf.Url == "" {
+		return nil, errors.New("url not specified")
+	}
+
+	return &influx{
+		url:    cf.Url,
+		client: &http.Client{Timeout: time.Duration(cf.Timeout) * time.Second},
+	}, nil
+}
+
+// measurement,tag=v,... value=1.5,status=1i,... timestamp(ns)
+func (x *influx) format(s *Sample, name string) string {
+
+	var b strings.Builder
+
+	b.WriteString(influxEscName.Replace(name))
+	for _, kv := range sampleTags(s) {
+		fmt.Fprintf(&b, ",%s=%s", influxEscTag.Replace(kv[0]), influxEscTag.Replace(kv[1]))
+	}
+
+	fmt.Fprintf(&b, " value=%g,status=%di", s.Value, int(s.Status))
+	if s.Yn != 0 || s.Dn != 0 {
+		fmt.Fprintf(&b, ",expected=%g,deviation=%g", s.Yn, s.Dn)
+	}
+	fmt.Fprintf(&b, " %d", s.When*1000000000)
+
+	return b.String()
+}
+
+func (x *influx) send(lines []string) error {
+
+	if x.url == "" {
+		return x.netWriter.send(lines)
+	}
+
+	res, err := x.client.Post(x.url, "text/plain; charset=utf-8", strings.NewReader(strings.Join(lines, "\n")+"\n"))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(ioutil.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("influxdb: %s", res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 19:55 (EDT)
+// Function: opentsdb telnet protocol
+
+package graph
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type openTSDB struct {
+	netWriter
+}
+
+// opentsdb permits: a-z, A-Z, 0-9, -, _, ., /
+var reNotTSDB = regexp.MustCompile(`[^A-Za-z0-9_\-./]+`)
+
+func newOpenTSDB(cf *GrapherConf) (sink, error) {
+
+	if cf.Protocol == "udp" {
+		return nil, fmt.Errorf("opentsdb requires tcp")
+	}
+
+	w, err := newNetWriter(cf, "4242")
+	if err != nil {
+		return nil, err
+	}
+	return &openTSDB{w}, nil
+}
+
+// put metric timestamp value tagk=tagv ...
+func (o *openTSDB) format(s *Sample, name string) string {
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "put %s %d %g", reNotTSDB.ReplaceAllString(name, "_"), s.When, s.Value)
+
+	for _, kv := range sampleTags(s) {
+		fmt.Fprintf(&b, " %s=%s", reNotTSDB.ReplaceAllString(kv[0], "_"), reNotTSDB.ReplaceAllString(kv[1], "_"))
+	}
+
+	return b.String()
+}
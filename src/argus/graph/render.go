@@ -0,0 +1,355 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 22:15 (EDT)
+// Function: render graphs server-side, as svg or png
+
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"time"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/graph/graphd"
+)
+
+type ImageConf struct {
+	Title  string
+	YLabel string
+	Width  int
+	Height int
+	Start  int64
+	End    int64
+	Which  string // samples, hours, days. default: based on the range
+}
+
+// lighter versions of the status colors on the web pages
+var statusShade = map[argus.Status]color.RGBA{
+	argus.WARNING:  {0xCC, 0xEE, 0xFF, 0xFF},
+	argus.MINOR:    {0xFF, 0xFF, 0xAA, 0xFF},
+	argus.MAJOR:    {0xFF, 0xDD, 0xAA, 0xFF},
+	argus.CRITICAL: {0xFF, 0xCC, 0xCC, 0xFF},
+}
+
+var (
+	colorBg     = color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	colorAxis   = color.RGBA{0x88, 0x88, 0x88, 0xFF}
+	colorLine   = color.RGBA{0x22, 0x44, 0xCC, 0xFF}
+	colorHwab   = color.RGBA{0xBB, 0xDD, 0xBB, 0xFF}
+	colorMinMax = color.RGBA{0xDD, 0xDD, 0xDD, 0xFF}
+)
+
+type plot struct {
+	ic    *ImageConf
+	data  []*graphd.Export
+	summy bool
+	hwab  bool
+	x0    int // plot area, y0 is the top
+	y0    int
+	x1    int
+	y1    int
+	lo    float64
+	hi    float64
+}
+
+func Image(w io.Writer, format string, file string, ic *ImageConf) error {
+
+	if ic.End <= ic.Start || ic.Width < 10 || ic.Height < 10 {
+		return fmt.Errorf("invalid image size or range")
+	}
+
+	which := ic.Which
+	if which == "" {
+		which = pickWhich(ic.End - ic.Start)
+	}
+
+	var data []*graphd.Export
+	for _, e := range graphd.Get(file, which, ic.Start, 0) {
+		if e.Time <= ic.End {
+			data = append(data, e)
+		}
+	}
+
+	p := &plot{ic: ic, data: data, summy: which != "samples"}
+
+	switch format {
+	case "svg":
+		p.layout(60, 10, 24, 20)
+		return p.svg(w)
+	case "png":
+		p.layout(2, 2, 2, 2)
+		return p.png(w)
+	}
+	return fmt.Errorf("unknown format '%s'", format)
+}
+
+func pickWhich(dt int64) string {
+
+	switch {
+	case dt <= 2*86400:
+		return "samples"
+	case dt <= 60*86400:
+		return "hours"
+	}
+	return "days"
+}
+
+// determine the plot area + y range
+func (p *plot) layout(left, right, top, bottom int) {
+
+	p.x0 = left
+	p.x1 = p.ic.Width - right
+	p.y0 = top
+	p.y1 = p.ic.Height - bottom
+
+	p.lo = math.Inf(1)
+	p.hi = math.Inf(-1)
+
+	for _, e := range p.data {
+		p.extend(float64(e.Value))
+		if e.Delt != 0 {
+			p.hwab = true
+			p.extend(float64(e.Exp - e.Delt))
+			p.extend(float64(e.Exp + e.Delt))
+		}
+		if p.summy {
+			p.extend(float64(e.Min))
+			p.extend(float64(e.Max))
+		}
+	}
+
+	if len(p.data) == 0 {
+		p.lo, p.hi = 0, 1
+	}
+	if p.lo > 0 {
+		p.lo = 0
+	}
+	if p.hi <= p.lo {
+		p.hi = p.lo + 1
+	}
+}
+
+func (p *plot) extend(v float64) {
+
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return
+	}
+	if v < p.lo {
+		p.lo = v
+	}
+	if v > p.hi {
+		p.hi = v
+	}
+}
+
+func (p *plot) xpos(t int64) float64 {
+	return float64(p.x0) + float64(t-p.ic.Start)*float64(p.x1-p.x0)/float64(p.ic.End-p.ic.Start)
+}
+
+func (p *plot) ypos(v float32) float64 {
+	return float64(p.y1) - (float64(v)-p.lo)*float64(p.y1-p.y0)/(p.hi-p.lo)
+}
+
+// each point covers the time since the previous one
+func (p *plot) shading(f func(x0, x1 float64, c color.RGBA)) {
+
+	pt := p.ic.Start
+	for _, e := range p.data {
+		if c, ok := statusShade[e.Status]; ok {
+			f(p.xpos(pt), p.xpos(e.Time), c)
+		}
+		pt = e.Time
+	}
+}
+
+// ################################################################
+
+func (p *plot) svg(w io.Writer) error {
+
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="11">`+"\n",
+		p.ic.Width, p.ic.Height)
+	fmt.Fprintf(b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", svgColor(colorBg))
+
+	p.shading(func(x0, x1 float64, c color.RGBA) {
+		fmt.Fprintf(b, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s"/>`+"\n",
+			x0, p.y0, x1-x0, p.y1-p.y0, svgColor(c))
+	})
+
+	if p.summy {
+		p.svgBand(b, colorMinMax, func(e *graphd.Export) (float32, float32) { return e.Min, e.Max })
+	}
+	if p.hwab {
+		p.svgBand(b, colorHwab, func(e *graphd.Export) (float32, float32) { return e.Exp - e.Delt, e.Exp + e.Delt })
+	}
+
+	if len(p.data) != 0 {
+		fmt.Fprintf(b, `<polyline fill="none" stroke="%s" stroke-width="1.5" points="`, svgColor(colorLine))
+		for _, e := range p.data {
+			fmt.Fprintf(b, "%.1f,%.1f ", p.xpos(e.Time), p.ypos(e.Value))
+		}
+		fmt.Fprintf(b, "\"/>\n")
+	}
+
+	// axes + labels
+	fmt.Fprintf(b, `<polyline fill="none" stroke="%s" points="%d,%d %d,%d %d,%d"/>`+"\n",
+		svgColor(colorAxis), p.x0, p.y0, p.x0, p.y1, p.x1, p.y1)
+
+	for _, v := range []float64{p.lo, (p.lo + p.hi) / 2, p.hi} {
+		fmt.Fprintf(b, `<text x="%d" y="%.1f" text-anchor="end" dominant-baseline="middle">%.4g</text>`+"\n",
+			p.x0-4, p.ypos(float32(v)), v)
+	}
+
+	tfmt := "15:04"
+	if p.ic.End-p.ic.Start > 2*86400 {
+		tfmt = "Jan 02"
+	}
+	for i, anchor := range []string{"start", "middle", "end"} {
+		t := p.ic.Start + int64(i)*(p.ic.End-p.ic.Start)/2
+		fmt.Fprintf(b, `<text x="%.1f" y="%d" text-anchor="%s">%s</text>`+"\n",
+			p.xpos(t), p.ic.Height-5, anchor, time.Unix(t, 0).Format(tfmt))
+	}
+
+	if p.ic.Title != "" {
+		fmt.Fprintf(b, `<text x="%d" y="16" text-anchor="middle" font-size="13">%s</text>`+"\n",
+			p.ic.Width/2, html.EscapeString(p.ic.Title))
+	}
+	if p.ic.YLabel != "" {
+		fmt.Fprintf(b, `<text x="12" y="%d" text-anchor="middle" transform="rotate(-90 12 %d)">%s</text>`+"\n",
+			(p.y0+p.y1)/2, (p.y0+p.y1)/2, html.EscapeString(p.ic.YLabel))
+	}
+
+	fmt.Fprintf(b, "</svg>\n")
+	return b.Flush()
+}
+
+func (p *plot) svgBand(b io.Writer, c color.RGBA, f func(*graphd.Export) (float32, float32)) {
+
+	if len(p.data) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, `<polygon fill="%s" stroke="none" points="`, svgColor(c))
+	for _, e := range p.data {
+		_, hi := f(e)
+		fmt.Fprintf(b, "%.1f,%.1f ", p.xpos(e.Time), p.ypos(hi))
+	}
+	for i := len(p.data) - 1; i >= 0; i-- {
+		lo, _ := f(p.data[i])
+		fmt.Fprintf(b, "%.1f,%.1f ", p.xpos(p.data[i].Time), p.ypos(lo))
+	}
+	fmt.Fprintf(b, "\"/>\n")
+}
+
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// ################################################################
+
+// NB - no text. we do not have fonts
+func (p *plot) png(w io.Writer) error {
+
+	img := image.NewRGBA(image.Rect(0, 0, p.ic.Width, p.ic.Height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBg}, image.Point{}, draw.Src)
+
+	p.shading(func(x0, x1 float64, c color.RGBA) {
+		r := image.Rect(int(x0), p.y0, int(math.Ceil(x1)), p.y1)
+		draw.Draw(img, r, &image.Uniform{c}, image.Point{}, draw.Src)
+	})
+
+	if p.summy {
+		p.pngBand(img, colorMinMax, func(e *graphd.Export) (float32, float32) { return e.Min, e.Max })
+	}
+	if p.hwab {
+		p.pngBand(img, colorHwab, func(e *graphd.Export) (float32, float32) { return e.Exp - e.Delt, e.Exp + e.Delt })
+	}
+
+	for i := 1; i < len(p.data); i++ {
+		a, b := p.data[i-1], p.data[i]
+		pngLine(img, p.xpos(a.Time), p.ypos(a.Value), p.xpos(b.Time), p.ypos(b.Value), colorLine)
+	}
+
+	pngLine(img, float64(p.x0), float64(p.y0), float64(p.x0), float64(p.y1), colorAxis)
+	pngLine(img, float64(p.x0), float64(p.y1), float64(p.x1), float64(p.y1), colorAxis)
+
+	return png.Encode(w, img)
+}
+
+// fill vertically between lo + hi, interpolating between points
+func (p *plot) pngBand(img *image.RGBA, c color.RGBA, f func(*graphd.Export) (float32, float32)) {
+
+	b := img.Bounds()
+
+	for i := 1; i < len(p.data); i++ {
+		xa, xb := p.xpos(p.data[i-1].Time), p.xpos(p.data[i].Time)
+		loa, hia := f(p.data[i-1])
+		lob, hib := f(p.data[i])
+		ya0, ya1 := p.ypos(hia), p.ypos(loa)
+		yb0, yb1 := p.ypos(hib), p.ypos(lob)
+
+		if !finite(xa, xb, ya0, ya1, yb0, yb1) {
+			continue
+		}
+
+		for x := clampInt(xa, b.Min.X, b.Max.X-1); x <= clampInt(xb, b.Min.X, b.Max.X-1); x++ {
+			k := 0.0
+			if xb > xa {
+				k = (float64(x) - xa) / (xb - xa)
+			}
+			top := ya0 + (yb0-ya0)*k
+			bot := ya1 + (yb1-ya1)*k
+			for y := clampInt(top, b.Min.Y, b.Max.Y-1); y <= clampInt(bot, b.Min.Y, b.Max.Y-1); y++ {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+func pngLine(img *image.RGBA, x0, y0, x1, y1 float64, c color.RGBA) {
+
+	if !finite(x0, y0, x1, y1) {
+		return
+	}
+
+	b := img.Bounds()
+	x0 = clamp(x0, float64(b.Min.X), float64(b.Max.X-1))
+	x1 = clamp(x1, float64(b.Min.X), float64(b.Max.X-1))
+	y0 = clamp(y0, float64(b.Min.Y), float64(b.Max.Y-1))
+	y1 = clamp(y1, float64(b.Min.Y), float64(b.Max.Y-1))
+
+	n := int(math.Max(math.Abs(x1-x0), math.Abs(y1-y0))) + 1
+
+	for i := 0; i <= n; i++ {
+		k := float64(i) / float64(n)
+		img.SetRGBA(int(x0+(x1-x0)*k+0.5), int(y0+(y1-y0)*k+0.5), c)
+	}
+}
+
+// Inf or NaN samples cannot be drawn
+func finite(vs ...float64) bool {
+
+	for _, v := range vs {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
+
+func clampInt(v float64, lo, hi int) int {
+	return int(clamp(v, float64(lo), float64(hi)))
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 23:50 (EDT)
+// Function:
+
+package graph
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/graph/graphd"
+)
+
+func testPlot() *plot {
+
+	ic := &ImageConf{Title: "Test <1>", Width: 300, Height: 100, Start: 1000, End: 1000 + 60*100}
+	p := &plot{ic: ic}
+
+	for i := 1; i <= 100; i++ {
+		e := &graphd.Export{Time: 1000 + int64(i)*60, Value: float32(i % 10), Exp: 5, Delt: 2}
+		if i > 80 {
+			e.Status = argus.CRITICAL
+		}
+		p.data = append(p.data, e)
+	}
+	return p
+}
+
+func TestRenderSVG(t *testing.T) {
+
+	p := testPlot()
+	p.layout(60, 10, 24, 20)
+
+	if !p.hwab || p.lo != 0 || p.hi != 9 {
+		fmt.Printf("layout: hwab %v, %g - %g\n", p.hwab, p.lo, p.hi)
+		t.Fail()
+	}
+
+	var buf bytes.Buffer
+	p.svg(&buf)
+	svg := buf.String()
+
+	for _, want := range []string{"<polyline", "<polygon", "#FFCCCC", "Test &lt;1&gt;", "</svg>"} {
+		if !strings.Contains(svg, want) {
+			fmt.Printf("svg missing %s\n", want)
+			t.Fail()
+		}
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+
+	p := testPlot()
+	p.layout(2, 2, 2, 2)
+
+	var buf bytes.Buffer
+	err := p.png(&buf)
+	if err != nil {
+		fmt.Printf("png: %v\n", err)
+		t.FailNow()
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil || img.Bounds().Dx() != 300 || img.Bounds().Dy() != 100 {
+		fmt.Printf("png decode: %v\n", err)
+		t.FailNow()
+	}
+
+	// shaded near the end, not at the start
+	r, _, _, _ := img.At(290, 5).RGBA()
+	g, _, _, _ := img.At(10, 5).RGBA()
+	if r>>8 != 0xFF || g>>8 != 0xFF {
+		fmt.Printf("shading %x %x\n", r, g)
+		t.Fail()
+	}
+	_, gr, _, _ := img.At(290, 5).RGBA()
+	if gr>>8 != 0xCC {
+		fmt.Printf("shading %x\n", gr)
+		t.Fail()
+	}
+}
+
+func TestRenderPNGInf(t *testing.T) {
+
+	p := testPlot()
+	p.summy = true
+	for i, e := range p.data {
+		e.Min, e.Max = 0, 9
+		switch i {
+		case 10:
+			e.Value = float32(math.Inf(1))
+			e.Max = float32(math.Inf(1))
+		case 20:
+			e.Value = float32(math.Inf(-1))
+			e.Exp = float32(math.Inf(-1))
+		case 30:
+			e.Value = float32(math.NaN())
+			e.Min = float32(math.NaN())
+		}
+	}
+	p.layout(2, 2, 2, 2)
+
+	if p.lo != 0 || p.hi != 9 {
+		fmt.Printf("layout: %g - %g\n", p.lo, p.hi)
+		t.Fail()
+	}
+
+	done := make(chan error)
+	go func() {
+		var buf bytes.Buffer
+		done <- p.png(&buf)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			fmt.Printf("png: %v\n", err)
+			t.Fail()
+		}
+	case <-time.After(10 * time.Second):
+		fmt.Printf("png did not finish\n")
+		t.FailNow()
+	}
+}
@@ -0,0 +1,336 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-22 15:20 (EDT)
+// Function: durable event history - transitions, overrides, acks, ...
+
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/config"
+	"argus.domain/argus/configure"
+	"argus.domain/argus/sched"
+	"github.com/jaw0/acdiag"
+)
+
+type Conf struct {
+	Keep int64 `cfconv:"timespec"`
+	ACL  string
+}
+
+type Event struct {
+	Id     int64 // unique, increasing
+	When   int64
+	Type   string // transition, override, annotation, ack, snooze, config, darp, ...
+	Obj    string
+	Tags   string
+	User   string
+	Status argus.Status
+	Msg    string
+}
+
+type Query struct {
+	Start int64
+	End   int64
+	Path  string // object prefix
+	Tag   string
+	Type  string // comma separated
+	User  string
+	Limit int
+}
+
+const (
+	DAYFMT   = "2006-01-02"
+	MAXLIMIT = 10000
+	DEFLIMIT = 1000
+)
+
+var dl = diag.Logger("history")
+
+var cf = Conf{
+	Keep: 90 * 24 * 3600,
+	ACL:  "staff root",
+}
+
+var lock sync.Mutex
+var lastId int64
+
+// events are written by the writer, so Add never waits on the disk
+type queued struct {
+	file string
+	e    *Event
+}
+
+var qlock sync.Mutex
+var queue []queued
+var wake = make(chan struct{}, 1)
+
+// flock protects the current file
+var flock sync.Mutex
+var curName string
+var curFile *os.File
+
+var historyCron = sched.NewFunc(&sched.Conf{
+	Freq:  24 * 3600,
+	Phase: 1800,
+	Auto:  true,
+	Text:  "event history cleanup",
+}, cleanup)
+
+func init() {
+	go writer()
+}
+
+func Configure(ccf *configure.CF) {
+	ccf.InitFromConfig(&cf, "history", "history_")
+}
+
+// Stop writes any queued events
+func Stop() {
+	flush()
+}
+
+func Dir() string {
+
+	dir := config.Cf().Datadir
+	if dir == "" {
+		return ""
+	}
+	return dir + "/history"
+}
+
+// ACLPermits - may these users see the history?
+func ACLPermits(groups string) bool {
+	return argus.ACLPermitsUser(cf.ACL, strings.Fields(groups))
+}
+
+// ################################################################
+
+// Add records an event. When + Id are filled in
+// it is written to disk shortly
+func Add(e *Event) {
+
+	lock.Lock()
+	now := clock.Nano()
+	if now <= lastId {
+		now = lastId + 1
+	}
+	lastId = now
+	e.Id = now
+	if e.When == 0 {
+		e.When = now / 1000000000
+	}
+
+	dl.Debug("event %s %s %s", e.Type, e.Obj, e.Msg)
+	publish(e)
+
+	file := ""
+	if dir := Dir(); dir != "" {
+		file = dir + "/" + time.Unix(e.When, 0).Format(DAYFMT)
+	}
+
+	// queue it while locked, so they are written in order
+	qlock.Lock()
+	queue = append(queue, queued{file, e})
+	qlock.Unlock()
+	lock.Unlock()
+
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+func writer() {
+
+	for range wake {
+		flush()
+	}
+}
+
+// write the queued events
+func flush() {
+
+	flock.Lock()
+	defer flock.Unlock()
+
+	qlock.Lock()
+	q := queue
+	queue = nil
+	qlock.Unlock()
+
+	for _, x := range q {
+		f := openFile(x.file)
+		if f == nil {
+			continue
+		}
+
+		js, _ := json.Marshal(x.e)
+		js = append(js, '\n')
+		_, err := f.Write(js)
+		if err != nil {
+			dl.Problem("cannot write history: %v", err)
+		}
+	}
+}
+
+// flock is already held
+func openFile(file string) *os.File {
+
+	if file == curName && curFile != nil {
+		return curFile
+	}
+	if file == "" {
+		return nil
+	}
+
+	if curFile != nil {
+		curFile.Close()
+		curFile = nil
+	}
+
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		dl.Problem("cannot open history file: %v", err)
+		return nil
+	}
+
+	curName = file
+	curFile = f
+	return f
+}
+
+// ################################################################
+
+// Get returns matching events, oldest first
+func Get(q *Query) []*Event {
+
+	dir := Dir()
+	if dir == "" {
+		return nil
+	}
+
+	if q.Limit <= 0 {
+		q.Limit = DEFLIMIT
+	}
+	if q.Limit > MAXLIMIT {
+		q.Limit = MAXLIMIT
+	}
+	if q.End == 0 {
+		q.End = clock.Unix()
+	}
+
+	var res []*Event
+
+	for _, day := range days(q.Start, q.End) {
+		f, err := os.Open(dir + "/" + day)
+		if err != nil {
+			continue
+		}
+
+		scan := bufio.NewScanner(f)
+		scan.Buffer(nil, 1024*1024)
+
+		for scan.Scan() {
+			e := &Event{}
+			if json.Unmarshal(scan.Bytes(), e) != nil {
+				continue
+			}
+			if !q.match(e) {
+				continue
+			}
+			res = append(res, e)
+			if len(res) >= q.Limit {
+				break
+			}
+		}
+		f.Close()
+
+		if len(res) >= q.Limit {
+			break
+		}
+	}
+
+	return res
+}
+
+func (q *Query) match(e *Event) bool {
+
+	if e.When < q.Start || e.When > q.End {
+		return false
+	}
+	if q.Path != "" && !strings.HasPrefix(e.Obj, q.Path) {
+		return false
+	}
+	if q.Tag != "" && !argus.IncludesTag(e.Tags, strings.ToLower(q.Tag), false) {
+		return false
+	}
+	if q.User != "" && e.User != q.User {
+		return false
+	}
+	if q.Type != "" {
+		ok := false
+		for _, t := range strings.Split(q.Type, ",") {
+			if strings.TrimSpace(t) == e.Type {
+				ok = true
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// the day files covering the range
+func days(start, end int64) []string {
+
+	var res []string
+
+	t := time.Unix(start, 0)
+	y, m, d := t.Date()
+	t = time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+
+	for ; t.Unix() <= end; t = t.AddDate(0, 0, 1) {
+		res = append(res, t.Format(DAYFMT))
+	}
+	return res
+}
+
+// ################################################################
+
+// remove day files older than the retention
+func cleanup() {
+
+	dir := Dir()
+	if dir == "" || cf.Keep == 0 {
+		return
+	}
+
+	f, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	files, _ := f.Readdirnames(-1)
+	f.Close()
+
+	sort.Strings(files)
+	limit := time.Unix(clock.Unix()-cf.Keep, 0).Format(DAYFMT)
+
+	for _, file := range files {
+		if len(file) != len(DAYFMT) || file >= limit {
+			continue
+		}
+		dl.Debug("removing %s", file)
+		os.Remove(dir + "/" + file)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-22 17:30 (EDT)
+// Function:
+
+package history
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/config"
+)
+
+func TestHistory(t *testing.T) {
+
+	dir, err := os.MkdirTemp("", "history")
+	if err != nil {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	config.Cf().Datadir = dir
+	os.Mkdir(Dir(), 0777)
+
+	now := time.Now().Unix()
+	yesterday := now - 86400
+
+	Add(&Event{When: yesterday, Type: "transition", Obj: "Top:Foo:Bar", Status: argus.MAJOR, Msg: "down"})
+	Add(&Event{When: now - 60, Type: "override", Obj: "Top:Foo:Bar", User: "bob", Msg: "enabled by bob"})
+	Add(&Event{When: now - 30, Type: "transition", Obj: "Top:Other", Tags: "web prod", Msg: "up"})
+	Add(&Event{When: now - 10, Type: "darp", Tags: "peer1", Msg: "peer peer1 connected"})
+	flush()
+
+	all := Get(&Query{Start: yesterday - 10, End: now})
+	if len(all) != 4 || all[0].Msg != "down" || all[3].Type != "darp" {
+		fmt.Printf("all: %d\n", len(all))
+		t.FailNow()
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].Id <= all[i-1].Id {
+			fmt.Printf("ids not increasing\n")
+			t.Fail()
+		}
+	}
+
+	check := func(name string, q *Query, n int) {
+		if got := Get(q); len(got) != n {
+			fmt.Printf("%s: got %d, expected %d\n", name, len(got), n)
+			t.Fail()
+		}
+	}
+
+	check("range", &Query{Start: now - 3600, End: now}, 3)
+	check("path", &Query{Start: yesterday - 10, End: now, Path: "Top:Foo"}, 2)
+	check("type", &Query{Start: yesterday - 10, End: now, Type: "override,darp"}, 2)
+	check("user", &Query{Start: yesterday - 10, End: now, User: "bob"}, 1)
+	check("tag", &Query{Start: yesterday - 10, End: now, Tag: "prod"}, 1)
+	check("limit", &Query{Start: yesterday - 10, End: now, Limit: 2}, 2)
+}
+
+func TestSubscribe(t *testing.T) {
+
+	config.Cf().Datadir = ""
+
+	Add(&Event{Type: "transition", Obj: "Top:A", Msg: "first"})
+	mark := lastId
+	Add(&Event{Type: "transition", Obj: "Top:A", Msg: "second"})
+
+	sub, replay := Subscribe(mark)
+	if len(replay) != 1 || replay[0].Msg != "second" {
+		fmt.Printf("replay: %d\n", len(replay))
+		t.Fail()
+	}
+
+	Add(&Event{Type: "override", Obj: "Top:B", Msg: "live"})
+	if e := <-sub.C; e.Msg != "live" {
+		fmt.Printf("live: %s\n", e.Msg)
+		t.Fail()
+	}
+
+	// too slow => dropped
+	for i := 0; i <= SUBQUEUE; i++ {
+		Add(&Event{Type: "transition", Obj: "Top:C"})
+	}
+	n := 0
+	for range sub.C {
+		n++
+	}
+	if n != SUBQUEUE {
+		fmt.Printf("queued: %d\n", n)
+		t.Fail()
+	}
+	sub.Close()
+}
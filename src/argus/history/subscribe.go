@@ -0,0 +1,95 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-23 10:15 (EDT)
+// Function: live event subscriptions, for streaming
+
+package history
+
+const (
+	RECENT   = 1000 // events kept in memory for resuming
+	SUBQUEUE = 256
+)
+
+type Subscription struct {
+	C      chan *Event
+	closed bool
+}
+
+var recent []*Event
+var subs = make(map[*Subscription]bool)
+
+// lock is already held
+func publish(e *Event) {
+
+	// keep between RECENT and 2*RECENT
+	recent = append(recent, e)
+	if len(recent) >= 2*RECENT {
+		n := copy(recent, recent[len(recent)-RECENT:])
+		recent = recent[:n]
+	}
+
+	for s := range subs {
+		select {
+		case s.C <- e:
+		default:
+			// too slow - drop them. they can reconnect + resume
+			dl.Verbose("dropping slow subscriber")
+			s.closeL()
+		}
+	}
+}
+
+// Subscribe returns a subscription for new events, plus any events after the id
+// (0 => only new events). the caller must Close the subscription
+func Subscribe(after int64) (*Subscription, []*Event) {
+
+	s := &Subscription{C: make(chan *Event, SUBQUEUE)}
+
+	lock.Lock()
+	subs[s] = true
+	covered := after == 0 || (len(recent) > 0 && recent[0].Id <= after)
+	var replay []*Event
+	for _, e := range recent {
+		if after != 0 && e.Id > after {
+			replay = append(replay, e)
+		}
+	}
+	lock.Unlock()
+
+	if covered {
+		return s, replay
+	}
+
+	// too old for memory, go to disk
+	q := &Query{Start: after / 1000000000, Limit: MAXLIMIT}
+	var old []*Event
+	for _, e := range Get(q) {
+		if e.Id <= after {
+			continue
+		}
+		if len(replay) > 0 && e.Id >= replay[0].Id {
+			break
+		}
+		old = append(old, e)
+	}
+
+	return s, append(old, replay...)
+}
+
+func (s *Subscription) Close() {
+
+	lock.Lock()
+	defer lock.Unlock()
+	s.closeL()
+}
+
+// lock is already held
+func (s *Subscription) closeL() {
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+	delete(subs, s)
+	close(s.C)
+}
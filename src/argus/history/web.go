@@ -0,0 +1,163 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-22 16:45 (EDT)
+// Function: event history - web + api
+
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"argus.domain/argus/api"
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/web"
+)
+
+func init() {
+	web.Add(web.PRIVATE, "/api/history", webHistory)
+	api.Add(true, "history", apiHistory)
+
+	web.AddREST(&web.RESTRoute{
+		Method:  "GET",
+		Path:    "/history",
+		Scope:   "read",
+		Tag:     "history",
+		Summary: "events - transitions, overrides, acks, annotations, notifications, ...",
+		Params: []web.RESTParam{
+			{Name: "start", Type: "string", Desc: "unix time, date, time today (02:00), or ago (2h). default: 1h"},
+			{Name: "end", Type: "string", Desc: "as start. default: now"},
+			{Name: "path", Type: "string", Desc: "objects starting with"},
+			{Name: "tag", Type: "string", Desc: "events with the tag"},
+			{Name: "type", Type: "string", Desc: "comma separated event types"},
+			{Name: "user", Type: "string", Desc: "events by the user"},
+			{Name: "limit", Type: "integer", Desc: "maximum number of results. default: 1000"},
+		},
+		F: restHistory,
+	})
+}
+
+// start, end, path, tag, type, user, limit
+// start + end: unix time, date, time today (02:00), or ago (2h). default: the last hour
+func queryFromArgs(get func(string) string) (*Query, error) {
+
+	now := clock.Unix()
+	q := &Query{
+		Path: get("path"),
+		Tag:  get("tag"),
+		Type: strings.ToLower(get("type")),
+		User: get("user"),
+	}
+
+	var err error
+	q.Start, err = argus.ParseWhen(get("start"), now, now-3600)
+	if err != nil {
+		return nil, err
+	}
+	q.End, err = argus.ParseWhen(get("end"), now, now)
+	if err != nil {
+		return nil, err
+	}
+	if q.End < q.Start {
+		return nil, fmt.Errorf("invalid time range")
+	}
+
+	if l := get("limit"); l != "" {
+		q.Limit, err = strconv.Atoi(l)
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit '%s'", l)
+		}
+	}
+
+	return q, nil
+}
+
+func webHistory(ctx *web.Context) {
+
+	if ctx.User == nil || !ACLPermits(ctx.User.Groups) {
+		ctx.W.WriteHeader(403)
+		return
+	}
+
+	q, err := queryFromArgs(ctx.Get)
+	if err != nil {
+		ctx.W.WriteHeader(400)
+		return
+	}
+
+	res := struct {
+		Start int64
+		End   int64
+		List  []*Event
+	}{q.Start, q.End, Get(q)}
+
+	js, _ := json.MarshalIndent(res, "", "  ")
+	ctx.W.Header().Set("Content-Type", "application/json; charset=utf-8")
+	ctx.W.Write(js)
+}
+
+func restHistory(ctx *web.Context) {
+
+	if !ACLPermits(ctx.User.Groups) {
+		ctx.SendError(403, "not permitted")
+		return
+	}
+
+	q, err := queryFromArgs(ctx.Get)
+	if err != nil {
+		ctx.SendError(400, err.Error())
+		return
+	}
+
+	list := Get(q)
+	if list == nil {
+		list = []*Event{}
+	}
+
+	ctx.SendJSON(200, map[string]interface{}{
+		"start": q.Start,
+		"end":   q.End,
+		"list":  list,
+	})
+}
+
+// argusctl -q history start=02:00 end=02:30 path=Top:Foo type=transition,override
+func apiHistory(ctx *api.Context) {
+
+	q, err := queryFromArgs(func(k string) string { return ctx.Args[k] })
+	if err != nil {
+		ctx.SendResponseFinal(400, err.Error())
+		return
+	}
+
+	list := Get(q)
+	ctx.SendOK()
+
+	if ctx.Args["fmt"] == "json" {
+		js, _ := json.MarshalIndent(list, "", "  ")
+		ctx.Send(string(js) + "\n")
+		ctx.SendFinal()
+		return
+	}
+
+	for _, e := range list {
+		ctx.Send(e.String() + "\n")
+	}
+	ctx.SendFinal()
+}
+
+func (e *Event) String() string {
+
+	s := fmt.Sprintf("%s %-10s", time.Unix(e.When, 0).Format("2006-01-02 15:04:05"), e.Type)
+	if e.Obj != "" {
+		s += fmt.Sprintf(" %s [%s]", e.Obj, e.Status)
+	}
+	if e.User != "" {
+		s += " (" + e.User + ")"
+	}
+	return s + " " + e.Msg
+}
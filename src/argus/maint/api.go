@@ -0,0 +1,247 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 15:48 (EDT)
+// Function: maintenance window web + control api
+
+package maint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"argus.domain/argus/api"
+	"argus.domain/argus/argus"
+	"argus.domain/argus/configure"
+	"argus.domain/argus/web"
+)
+
+type GlobalConf struct {
+	ACL_Maint string
+}
+
+var globalDefaults = GlobalConf{
+	ACL_Maint: "staff root",
+}
+
+func init() {
+	web.Add(web.PRIVATE, "/api/listmaint", webList)
+	web.Add(web.WRITE, "/api/maint", webMaint)
+	api.Add(true, "maintadd", apiAdd)
+	api.Add(true, "maintdel", apiDel)
+	api.Add(true, "maintlist", apiList)
+	api.Add(true, "maintimport", apiImport)
+}
+
+func Configure(cf *configure.CF) {
+	cf.InitFromConfig(&globalDefaults, "maint", "")
+}
+
+// objects, mode, name, comment, cron, rrule, start, duration, tz
+func windowFromArgs(get func(string) string) (*Window, error) {
+
+	w := &Window{
+		Name:    get("name"),
+		Objects: get("objects"),
+		Mode:    get("mode"),
+		Cron:    get("cron"),
+		RRule:   get("rrule"),
+		TZ:      get("tz"),
+		Comment: get("comment"),
+	}
+
+	w.Start, _ = strconv.ParseInt(get("start"), 10, 64)
+
+	if d := get("duration"); d != "" {
+		dur, err := argus.Timespec(d, 60)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration '%s'", d)
+		}
+		w.Duration = dur
+	}
+
+	return w, nil
+}
+
+// newest first
+func list() []Window {
+
+	lock.RLock()
+	defer lock.RUnlock()
+
+	var res []Window
+	for _, w := range windows {
+		res = append(res, *w)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Id > res[j].Id })
+	return res
+}
+
+func (w *Window) recurrence() string {
+
+	switch {
+	case w.Cron != "":
+		return "cron " + w.Cron
+	case w.RRule != "":
+		return "rrule " + w.RRule
+	}
+	return "once"
+}
+
+// ################################################################
+
+func webList(ctx *web.Context) {
+
+	if ctx.User == nil {
+		ctx.W.WriteHeader(403)
+		return
+	}
+
+	creds := strings.Fields(ctx.User.Groups)
+
+	if !argus.ACLPermitsUser(globalDefaults.ACL_Maint, creds) {
+		ctx.W.WriteHeader(403)
+		return
+	}
+
+	d := make(map[string]interface{})
+	d["list"] = list()
+
+	js, _ := json.MarshalIndent(d, "", "  ")
+	ctx.W.Header().Set("Content-Type", "application/json; charset=utf-8")
+	ctx.W.Write(js)
+}
+
+func webMaint(ctx *web.Context) {
+
+	if ctx.User == nil {
+		ctx.W.WriteHeader(403)
+		return
+	}
+
+	creds := strings.Fields(ctx.User.Groups)
+
+	if !argus.ACLPermitsUser(globalDefaults.ACL_Maint, creds) {
+		dl.Debug("denied")
+		ctx.W.WriteHeader(403)
+		return
+	}
+
+	switch {
+	case ctx.Get("remove") != "":
+		id, _ := strconv.Atoi(ctx.Get("remove"))
+		if !Delete(id, ctx.User.Name) {
+			ctx.W.WriteHeader(404)
+			return
+		}
+	case ctx.Get("ics") != "":
+		dflt, err := windowFromArgs(ctx.Get)
+		if err != nil {
+			ctx.W.WriteHeader(400)
+			return
+		}
+		dflt.Creator = ctx.User.Name
+		_, errs := Import([]byte(ctx.Get("ics")), dflt)
+		if len(errs) != 0 {
+			dl.Debug("import errors: %v", errs)
+			ctx.W.WriteHeader(400)
+			return
+		}
+	default:
+		w, err := windowFromArgs(ctx.Get)
+		if err == nil {
+			w.Creator = ctx.User.Name
+			err = Add(w)
+		}
+		if err != nil {
+			dl.Debug("invalid window: %v", err)
+			ctx.W.WriteHeader(400)
+			return
+		}
+	}
+
+	webList(ctx)
+}
+
+// ################################################################
+
+func apiUser(ctx *api.Context) string {
+
+	if u := ctx.Args["user"]; u != "" {
+		return u
+	}
+	return "api"
+}
+
+func apiAdd(ctx *api.Context) {
+
+	w, err := windowFromArgs(func(k string) string { return ctx.Args[k] })
+	if err == nil {
+		w.Creator = apiUser(ctx)
+		err = Add(w)
+	}
+
+	if err != nil {
+		ctx.SendResponseFinal(400, err.Error())
+		return
+	}
+
+	ctx.SendOK()
+	ctx.SendKVP("id", fmt.Sprintf("%d", w.Id))
+	ctx.SendFinal()
+}
+
+func apiDel(ctx *api.Context) {
+
+	id, _ := strconv.Atoi(ctx.Args["id"])
+
+	if !Delete(id, apiUser(ctx)) {
+		ctx.Send404()
+		return
+	}
+	ctx.SendOKFinal()
+}
+
+func apiList(ctx *api.Context) {
+
+	ctx.SendOK()
+	for _, w := range list() {
+		state := "closed"
+		if w.IsOpen {
+			state = "open"
+		}
+		ctx.SendKVP(fmt.Sprintf("%d", w.Id), fmt.Sprintf("%s %s [%s] %s {%s} %s",
+			state, w.Mode, w.Objects, w.recurrence(), w.Name, w.Comment))
+	}
+	ctx.SendFinal()
+}
+
+// file=/path/to/calendar.ics objects=... mode=...
+func apiImport(ctx *api.Context) {
+
+	data, err := ioutil.ReadFile(ctx.Args["file"])
+	if err != nil {
+		ctx.SendResponseFinal(400, err.Error())
+		return
+	}
+
+	dflt, err := windowFromArgs(func(k string) string { return ctx.Args[k] })
+	if err != nil {
+		ctx.SendResponseFinal(400, err.Error())
+		return
+	}
+	dflt.Creator = apiUser(ctx)
+
+	n, errs := Import(data, dflt)
+
+	ctx.SendOK()
+	ctx.SendKVP("imported", fmt.Sprintf("%d", n))
+	for i, e := range errs {
+		ctx.SendKVP(fmt.Sprintf("error%d", i), e)
+	}
+	ctx.SendFinal()
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 15:12 (EDT)
+// Function: import iCalendar (.ics) events as maintenance windows
+
+package maint
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"argus.domain/argus/clock"
+)
+
+type icsEvent struct {
+	uid     string
+	summary string
+	desc    string
+	start   time.Time
+	end     time.Time
+	dur     int64
+	tz      string
+	rrule   string
+	objects string
+	mode    string
+}
+
+// Import adds the events in the ics data as windows.
+// dflt supplies the objects, mode, etc for events that do not specify them.
+// previously imported events (by UID) are replaced.
+func Import(data []byte, dflt *Window) (int, []string) {
+
+	var errs []string
+	n := 0
+
+	for _, ev := range parseICS(data) {
+		w := &Window{
+			Name:     ev.summary,
+			Uid:      ev.uid,
+			Objects:  dflt.Objects,
+			Mode:     dflt.Mode,
+			RRule:    ev.rrule,
+			Start:    ev.start.Unix(),
+			Duration: ev.dur,
+			TZ:       ev.tz,
+			Creator:  dflt.Creator,
+			Comment:  ev.desc,
+		}
+		if ev.objects != "" {
+			w.Objects = ev.objects
+		}
+		if ev.mode != "" {
+			w.Mode = ev.mode
+		}
+		if w.TZ == "" {
+			w.TZ = dflt.TZ
+		}
+		if w.Duration == 0 && !ev.end.IsZero() {
+			w.Duration = ev.end.Unix() - w.Start
+		}
+
+		// skip events that are over
+		if w.RRule == "" && w.Start+w.Duration < clock.Unix() {
+			continue
+		}
+
+		old := 0
+		if ev.uid != "" {
+			old = findUid(ev.uid)
+		}
+
+		err := Replace(old, w)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("event '%s': %v", ev.summary, err))
+			continue
+		}
+		n++
+	}
+
+	return n, errs
+}
+
+func findUid(uid string) int {
+
+	lock.RLock()
+	defer lock.RUnlock()
+
+	for _, w := range windows {
+		if w.Uid == uid {
+			return w.Id
+		}
+	}
+	return 0
+}
+
+// ################################################################
+
+func parseICS(data []byte) []*icsEvent {
+
+	var evs []*icsEvent
+	var ev *icsEvent
+
+	for _, l := range unfoldICS(data) {
+		name, params, val := splitICSLine(l)
+
+		switch {
+		case name == "BEGIN" && val == "VEVENT":
+			ev = &icsEvent{}
+			continue
+		case name == "END" && val == "VEVENT":
+			if ev != nil && !ev.start.IsZero() {
+				evs = append(evs, ev)
+			}
+			ev = nil
+			continue
+		}
+
+		if ev == nil {
+			continue
+		}
+
+		switch name {
+		case "UID":
+			ev.uid = val
+		case "SUMMARY":
+			ev.summary = icsUnescape(val)
+		case "DESCRIPTION":
+			ev.desc = icsUnescape(val)
+		case "DTSTART":
+			ev.tz = params["TZID"]
+			ev.start, _ = parseICalTime(val, icsLocation(ev.tz))
+		case "DTEND":
+			ev.end, _ = parseICalTime(val, icsLocation(params["TZID"]))
+		case "DURATION":
+			ev.dur, _ = parseICSDuration(val)
+		case "RRULE":
+			ev.rrule = val
+		case "X-ARGUS-OBJECTS":
+			ev.objects = icsUnescape(val)
+		case "X-ARGUS-MODE":
+			ev.mode = strings.ToLower(val)
+		}
+	}
+
+	return evs
+}
+
+// long lines are folded with a leading space
+func unfoldICS(data []byte) []string {
+
+	var lines []string
+	scan := bufio.NewScanner(bytes.NewReader(data))
+
+	for scan.Scan() {
+		l := strings.TrimRight(scan.Text(), "\r")
+		if l == "" {
+			continue
+		}
+		if (l[0] == ' ' || l[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+// NAME;PARAM=X;PARAM=Y:value
+func splitICSLine(l string) (string, map[string]string, string) {
+
+	colon := strings.IndexByte(l, ':')
+	if colon == -1 {
+		return "", nil, ""
+	}
+
+	val := l[colon+1:]
+	f := strings.Split(l[:colon], ";")
+	params := make(map[string]string)
+
+	for _, p := range f[1:] {
+		if i := strings.IndexByte(p, '='); i != -1 {
+			params[strings.ToUpper(p[:i])] = strings.Trim(p[i+1:], `"`)
+		}
+	}
+
+	return strings.ToUpper(f[0]), params, val
+}
+
+func icsLocation(tz string) *time.Location {
+
+	if tz == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		dl.Verbose("unknown time zone '%s', using local", tz)
+		return time.Local
+	}
+	return loc
+}
+
+func icsUnescape(s string) string {
+
+	r := strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return r.Replace(s)
+}
+
+// P1W, P1D, PT2H30M, P1DT12H
+func parseICSDuration(v string) (int64, error) {
+
+	if !strings.HasPrefix(v, "P") {
+		return 0, fmt.Errorf("invalid duration '%s'", v)
+	}
+
+	var tot int64
+	num := ""
+	inTime := false
+	for _, c := range v[1:] {
+		switch {
+		case c >= '0' && c <= '9':
+			num += string(c)
+			continue
+		case c == 'T':
+			inTime = true
+			continue
+		}
+
+		n, err := strconv.ParseInt(num, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s'", v)
+		}
+		num = ""
+
+		switch c {
+		case 'W':
+			tot += n * 7 * 24 * 3600
+		case 'D':
+			tot += n * 24 * 3600
+		case 'H':
+			tot += n * 3600
+		case 'M':
+			if inTime {
+				tot += n * 60
+			} else {
+				tot += n * 30 * 24 * 3600
+			}
+		case 'S':
+			tot += n
+		default:
+			return 0, fmt.Errorf("invalid duration '%s'", v)
+		}
+	}
+	return tot, nil
+}
@@ -0,0 +1,430 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 13:31 (EDT)
+// Function: scheduled maintenance windows
+
+package maint
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/config"
+	"argus.domain/argus/monel"
+	"argus.domain/argus/notify"
+	"argus.domain/argus/sched"
+	"github.com/jaw0/acdiag"
+)
+
+const (
+	MODE_OVERRIDE = "override"
+	MODE_MUTE     = "mute"
+	MAXLOG        = 100
+	MAXDURATION   = 31 * 24 * 3600
+	KEEPDONE      = 30 * 24 * 3600
+	WHO           = "maint"
+)
+
+type Window struct {
+	Id       int
+	Name     string
+	Uid      string // from imported ics
+	Objects  string
+	Mode     string // override | mute
+	Cron     string
+	RRule    string
+	Start    int64 // one-shot start, or rrule dtstart
+	Duration int64
+	TZ       string
+	Creator  string
+	Comment  string
+	Created  int64
+	// current state
+	IsOpen    bool
+	OpenedAt  int64
+	CloseAt   int64
+	SilenceId int
+	Log       []LogDat
+	rec       *recur
+	loc       *time.Location
+}
+
+type LogDat struct {
+	When int64
+	Msg  string
+}
+
+type windowSave struct {
+	NextId int
+	List   []*Window
+}
+
+var dl = diag.Logger("maint")
+var lock sync.RWMutex
+var windows []*Window
+var nextId = 1
+
+func Init() {
+
+	load()
+
+	sched.NewFunc(&sched.Conf{
+		Freq: 60,
+		Text: "maintenance windows",
+		Auto: true,
+	}, periodic)
+}
+
+// ################################################################
+
+func (w *Window) compile() error {
+
+	if strings.TrimSpace(w.Objects) == "" {
+		return errors.New("no objects specified")
+	}
+	if w.Mode == "" {
+		w.Mode = MODE_OVERRIDE
+	}
+	if w.Mode != MODE_OVERRIDE && w.Mode != MODE_MUTE {
+		return fmt.Errorf("invalid mode '%s'", w.Mode)
+	}
+	if w.Duration <= 0 || w.Duration > MAXDURATION {
+		return fmt.Errorf("invalid duration %d", w.Duration)
+	}
+
+	w.loc = time.Local
+	if w.TZ != "" {
+		loc, err := time.LoadLocation(w.TZ)
+		if err != nil {
+			return fmt.Errorf("invalid time zone '%s'", w.TZ)
+		}
+		w.loc = loc
+	}
+
+	var err error
+	switch {
+	case w.Cron != "" && w.RRule != "":
+		return errors.New("specify cron or rrule, not both")
+	case w.Cron != "":
+		w.rec, err = parseCron(w.Cron)
+	case w.RRule != "":
+		if w.Start == 0 {
+			return errors.New("rrule requires a start")
+		}
+		w.rec, err = parseRRule(w.RRule, time.Unix(w.Start, 0).In(w.loc))
+	default:
+		if w.Start == 0 {
+			return errors.New("no start, cron, or rrule specified")
+		}
+	}
+
+	return err
+}
+
+// if the window is open at now, when did it open?
+func (w *Window) openedAt(now int64) (bool, int64) {
+
+	if w.rec == nil {
+		if w.Start <= now && now < w.Start+w.Duration {
+			return true, w.Start
+		}
+		return false, 0
+	}
+
+	// the most recent occurrence, if it is still running
+	t, ok := w.rec.lastAt(time.Unix(now, 0).In(w.loc), time.Unix(now-w.Duration, 0))
+	if !ok {
+		return false, 0
+	}
+	return true, t.Unix()
+}
+
+// will the window never open again?
+func (w *Window) isDone(now int64) bool {
+
+	if w.IsOpen {
+		return false
+	}
+	if w.rec == nil {
+		return w.Start+w.Duration < now
+	}
+	if w.rec.freq != "" && !w.rec.until.IsZero() {
+		return w.rec.until.Unix()+w.Duration < now
+	}
+	return false
+}
+
+func (w *Window) log(msg string, args ...interface{}) {
+
+	txt := fmt.Sprintf(msg, args...)
+	dl.Verbose("window #%d %s", w.Id, txt)
+
+	w.Log = append(w.Log, LogDat{clock.Unix(), txt})
+	if len(w.Log) > MAXLOG {
+		w.Log = w.Log[len(w.Log)-MAXLOG:]
+	}
+}
+
+func (w *Window) text() string {
+	return fmt.Sprintf("maintenance window #%d %s", w.Id, w.Name)
+}
+
+// ################################################################
+
+func periodic() {
+
+	now := clock.Unix()
+	changed := false
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	var keep []*Window
+
+	for _, w := range windows {
+		open, start := w.openedAt(now)
+
+		switch {
+		case open && !w.IsOpen:
+			w.open(start + w.Duration)
+			changed = true
+		case !open && w.IsOpen:
+			w.close()
+			changed = true
+		}
+
+		if w.isDone(now - KEEPDONE) {
+			dl.Debug("discarding old window #%d", w.Id)
+			changed = true
+			continue
+		}
+		keep = append(keep, w)
+	}
+
+	windows = keep
+
+	if changed {
+		save()
+	}
+}
+
+// lock is already held
+func (w *Window) open(closeAt int64) {
+
+	w.IsOpen = true
+	w.OpenedAt = clock.Unix()
+	w.CloseAt = closeAt
+	w.log("opened (%s) until %s", w.Mode, time.Unix(closeAt, 0).In(w.loc).Format(time.RFC1123))
+
+	objs := strings.Fields(w.Objects)
+	setMaintenance(objs, w.Id, true, w.text())
+
+	if w.Mode == MODE_MUTE {
+		s := &notify.Silence{
+			Match_Regex: objectRegex(objs),
+			End:         closeAt,
+			Creator:     WHO,
+			Comment:     w.text(),
+		}
+		err := notify.AddSilence(s)
+		if err != nil {
+			w.log("cannot silence: %v", err)
+			return
+		}
+		w.SilenceId = s.Id
+		return
+	}
+
+	for _, o := range objs {
+		m := monel.Find(o)
+		if m == nil {
+			w.log("cannot find object '%s'", o)
+			continue
+		}
+
+		if ov := currentOverride(m); ov != nil {
+			// do not clobber someone else's override
+			w.log("'%s' is already overridden by %s", o, ov.User)
+			continue
+		}
+
+		m.SetOverride(&argus.Override{
+			User:    WHO,
+			Text:    w.text(),
+			Expires: closeAt,
+		})
+	}
+}
+
+// lock is already held
+func (w *Window) close() {
+
+	w.IsOpen = false
+	w.log("closed")
+	setMaintenance(strings.Fields(w.Objects), w.Id, false, w.text())
+
+	if w.SilenceId != 0 {
+		notify.EndSilence(w.SilenceId, WHO)
+		w.SilenceId = 0
+	}
+
+	if w.Mode != MODE_OVERRIDE {
+		return
+	}
+
+	for _, o := range strings.Fields(w.Objects) {
+		m := monel.Find(o)
+		if m == nil {
+			continue
+		}
+
+		ov := currentOverride(m)
+		if ov != nil && ov.User == WHO && ov.Text == w.text() {
+			m.DelOverride(WHO, w.text()+" closed")
+		}
+	}
+}
+
+// excluded from sla reports, in either mode
+func setMaintenance(objs []string, id int, on bool, text string) {
+
+	for _, o := range objs {
+		if m := monel.Find(o); m != nil {
+			m.SetMaintenance(id, on, text)
+		}
+	}
+}
+
+func currentOverride(m *monel.M) *argus.Override {
+
+	m.Lock.RLock()
+	defer m.Lock.RUnlock()
+	return m.P.Override
+}
+
+// match the objects, and everything below them
+func objectRegex(objs []string) string {
+
+	var q []string
+	for _, o := range objs {
+		q = append(q, regexp.QuoteMeta(o))
+	}
+	return "^(" + strings.Join(q, "|") + ")(:|$)"
+}
+
+// ################################################################
+
+func Add(w *Window) error {
+	return Replace(0, w)
+}
+
+// Replace adds the window in place of an existing one.
+// if the new window is invalid, the existing one is kept
+func Replace(old int, w *Window) error {
+
+	err := w.compile()
+	if err != nil {
+		return err
+	}
+
+	w.Created = clock.Unix()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	w.Id = nextId
+	nextId++
+	windows = append(windows, w)
+	w.log("created by %s", w.Creator)
+
+	for i, o := range windows {
+		if old == 0 || o.Id != old {
+			continue
+		}
+		if o.IsOpen {
+			o.close()
+		}
+		dl.Verbose("window #%d replaced by #%d", old, w.Id)
+		windows = append(windows[:i], windows[i+1:]...)
+		break
+	}
+
+	save()
+	return nil
+}
+
+func Delete(id int, who string) bool {
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	for i, w := range windows {
+		if w.Id != id {
+			continue
+		}
+		if w.IsOpen {
+			w.close()
+		}
+		dl.Verbose("window #%d deleted by %s", id, who)
+		windows = append(windows[:i], windows[i+1:]...)
+		save()
+		return true
+	}
+	return false
+}
+
+// ################################################################
+
+func load() {
+
+	cf := config.Cf()
+	if cf.Datadir == "" {
+		dl.Debug("datadir not configured. not loading")
+		return
+	}
+
+	var ws windowSave
+	err := argus.Load(cf.Datadir+"/maintwin", &ws)
+	if err != nil {
+		dl.Debug("cannot open file: %v", err)
+		return
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	for _, w := range ws.List {
+		err := w.compile()
+		if err != nil {
+			dl.Problem("discarding window #%d: %v", w.Id, err)
+			continue
+		}
+		windows = append(windows, w)
+	}
+	if ws.NextId > nextId {
+		nextId = ws.NextId
+	}
+}
+
+// lock is already held
+func save() {
+
+	cf := config.Cf()
+	if cf.Datadir == "" {
+		dl.Debug("datadir not configured. not saving")
+		return
+	}
+	file := cf.Datadir + "/maintwin"
+
+	err := argus.Save(file, &windowSave{nextId, windows})
+
+	if err != nil {
+		dl.Problem("cannot save windows to '%s': %v", file, err)
+	}
+}
@@ -0,0 +1,443 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 14:05 (EDT)
+// Function: recurrence rules - cron + iCalendar RRULE
+
+package maint
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// an occurrence is a day that matches, at a time that matches
+type recur struct {
+	minute  [60]bool
+	hour    [24]bool
+	mday    [32]bool
+	month   [13]bool
+	wday    [7]bool
+	mdayAll bool
+	wdayAll bool
+	nthDay  []nthDay // BYDAY=2TU, -1FR
+	// rrule only
+	freq     string
+	interval int
+	start    time.Time
+	until    time.Time
+}
+
+type nthDay struct {
+	wday int
+	nth  int
+}
+
+const MAXCOUNTDAYS = 100 * 366
+
+var rruleDay = map[string]int{"SU": 0, "MO": 1, "TU": 2, "WE": 3, "TH": 4, "FR": 5, "SA": 6}
+
+// ################################################################
+
+// min hour mday month wday
+func parseCron(spec string) (*recur, error) {
+
+	f := strings.Fields(spec)
+	if len(f) != 5 {
+		return nil, fmt.Errorf("invalid cron spec '%s' - need 5 fields", spec)
+	}
+
+	r := &recur{}
+
+	err := cronField(f[0], 0, 59, r.minute[:])
+	if err == nil {
+		err = cronField(f[1], 0, 23, r.hour[:])
+	}
+	if err == nil {
+		err = cronField(f[2], 1, 31, r.mday[:])
+	}
+	if err == nil {
+		err = cronField(f[3], 1, 12, r.month[:])
+	}
+	if err == nil {
+		// 7 is also sunday
+		var wd [8]bool
+		err = cronField(f[4], 0, 7, wd[:])
+		copy(r.wday[:], wd[:7])
+		r.wday[0] = r.wday[0] || wd[7]
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron spec '%s': %v", spec, err)
+	}
+
+	r.mdayAll = f[2] == "*"
+	r.wdayAll = f[4] == "*"
+	return r, nil
+}
+
+// *, 5, 1-5, */15, 1-10/2, 1,3,5
+func cronField(spec string, min int, max int, set []bool) error {
+
+	for _, part := range strings.Split(spec, ",") {
+		step := 1
+		if i := strings.IndexByte(part, '/'); i != -1 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s < 1 {
+				return fmt.Errorf("invalid step '%s'", part)
+			}
+			step = s
+			part = part[:i]
+		}
+
+		lo, hi := min, max
+		if part != "*" {
+			var err error
+			if i := strings.IndexByte(part, '-'); i != -1 {
+				lo, err = strconv.Atoi(part[:i])
+				if err == nil {
+					hi, err = strconv.Atoi(part[i+1:])
+				}
+			} else {
+				lo, err = strconv.Atoi(part)
+				hi = lo
+			}
+			if err != nil {
+				return fmt.Errorf("invalid value '%s'", part)
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return fmt.Errorf("value out of range '%s'", part)
+		}
+
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return nil
+}
+
+// ################################################################
+
+// FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;BYHOUR=2;BYMINUTE=30;UNTIL=20271231T000000Z
+func parseRRule(spec string, start time.Time) (*recur, error) {
+
+	r := &recur{interval: 1, start: start}
+	count := 0
+	var byday, bymday, bymonth, byhour, byminute string
+
+	for _, kv := range strings.Split(strings.TrimPrefix(spec, "RRULE:"), ";") {
+		if kv == "" {
+			continue
+		}
+		i := strings.IndexByte(kv, '=')
+		if i == -1 {
+			return nil, fmt.Errorf("invalid rrule '%s'", kv)
+		}
+		k := strings.ToUpper(kv[:i])
+		v := kv[i+1:]
+		var err error
+
+		switch k {
+		case "FREQ":
+			r.freq = strings.ToUpper(v)
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(v)
+			if r.interval < 1 {
+				err = errors.New("invalid interval")
+			}
+		case "COUNT":
+			count, err = strconv.Atoi(v)
+		case "UNTIL":
+			r.until, err = parseICalTime(v, start.Location())
+		case "BYDAY":
+			byday = strings.ToUpper(v)
+		case "BYMONTHDAY":
+			bymday = v
+		case "BYMONTH":
+			bymonth = v
+		case "BYHOUR":
+			byhour = v
+		case "BYMINUTE":
+			byminute = v
+		case "WKST":
+			// monday only
+		default:
+			return nil, fmt.Errorf("unsupported rrule part '%s'", k)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid rrule '%s': %v", kv, err)
+		}
+	}
+
+	switch r.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return nil, fmt.Errorf("unsupported rrule freq '%s'", r.freq)
+	}
+
+	// by default, things repeat at the same time/day as the start
+	if byminute == "" {
+		byminute = strconv.Itoa(start.Minute())
+	}
+	if byhour == "" {
+		byhour = strconv.Itoa(start.Hour())
+	}
+	if byday == "" && bymday == "" {
+		switch r.freq {
+		case "WEEKLY":
+			byday = strings.ToUpper(start.Weekday().String()[:2])
+		case "MONTHLY", "YEARLY":
+			bymday = strconv.Itoa(start.Day())
+		}
+	}
+	if bymonth == "" && r.freq == "YEARLY" {
+		bymonth = strconv.Itoa(int(start.Month()))
+	}
+
+	err := cronList(byminute, 0, 59, r.minute[:])
+	if err == nil {
+		err = cronList(byhour, 0, 23, r.hour[:])
+	}
+	if err == nil {
+		err = cronList(bymday, 1, 31, r.mday[:])
+	}
+	if err == nil {
+		err = cronList(bymonth, 1, 12, r.month[:])
+	}
+	if err == nil {
+		err = r.parseByDay(byday)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid rrule '%s': %v", spec, err)
+	}
+
+	r.mdayAll = bymday == ""
+	r.wdayAll = byday == ""
+	if bymonth == "" {
+		for i := range r.month {
+			r.month[i] = true
+		}
+	}
+
+	if count > 0 {
+		r.until = r.countUntil(count)
+	}
+
+	return r, nil
+}
+
+// comma separated list, empty => none
+func cronList(spec string, min int, max int, set []bool) error {
+
+	if spec == "" {
+		return nil
+	}
+	return cronField(spec, min, max, set)
+}
+
+func (r *recur) parseByDay(spec string) error {
+
+	if spec == "" {
+		return nil
+	}
+
+	for _, d := range strings.Split(spec, ",") {
+		if len(d) < 2 {
+			return fmt.Errorf("invalid day '%s'", d)
+		}
+		wd, ok := rruleDay[d[len(d)-2:]]
+		if !ok {
+			return fmt.Errorf("invalid day '%s'", d)
+		}
+		if len(d) == 2 {
+			r.wday[wd] = true
+			continue
+		}
+
+		n, err := strconv.Atoi(d[:len(d)-2])
+		if err != nil || n == 0 || n > 5 || n < -5 {
+			return fmt.Errorf("invalid day '%s'", d)
+		}
+		r.nthDay = append(r.nthDay, nthDay{wd, n})
+	}
+	return nil
+}
+
+// convert COUNT into UNTIL
+func (r *recur) countUntil(count int) time.Time {
+
+	day := r.start
+	for i := 0; i < MAXCOUNTDAYS; i++ {
+		if r.dayMatches(day) {
+			for h := 0; h < 24; h++ {
+				for m := 0; m < 60; m++ {
+					if !r.hour[h] || !r.minute[m] {
+						continue
+					}
+					t := time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, day.Location())
+					if t.Before(r.start) {
+						continue
+					}
+					count--
+					if count == 0 {
+						return t
+					}
+				}
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// ################################################################
+
+// does something start at exactly t?
+func (r *recur) occursAt(t time.Time) bool {
+
+	if !r.hour[t.Hour()] || !r.minute[t.Minute()] {
+		return false
+	}
+	if r.freq != "" {
+		if t.Before(r.start) {
+			return false
+		}
+		if !r.until.IsZero() && t.After(r.until) {
+			return false
+		}
+	}
+	return r.dayMatches(t)
+}
+
+// the most recent occurrence in (after, t]
+func (r *recur) lastAt(t time.Time, after time.Time) (time.Time, bool) {
+
+	loc := t.Location()
+
+	for d := 0; ; d++ {
+		day := time.Date(t.Year(), t.Month(), t.Day()-d, 0, 0, 0, 0, loc)
+		if !day.AddDate(0, 0, 1).After(after) {
+			return time.Time{}, false
+		}
+		if !r.dayMatches(day) {
+			continue
+		}
+
+		for h := 23; h >= 0; h-- {
+			if !r.hour[h] {
+				continue
+			}
+			for m := 59; m >= 0; m-- {
+				if !r.minute[m] {
+					continue
+				}
+				o := time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, loc)
+				if o.After(t) {
+					continue
+				}
+				if !o.After(after) {
+					return time.Time{}, false
+				}
+				if r.freq != "" && o.Before(r.start) {
+					return time.Time{}, false
+				}
+				if r.freq != "" && !r.until.IsZero() && o.After(r.until) {
+					continue
+				}
+				return o, true
+			}
+		}
+	}
+}
+
+func (r *recur) dayMatches(t time.Time) bool {
+
+	if !r.month[int(t.Month())] {
+		return false
+	}
+	if r.freq != "" && !r.intervalMatches(t) {
+		return false
+	}
+
+	mday := r.mday[t.Day()]
+	wday := r.wday[int(t.Weekday())] || r.nthMatches(t)
+
+	switch {
+	case r.mdayAll && r.wdayAll:
+		return true
+	case r.mdayAll:
+		return wday
+	case r.wdayAll:
+		return mday
+	case r.freq != "":
+		// rrule: all BYxxx must match
+		return mday && wday
+	}
+	// cron: either
+	return mday || wday
+}
+
+func (r *recur) nthMatches(t time.Time) bool {
+
+	if len(r.nthDay) == 0 {
+		return false
+	}
+
+	wd := int(t.Weekday())
+	fwd := (t.Day()-1)/7 + 1
+	dim := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	bwd := -((dim-t.Day())/7 + 1)
+
+	for _, n := range r.nthDay {
+		if n.wday == wd && (n.nth == fwd || n.nth == bwd) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *recur) intervalMatches(t time.Time) bool {
+
+	if r.interval <= 1 {
+		return true
+	}
+
+	switch r.freq {
+	case "DAILY":
+		return daysBetween(r.start, t)%r.interval == 0
+	case "WEEKLY":
+		// weeks start on monday
+		so := (int(r.start.Weekday()) + 6) % 7
+		to := (int(t.Weekday()) + 6) % 7
+		return ((daysBetween(r.start, t)+so-to)/7)%r.interval == 0
+	case "MONTHLY":
+		m := (t.Year()-r.start.Year())*12 + int(t.Month()) - int(r.start.Month())
+		return m%r.interval == 0
+	case "YEARLY":
+		return (t.Year()-r.start.Year())%r.interval == 0
+	}
+	return true
+}
+
+// calendar days, ignoring dst
+func daysBetween(a, b time.Time) int {
+
+	da := time.Date(a.Year(), a.Month(), a.Day(), 12, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 12, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
+
+// 20271231T020000Z, 20271231T020000, 20271231
+func parseICalTime(v string, loc *time.Location) (time.Time, error) {
+
+	if strings.HasSuffix(v, "Z") {
+		return time.Parse("20060102T150405Z", v)
+	}
+	if len(v) == 8 {
+		return time.ParseInLocation("20060102", v, loc)
+	}
+	return time.ParseInLocation("20060102T150405", v, loc)
+}
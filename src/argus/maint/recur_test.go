@@ -0,0 +1,147 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 16:20 (EDT)
+// Function:
+
+package maint
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"argus.domain/argus/config"
+)
+
+func occursTest(t *testing.T, r *recur, when string, exp bool) {
+
+	tm, _ := time.ParseInLocation("2006-01-02 15:04", when, time.UTC)
+	got := r.occursAt(tm)
+	if got != exp {
+		fmt.Printf("%s -> %v != %v\n", when, got, exp)
+		t.Fail()
+	}
+}
+
+func TestCron(t *testing.T) {
+
+	// 02:30 on weekdays
+	r, err := parseCron("30 2 * * 1-5")
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		t.FailNow()
+	}
+
+	occursTest(t, r, "2026-10-19 02:30", true)  // mon
+	occursTest(t, r, "2026-10-19 02:31", false) // mon
+	occursTest(t, r, "2026-10-18 02:30", false) // sun
+
+	// every 15 min, on the 1st + 15th
+	r, _ = parseCron("*/15 * 1,15 * *")
+	occursTest(t, r, "2026-10-15 13:45", true)
+	occursTest(t, r, "2026-10-16 13:45", false)
+}
+
+func TestRRule(t *testing.T) {
+
+	start := time.Date(2026, 10, 6, 22, 0, 0, 0, time.UTC) // tue
+
+	// every other tuesday
+	r, err := parseRRule("FREQ=WEEKLY;INTERVAL=2", start)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		t.FailNow()
+	}
+	occursTest(t, r, "2026-10-06 22:00", true)
+	occursTest(t, r, "2026-10-13 22:00", false)
+	occursTest(t, r, "2026-10-20 22:00", true)
+	occursTest(t, r, "2026-09-22 22:00", false) // before start
+
+	// second tuesday of the month, 3 times
+	r, _ = parseRRule("FREQ=MONTHLY;BYDAY=2TU;COUNT=3", start)
+	occursTest(t, r, "2026-10-13 22:00", true)
+	occursTest(t, r, "2026-11-10 22:00", true)
+	occursTest(t, r, "2026-12-08 22:00", true)
+	occursTest(t, r, "2027-01-12 22:00", false)
+
+	// last friday
+	r, _ = parseRRule("FREQ=MONTHLY;BYDAY=-1FR;BYHOUR=1;BYMINUTE=0", start)
+	occursTest(t, r, "2026-10-30 01:00", true)
+	occursTest(t, r, "2026-10-23 01:00", false)
+}
+
+func TestICS(t *testing.T) {
+
+	ics := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:abc@example\r\nSUMMARY:DB patch\\, phase 1\r\n" +
+		"DTSTART;TZID=UTC:20261020T020000\r\nDURATION:PT2H30M\r\nX-ARGUS-OBJECTS:Top:DB\r\n" +
+		"END:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	evs := parseICS([]byte(ics))
+
+	if len(evs) != 1 || evs[0].dur != 9000 || evs[0].summary != "DB patch, phase 1" || evs[0].objects != "Top:DB" {
+		fmt.Printf("ics -> %+v\n", evs)
+		t.Fail()
+	}
+}
+
+func TestICSReplace(t *testing.T) {
+
+	config.Cf().Datadir = ""
+
+	ics := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:replace@example\r\nSUMMARY:upgrade\r\n" +
+		"DTSTART;TZID=UTC:20991020T020000\r\nDURATION:PT1H\r\n" +
+		"END:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	n, errs := Import([]byte(ics), &Window{Objects: "Top:DB", Creator: "test"})
+	first := findUid("replace@example")
+	if n != 1 || len(errs) != 0 || first == 0 {
+		fmt.Printf("import: %d %v\n", n, errs)
+		t.FailNow()
+	}
+
+	// a bad re-import keeps the existing window
+	n, errs = Import([]byte(ics), &Window{Objects: "Top:DB", Mode: "bogus", Creator: "test"})
+	if n != 0 || len(errs) != 1 || findUid("replace@example") != first {
+		fmt.Printf("bad import: %d %v\n", n, errs)
+		t.Fail()
+	}
+
+	// a good one replaces it
+	n, _ = Import([]byte(ics), &Window{Objects: "Top:DB", Creator: "test"})
+	cnt := 0
+	for _, w := range windows {
+		if w.Uid == "replace@example" {
+			cnt++
+		}
+	}
+	if n != 1 || cnt != 1 || findUid("replace@example") == first {
+		fmt.Printf("replace: %d %d\n", n, cnt)
+		t.Fail()
+	}
+}
+
+func TestLastAt(t *testing.T) {
+
+	start := time.Date(2026, 10, 6, 22, 0, 0, 0, time.UTC) // tue
+	r, _ := parseRRule("FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH", start)
+
+	// compare with looking back one minute at a time
+	for _, when := range []string{"2026-10-06 21:59", "2026-10-06 22:00", "2026-10-09 03:17", "2026-10-16 12:00", "2026-10-21 00:00"} {
+		now, _ := time.ParseInLocation("2006-01-02 15:04", when, time.UTC)
+		after := now.Add(-4 * 24 * time.Hour)
+
+		var exp time.Time
+		for tm := now; tm.After(after); tm = tm.Add(-time.Minute) {
+			if r.occursAt(tm) {
+				exp = tm
+				break
+			}
+		}
+
+		got, ok := r.lastAt(now, after)
+		if ok != !exp.IsZero() || !got.Equal(exp) {
+			fmt.Printf("%s -> %v %v != %v\n", when, got, ok, exp)
+			t.Fail()
+		}
+	}
+}
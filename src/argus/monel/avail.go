@@ -0,0 +1,279 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-22 09:12 (EDT)
+// Function: availability history - durable record of transitions, for sla reports
+
+package monel
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"sync"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/config"
+	"argus.domain/argus/sched"
+)
+
+// why is the time not counted?
+const (
+	EXCL_NONE     = 0
+	EXCL_OVERRIDE = 1
+	EXCL_MAINT    = 2
+	EXCL_NODATA   = 3 // argus was not running
+)
+
+// each record holds until the next one
+type availRec struct {
+	When   int64
+	Status uint8
+	Excl   uint8
+	Pad    [6]byte
+}
+
+const AVAILRECSIZE = 16
+
+var availLock sync.Mutex
+
+var availCron = sched.NewFunc(&sched.Conf{
+	Freq:  24 * 3600,
+	Phase: 3600,
+	Auto:  true,
+	Text:  "availability history cleanup",
+}, availCleanup)
+
+func (m *M) availFile() string {
+
+	cf := config.Cf()
+	if cf.Datadir == "" || m.Cf.Passive {
+		return ""
+	}
+	return cf.Datadir + "/avail/" + m.Pathname("", "")
+}
+
+// current state, for the history
+func (m *M) availState() (argus.Status, uint8) {
+
+	switch {
+	case m.P.InMaint || m.P.AncInMaint:
+		return m.P.Status, EXCL_MAINT
+	case m.P.Override != nil || m.P.AncInOv:
+		return m.P.Status, EXCL_OVERRIDE
+	}
+	return m.P.Status, EXCL_NONE
+}
+
+// record the state, if it changed
+// lock is already held
+func (m *M) availUpdate() {
+
+	file := m.availFile()
+	if file == "" {
+		return
+	}
+
+	st, ex := m.availState()
+
+	if m.availLast == nil {
+		m.availLast = availLastRec(file)
+	}
+	if m.availLast != nil && argus.Status(m.availLast.Status) == st && m.availLast.Excl == ex {
+		return
+	}
+
+	rec := &availRec{When: clock.Unix(), Status: uint8(st), Excl: ex}
+	m.availLast = rec
+
+	err := availAppend(file, rec)
+	if err != nil {
+		dl.Problem("cannot save availability history '%s': %v", file, err)
+	}
+}
+
+// argus is stopping. time until we restart is not counted
+func (m *M) availStop() {
+
+	file := m.availFile()
+	if file == "" {
+		return
+	}
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	rec := &availRec{When: clock.Unix(), Status: uint8(argus.UNKNOWN), Excl: EXCL_NODATA}
+	m.availLast = rec
+	availAppend(file, rec)
+}
+
+// ################################################################
+
+func availAppend(file string, rec *availRec) error {
+
+	availLock.Lock()
+	defer availLock.Unlock()
+
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return binary.Write(f, binary.BigEndian, rec)
+}
+
+func availLastRec(file string) *availRec {
+
+	availLock.Lock()
+	defer availLock.Unlock()
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	_, err = f.Seek(-AVAILRECSIZE, io.SeekEnd)
+	if err != nil {
+		return nil
+	}
+
+	rec := &availRec{}
+	if binary.Read(f, binary.BigEndian, rec) != nil {
+		return nil
+	}
+	return rec
+}
+
+// all records, oldest first
+func availRead(file string) []availRec {
+
+	availLock.Lock()
+	defer availLock.Unlock()
+
+	return availReadL(file)
+}
+
+// availLock is already held
+func availReadL(file string) []availRec {
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var res []availRec
+	for {
+		var rec availRec
+		if binary.Read(f, binary.BigEndian, &rec) != nil {
+			break
+		}
+		res = append(res, rec)
+	}
+	return res
+}
+
+// discard records older than the retention time
+// keep the last one before the cutoff, it says what the state was
+func availTrim(file string, cutoff int64) {
+
+	availLock.Lock()
+	defer availLock.Unlock()
+
+	recs := availReadL(file)
+	i := 0
+	for i+1 < len(recs) && recs[i+1].When <= cutoff {
+		i++
+	}
+	if i == 0 {
+		return
+	}
+
+	temp := file + ".tmp"
+	f, err := os.Create(temp)
+	if err != nil {
+		dl.Problem("cannot trim availability history '%s': %v", file, err)
+		return
+	}
+	err = binary.Write(f, binary.BigEndian, recs[i:])
+	f.Close()
+
+	if err != nil {
+		os.Remove(temp)
+		return
+	}
+	os.Rename(temp, file)
+}
+
+func availCleanup() {
+
+	if slaCf.Keep == 0 {
+		return
+	}
+	cutoff := clock.Unix() - slaCf.Keep
+
+	lock.RLock()
+	var files []string
+	for _, m := range byname {
+		if f := m.availFile(); f != "" {
+			files = append(files, f)
+		}
+	}
+	lock.RUnlock()
+
+	for _, f := range files {
+		availTrim(f, cutoff)
+	}
+}
+
+// ################################################################
+
+// scheduled maintenance. excluded from sla reports
+// in maintenance until every window (by id) that opened has closed
+func (m *M) SetMaintenance(id int, on bool, text string) {
+
+	m.Lock.Lock()
+	if on {
+		m.loggitL("MAINT", "started "+text)
+	} else {
+		m.loggitL("MAINT", "ended "+text)
+	}
+
+	var ids []int
+	for _, i := range m.P.MaintIds {
+		if i != id {
+			ids = append(ids, i)
+		}
+	}
+	if on {
+		ids = append(ids, id)
+	}
+	m.P.MaintIds = ids
+	m.P.InMaint = len(ids) != 0
+	m.availUpdate()
+	m.WebTime = clock.Nano()
+	m.Lock.Unlock()
+
+	m.maintPropDown()
+}
+
+// set AncInMaint on all descendants
+func (m *M) maintPropDown() {
+
+	m.Lock.RLock()
+	v := m.P.InMaint || m.P.AncInMaint
+	childs := m.Children
+	m.Lock.RUnlock()
+
+	for _, c := range childs {
+		c.Lock.Lock()
+		c.P.AncInMaint = v
+		c.availUpdate()
+		c.Lock.Unlock()
+
+		c.maintPropDown()
+	}
+}
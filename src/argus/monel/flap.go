@@ -0,0 +1,233 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 09:12 (EDT)
+// Function: flap detection
+
+package monel
+
+import (
+	"fmt"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/darp"
+	"argus.domain/argus/notify"
+	"argus.domain/argus/sched"
+)
+
+// objects currently flapping. protected by the package lock
+var flappers = make(map[string]*M)
+
+var flapCron = sched.NewFunc(&sched.Conf{
+	Freq: 60,
+	Auto: true,
+	Text: "flap check",
+}, flapCheckAll)
+
+// recent transitions count more: weight 1.0 (now) to 0.5 (window ago)
+func flapScore(trans []int64, now int64, window int64) float64 {
+
+	if window <= 0 {
+		return 0
+	}
+
+	score := 0.0
+	for _, t := range trans {
+		age := now - t
+		if age < 0 || age > window {
+			continue
+		}
+		score += 1 - 0.5*float64(age)/float64(window)
+	}
+	return score
+}
+
+// lock is already held
+func (m *M) flapTrim(now int64) {
+
+	i := 0
+	for i < len(m.P.FlapTrans) && m.P.FlapTrans[i] < now-m.Cf.Flap_Window {
+		i++
+	}
+	if i != 0 {
+		m.P.FlapTrans = append([]int64(nil), m.P.FlapTrans[i:]...)
+	}
+}
+
+// lock is already held
+// track the transition, maybe start flapping
+func (m *M) flapTransition() {
+
+	if m.Cf.Flap_Window == 0 || m.Cf.Flap_High == 0 {
+		return
+	}
+
+	now := clock.Unix()
+	m.P.FlapTrans = append(m.P.FlapTrans, now)
+	m.flapTrim(now)
+
+	if m.P.Flapping {
+		m.P.FlapCount++
+		if m.P.OvStatus > m.P.FlapWorst && m.P.OvStatus <= argus.CRITICAL {
+			m.P.FlapWorst = m.P.OvStatus
+		}
+		return
+	}
+
+	score := flapScore(m.P.FlapTrans, now, m.Cf.Flap_Window)
+	if score < m.Cf.Flap_High {
+		return
+	}
+
+	m.P.Flapping = true
+	m.P.FlapStart = now
+	m.P.FlapCount = len(m.P.FlapTrans)
+	m.P.FlapNotified = false
+	m.P.FlapWorst = argus.CLEAR
+	if m.P.OvStatus <= argus.CRITICAL {
+		m.P.FlapWorst = m.P.OvStatus
+	}
+
+	m.loggitL("FLAPPING", fmt.Sprintf("started flapping (%d transitions)", len(m.P.FlapTrans)))
+	dl.Verbose("FLAPPING %s", m.Cf.Unique)
+
+	go func() {
+		lock.Lock()
+		flappers[m.Cf.Unique] = m
+		lock.Unlock()
+	}()
+}
+
+// lock is already held
+// notify once. returns true if notifications are being held
+func (m *M) flapNotify() bool {
+
+	if !m.P.Flapping {
+		return false
+	}
+	if m.P.FlapNotified {
+		return true
+	}
+
+	st := m.P.FlapWorst
+	if st < argus.WARNING {
+		// flapping, but nothing worse than up yet
+		return true
+	}
+
+	m.P.FlapNotified = true
+	m.sendNotify(st, argus.CLEAR, m.Cf.Friendlyname+" is FLAPPING")
+	return true
+}
+
+func flapCheckAll() {
+
+	lock.RLock()
+	var all []*M
+	for _, m := range flappers {
+		all = append(all, m)
+	}
+	lock.RUnlock()
+
+	for _, m := range all {
+		if !m.flapCheck() {
+			lock.Lock()
+			delete(flappers, m.Cf.Unique)
+			lock.Unlock()
+		}
+	}
+}
+
+// has it stabilized? returns true if still flapping
+func (m *M) flapCheck() bool {
+
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+
+	if !m.P.Flapping {
+		return false
+	}
+
+	now := clock.Unix()
+	m.flapTrim(now)
+	score := flapScore(m.P.FlapTrans, now, m.Cf.Flap_Window)
+
+	if score > m.Cf.Flap_Low {
+		return true
+	}
+
+	summary := fmt.Sprintf("stopped flapping after %s, %d transitions, worst %s",
+		argus.Elapsed(now-m.P.FlapStart), m.P.FlapCount, m.P.FlapWorst)
+
+	m.P.Flapping = false
+	m.WebTime = clock.Nano()
+	m.loggitL("FLAPPING", summary)
+	dl.Verbose("FLAPPING %s %s", m.Cf.Unique, summary)
+	m.incidentTransition()
+
+	if !m.P.FlapNotified {
+		return false
+	}
+	m.P.FlapNotified = false
+
+	st := m.P.OvStatus
+	if st > argus.CRITICAL || st == argus.UNKNOWN || m.P.AncInOv {
+		// overridden, depends, ... - nothing to say
+		return false
+	}
+
+	state := "UP"
+	if st != argus.CLEAR {
+		state = "DOWN/" + st.String()
+	}
+	m.sendNotify(st, m.P.FlapWorst, m.Cf.Friendlyname+" "+summary+", now "+state)
+	return false
+}
+
+// replaced by tests
+var newNotify = notify.New
+
+// services can add to the message, eg. the forecast
+type notifyDetailer interface {
+	NotifyDetail(argus.Status) string
+}
+
+// lock is already held
+func (m *M) sendNotify(st argus.Status, prevOv argus.Status, msg string) {
+
+	if m.NotifyCf == nil {
+		return
+	}
+
+	m.Debug("send notify")
+
+	detail := ""
+	if nd, ok := m.Me.(notifyDetailer); ok && msg == "" {
+		detail = nd.NotifyDetail(st)
+	}
+	gfile := ""
+	if m.Cf.Graph {
+		gfile = m.Pathname("", "")
+	}
+
+	notif := newNotify(&notify.NewConf{
+		Unique:       m.Cf.Unique,
+		FriendlyName: m.Cf.Friendlyname,
+		ShortName:    m.Cf.Label,
+		Conf:         m.NotifyCf,
+		Reason:       m.P.Reason,
+		Result:       m.P.Result,
+		Tags:         m.Cf.Tags,
+		Darp:         darp.MyId,
+		OvStatus:     st,
+		PrevOv:       prevOv,
+		Message:      msg,
+		Detail:       detail,
+		GraphFile:    gfile,
+	}, m)
+
+	if notif != nil {
+		m.Notifies = append(m.Notifies, notif)
+		m.incidentAdd(notif, st)
+	}
+}
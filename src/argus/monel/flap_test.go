@@ -0,0 +1,131 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 10:02 (EDT)
+// Function:
+
+package monel
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/configure"
+	"argus.domain/argus/notify"
+)
+
+func flapTest(t *testing.T, trans []int64, exp float64) {
+
+	got := flapScore(trans, 10000, 3600)
+	if got < exp-0.001 || got > exp+0.001 {
+		fmt.Printf("%v -> %f != %f\n", trans, got, exp)
+		t.Fail()
+	}
+}
+
+func TestFlapScore(t *testing.T) {
+
+	flapTest(t, nil, 0)
+	flapTest(t, []int64{10000}, 1)
+	flapTest(t, []int64{6400}, 0.5)
+	flapTest(t, []int64{6399, 8200, 10000}, 1.75) // oldest is outside the window
+	flapTest(t, []int64{9900, 9910, 9920, 9930, 9940, 9950}, 5.9375)
+}
+
+// a minimal leaf object, for tests
+type testMon struct {
+	m *M
+}
+
+func (f *testMon) Persist(map[string]interface{})                {}
+func (f *testMon) Restore(map[string]interface{})                {}
+func (f *testMon) WebJson(map[string]interface{})                {}
+func (f *testMon) WebMeta(map[string]interface{})                {}
+func (f *testMon) Config(*configure.CF) error                    { return nil }
+func (f *testMon) Dump(argus.Dumper)                             {}
+func (f *testMon) CheckNow()                                     {}
+func (f *testMon) Init() error                                   { return nil }
+func (f *testMon) DoneConfig()                                   {}
+func (f *testMon) Recycle()                                      {}
+func (f *testMon) Children() []*M                                { return nil }
+func (f *testMon) Self() *M                                      { return f.m }
+func (f *testMon) GraphList(string, []interface{}) []interface{} { return nil }
+
+func TestFlapping(t *testing.T) {
+
+	f := &testMon{}
+	m := New(f, nil)
+	f.m = m
+	m.Cf.Unique = "Top:flappy"
+	m.Cf.Friendlyname = "flappy"
+	m.Cf.Sendnotify[int(argus.UNKNOWN)] = argus.ScheduleAlways("yes")
+	m.statsInit()
+	m.NotifyCf = &notify.Conf{}
+
+	// see what would be sent, without sending it
+	var msgs []string
+	newNotify = func(c *notify.NewConf, r notify.Remover) *notify.N {
+		msg := c.Message
+		if msg == "" {
+			msg = c.FriendlyName + " is " + c.OvStatus.String()
+		}
+		msgs = append(msgs, msg)
+		return nil
+	}
+	defer func() { newNotify = notify.New }()
+
+	if m.Cf.Flap_High != 0 {
+		fmt.Printf("flap detection enabled by default\n")
+		t.Fail()
+	}
+	m.Cf.Flap_High = 3
+	m.Cf.Flap_Low = 1
+
+	update := func(st argus.Status) {
+		m.Update(st, "", "")
+	}
+
+	// down, up - not yet flapping
+	update(argus.CRITICAL)
+	update(argus.CLEAR)
+	if m.P.Flapping || len(msgs) != 2 {
+		fmt.Printf("early: %v %v\n", m.P.Flapping, msgs)
+		t.Fail()
+	}
+
+	// enter: one notification
+	update(argus.MAJOR)
+	if !m.P.Flapping || len(msgs) != 3 || !strings.Contains(msgs[2], "FLAPPING") {
+		fmt.Printf("enter: %v %v\n", m.P.Flapping, msgs)
+		t.FailNow()
+	}
+
+	// hold: nothing more, but the worst is tracked
+	update(argus.CLEAR)
+	update(argus.CRITICAL)
+	update(argus.CLEAR)
+	if len(msgs) != 3 || m.P.FlapWorst != argus.CRITICAL || m.P.FlapCount != 6 {
+		fmt.Printf("hold: %d %s %d %v\n", len(msgs), m.P.FlapWorst, m.P.FlapCount, msgs)
+		t.Fail()
+	}
+
+	// stabilize: a single summary
+	m.Lock.Lock()
+	for i := range m.P.FlapTrans {
+		m.P.FlapTrans[i] -= 2 * m.Cf.Flap_Window
+	}
+	m.Lock.Unlock()
+
+	if m.flapCheck() || m.P.Flapping {
+		fmt.Printf("still flapping\n")
+		t.Fail()
+	}
+	m.flapCheck()
+
+	if len(msgs) != 4 || !strings.Contains(msgs[3], "stopped flapping") || !strings.Contains(msgs[3], "worst critical") ||
+		!strings.HasSuffix(msgs[3], "now UP") {
+		fmt.Printf("summary: %v\n", msgs)
+		t.Fail()
+	}
+}
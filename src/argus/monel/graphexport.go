@@ -0,0 +1,337 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-21 09:40 (EDT)
+// Function: export + import graph data as csv or json
+
+package monel
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"argus.domain/argus/api"
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/darp"
+	"argus.domain/argus/graph"
+	"argus.domain/argus/web"
+)
+
+// services know their own retention, for creating new files
+type graphLayouter interface {
+	GraphLayout() *graph.Layout
+}
+
+type graphObjData struct {
+	Obj  string
+	Data []*graph.Point
+}
+
+var graphCSVHeader = []string{"object", "time", "status", "value", "min", "max", "stdev", "exp", "delt", "p50", "p90", "p95", "p99"}
+
+func init() {
+	web.Add(web.PRIVATE, "/api/graphexport", webGraphExport)
+	api.Add(true, "graphexport", apiGraphExport)
+	api.Add(true, "graphimport", apiGraphImport)
+}
+
+// obj, which, start, end, fmt, subtree, darp
+func webGraphExport(ctx *web.Context) {
+
+	m, creds := webObjUserCheck(ctx)
+	if m == nil {
+		return
+	}
+
+	now := clock.Unix()
+	which := ctx.Get("which")
+	start, err1 := argus.ParseWhen(ctx.Get("start"), now, 0)
+	end, err2 := argus.ParseWhen(ctx.Get("end"), now, now)
+	format := ctx.Get("fmt")
+
+	if err1 != nil || err2 != nil || !validWhich(which) {
+		ctx.W.WriteHeader(400)
+		return
+	}
+
+	var objs []*M
+	for _, o := range m.graphObjs(argus.CheckBool(ctx.Get("subtree")), nil, nil) {
+		if argus.ACLPermitsUser(o.Cf.ACL_Page, creds) {
+			objs = append(objs, o)
+		}
+	}
+
+	data := graphExport(objs, which, start, end, ctx.Get("darp"))
+
+	var buf bytes.Buffer
+	switch format {
+	case "csv":
+		writeGraphCSV(&buf, data)
+		ctx.W.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		ctx.W.Header().Set("Content-Disposition", "attachment; filename=\"graphdata.csv\"")
+	case "", "json":
+		js, _ := json.MarshalIndent(data, "", "  ")
+		buf.Write(js)
+		ctx.W.Header().Set("Content-Type", "application/json; charset=utf-8")
+	default:
+		ctx.W.WriteHeader(400)
+		return
+	}
+
+	ctx.W.Write(buf.Bytes())
+}
+
+// argusctl -q graphexport obj=Top:Foo subtree=yes which=hours start=30d fmt=csv
+func apiGraphExport(ctx *api.Context) {
+
+	m := Find(ctx.Args["obj"])
+	if m == nil {
+		ctx.Send404()
+		return
+	}
+
+	now := clock.Unix()
+	which := ctx.Args["which"]
+	start, err1 := argus.ParseWhen(ctx.Args["start"], now, 0)
+	end, err2 := argus.ParseWhen(ctx.Args["end"], now, now)
+
+	if err1 != nil || err2 != nil || !validWhich(which) {
+		ctx.SendResponseFinal(400, "invalid parameters")
+		return
+	}
+
+	objs := m.graphObjs(argus.CheckBool(ctx.Args["subtree"]), nil, nil)
+	data := graphExport(objs, which, start, end, ctx.Args["darp"])
+
+	var buf bytes.Buffer
+	switch ctx.Args["fmt"] {
+	case "", "csv":
+		writeGraphCSV(&buf, data)
+	case "json":
+		js, _ := json.MarshalIndent(data, "", "  ")
+		buf.Write(js)
+		buf.WriteString("\n")
+	default:
+		ctx.SendResponseFinal(400, "invalid format")
+		return
+	}
+
+	ctx.SendOK()
+	ctx.Send(buf.String())
+	ctx.SendFinal()
+}
+
+// argusctl graphimport file=/tmp/data.csv which=samples [from=Top:Old: to=Top:New:]
+func apiGraphImport(ctx *api.Context) {
+
+	which := ctx.Args["which"]
+	if !validWhich(which) {
+		ctx.SendResponseFinal(400, "invalid parameters")
+		return
+	}
+
+	f, err := os.Open(ctx.Args["file"])
+	if err != nil {
+		ctx.SendResponseFinal(404, err.Error())
+		return
+	}
+	defer f.Close()
+
+	data, err := readGraphCSV(f, ctx.Args["from"], ctx.Args["to"])
+	if err != nil {
+		ctx.SendResponseFinal(400, err.Error())
+		return
+	}
+
+	ctx.SendOK()
+
+	for _, d := range data {
+		m := Find(d.Obj)
+		if m == nil || !m.Cf.Graph {
+			ctx.SendKVP(d.Obj, "skipped - no such graph")
+			continue
+		}
+
+		var lay *graph.Layout
+		if gl, ok := m.Me.(graphLayouter); ok {
+			lay = gl.GraphLayout()
+		}
+
+		n, err := graph.Import(m.Pathname("", ""), which, d.Data, lay)
+		if err != nil {
+			ctx.SendKVP(d.Obj, err.Error())
+			continue
+		}
+		m.Loggit("GRAPH", fmt.Sprintf("imported %d %s", n, which))
+		ctx.SendKVP(d.Obj, fmt.Sprintf("imported %d", n))
+	}
+
+	ctx.SendFinal()
+}
+
+// ################################################################
+
+func validWhich(which string) bool {
+
+	switch which {
+	case "samples", "hours", "days":
+		return true
+	}
+	return false
+}
+
+// objects with graphs, in the subtree
+func (m *M) graphObjs(subtree bool, seen map[*M]bool, res []*M) []*M {
+
+	if seen == nil {
+		seen = make(map[*M]bool)
+	}
+	if seen[m] {
+		return res
+	}
+	seen[m] = true
+
+	if m.Cf.Graph {
+		res = append(res, m)
+	}
+	if !subtree {
+		return res
+	}
+
+	m.Lock.RLock()
+	childs := m.Me.Children()
+	m.Lock.RUnlock()
+
+	for _, c := range childs {
+		res = c.graphObjs(true, seen, res)
+	}
+	return res
+}
+
+func graphExport(objs []*M, which string, start, end int64, tag string) []graphObjData {
+
+	if tag == "local" || tag == darp.MyId {
+		tag = ""
+	}
+
+	var res []graphObjData
+	for _, m := range objs {
+		res = append(res, graphObjData{m.Cf.Unique, graph.Export(m.Pathname(tag, ""), which, start, end)})
+	}
+	return res
+}
+
+func writeGraphCSV(w io.Writer, data []graphObjData) {
+
+	cw := csv.NewWriter(w)
+	cw.Write(graphCSVHeader)
+
+	f := func(v float32) string { return strconv.FormatFloat(float64(v), 'g', -1, 32) }
+
+	for _, d := range data {
+		for _, e := range d.Data {
+			cw.Write([]string{d.Obj, strconv.FormatInt(e.Time, 10), e.Status.String(),
+				f(e.Value), f(e.Min), f(e.Max), f(e.Stdev), f(e.Exp), f(e.Delt), f(e.P50), f(e.P90), f(e.P95), f(e.P99)})
+		}
+	}
+	cw.Flush()
+}
+
+// the header line determines the columns. object, time, value are required
+// object names starting with from are renamed to start with to
+func readGraphCSV(r io.Reader, from, to string) ([]graphObjData, error) {
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	hdr, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	col := make(map[string]int)
+	for i, h := range hdr {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, req := range []string{"object", "time", "value"} {
+		if _, ok := col[req]; !ok {
+			return nil, fmt.Errorf("missing column '%s'", req)
+		}
+	}
+
+	var res []graphObjData
+	idx := make(map[string]int)
+	lineno := 1
+
+	for {
+		rec, err := cr.Read()
+		lineno++
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		get := func(name string) string {
+			if i, ok := col[name]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		getf := func(name string) float32 {
+			v, _ := strconv.ParseFloat(get(name), 32)
+			return float32(v)
+		}
+
+		when, err := argus.ParseWhen(get("time"), 0, 0)
+		if err != nil || when <= 0 {
+			return nil, fmt.Errorf("line %d: invalid time '%s'", lineno, get("time"))
+		}
+
+		obj := get("object")
+		if from != "" && strings.HasPrefix(obj, from) {
+			obj = to + strings.TrimPrefix(obj, from)
+		}
+
+		st := argus.CLEAR
+		if s := get("status"); s != "" {
+			if n, err := strconv.Atoi(s); err == nil {
+				st = argus.Status(n)
+			} else {
+				st = argus.StatusValue(s)
+			}
+		}
+
+		p := &graph.Point{
+			Time:   when,
+			Status: st,
+			Value:  getf("value"),
+			Min:    getf("min"),
+			Max:    getf("max"),
+			Stdev:  getf("stdev"),
+			Exp:    getf("exp"),
+			Delt:   getf("delt"),
+			P50:    getf("p50"),
+			P90:    getf("p90"),
+			P95:    getf("p95"),
+			P99:    getf("p99"),
+		}
+
+		i, ok := idx[obj]
+		if !ok {
+			i = len(res)
+			idx[obj] = i
+			res = append(res, graphObjData{Obj: obj})
+		}
+		res[i].Data = append(res[i].Data, p)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-21 10:30 (EDT)
+// Function:
+
+package monel
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/graph"
+)
+
+func TestGraphCSV(t *testing.T) {
+
+	in := []graphObjData{
+		{"Top:Old:A", []*graph.Point{{Time: 1700000000, Status: argus.CLEAR, Value: 1.5}, {Time: 1700000060, Status: argus.MAJOR, Value: 2}}},
+		{"Top:Other", []*graph.Point{{Time: 1700000000, Status: argus.CLEAR, Value: 3, Min: 1, Max: 4}}},
+	}
+
+	var buf bytes.Buffer
+	writeGraphCSV(&buf, in)
+
+	out, err := readGraphCSV(&buf, "Top:Old:", "Top:New:")
+	if err != nil {
+		fmt.Printf("read: %v\n", err)
+		t.FailNow()
+	}
+
+	if len(out) != 2 || out[0].Obj != "Top:New:A" || out[1].Obj != "Top:Other" {
+		fmt.Printf("objs %+v\n", out)
+		t.FailNow()
+	}
+	if len(out[0].Data) != 2 || out[0].Data[1].Status != argus.MAJOR || out[0].Data[0].Value != 1.5 {
+		fmt.Printf("data %+v\n", out[0].Data[1])
+		t.Fail()
+	}
+	if out[1].Data[0].Max != 4 {
+		fmt.Printf("data %+v\n", out[1].Data[0])
+		t.Fail()
+	}
+
+	// columns in any order, status optional
+	out, err = readGraphCSV(strings.NewReader("value,time,object\n5,2026-01-02 03:04,Top:X\n"), "", "")
+	if err != nil || len(out) != 1 || out[0].Data[0].Value != 5 || out[0].Data[0].Status != argus.CLEAR {
+		fmt.Printf("read: %v %+v\n", err, out)
+		t.Fail()
+	}
+
+	_, err = readGraphCSV(strings.NewReader("object,value\nTop:X,1\n"), "", "")
+	if err == nil {
+		fmt.Printf("expected error\n")
+		t.Fail()
+	}
+}
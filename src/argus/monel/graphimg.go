@@ -0,0 +1,167 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 23:30 (EDT)
+// Function: graph images, for notifications + embedding
+
+package monel
+
+import (
+	"bytes"
+	"strconv"
+
+	"argus.domain/argus/api"
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/darp"
+	"argus.domain/argus/graph"
+	"argus.domain/argus/notify"
+	"argus.domain/argus/web"
+)
+
+const (
+	GRAPHIMGRANGE = 6 * 3600
+	GRAPHIMGMAX   = 2000 // pixels
+)
+
+func init() {
+	// public - permits signed links. otherwise the user is checked
+	web.Add(web.PUBLIC, "/api/graphimg", webGraphImage)
+	api.Add(true, "graphlink", apiGraphLink)
+}
+
+// obj, fmt, range, end, width, height, which, darp [, exp, sig]
+// signed links only get what was signed: obj + range, up to now
+func webGraphImage(ctx *web.Context) {
+
+	obj := ctx.Get("obj")
+	rng, err := argus.Timespec(ctx.Get("range"), 1)
+	if err != nil || rng <= 0 {
+		rng = GRAPHIMGRANGE
+	}
+
+	var m *M
+	sig := ctx.Get("sig")
+	if sig != "" {
+		exp, _ := strconv.ParseInt(ctx.Get("exp"), 10, 64)
+		if !notify.GraphLinkValid(obj, rng, exp, sig) {
+			dl.Debug("invalid graph link")
+			ctx.W.WriteHeader(403)
+			return
+		}
+		m = Find(obj)
+		if m == nil {
+			ctx.W.WriteHeader(404)
+			return
+		}
+	} else {
+		if ctx.User == nil {
+			ctx.W.WriteHeader(403)
+			return
+		}
+		m, _ = webObjUserCheck(ctx)
+		if m == nil {
+			return
+		}
+	}
+
+	if !m.Cf.Graph {
+		ctx.W.WriteHeader(404)
+		return
+	}
+
+	end := clock.Unix()
+	width, height := 600, 200
+	tag, which := "", ""
+
+	if sig == "" {
+		if e, _ := strconv.ParseInt(ctx.Get("end"), 10, 64); e != 0 {
+			end = e
+		}
+		width = graphImgSize(ctx.Get("width"), 600)
+		height = graphImgSize(ctx.Get("height"), 200)
+		tag = ctx.Get("darp")
+		which = ctx.Get("which")
+	}
+	if tag == "local" || tag == darp.MyId {
+		tag = ""
+	}
+
+	format := ctx.Get("fmt")
+	ctype := "image/png"
+	switch format {
+	case "", "png":
+		format = "png"
+	case "svg":
+		ctype = "image/svg+xml"
+	default:
+		ctx.W.WriteHeader(400)
+		return
+	}
+
+	title := m.Cf.Title
+	if title == "" {
+		title = m.Cf.Friendlyname
+	}
+
+	var buf bytes.Buffer
+	err = graph.Image(&buf, format, m.Pathname(tag, ""), &graph.ImageConf{
+		Title:  title,
+		YLabel: m.Cf.YLabel,
+		Width:  width,
+		Height: height,
+		Start:  end - rng,
+		End:    end,
+		Which:  which,
+	})
+	if err != nil {
+		dl.Debug("graph image: %v", err)
+		ctx.W.WriteHeader(500)
+		return
+	}
+
+	ctx.W.Header().Set("Content-Type", ctype)
+	ctx.W.Header().Set("Cache-Control", "max-age=60")
+	ctx.W.Write(buf.Bytes())
+}
+
+func graphImgSize(v string, def int) int {
+
+	n, _ := strconv.Atoi(v)
+	if n <= 0 {
+		return def
+	}
+	if n > GRAPHIMGMAX {
+		return GRAPHIMGMAX
+	}
+	return n
+}
+
+// a long-lived link, for wikis, dashboards, ...
+// argusctl graphlink obj=Top:Foo range=6h expire=365d
+func apiGraphLink(ctx *api.Context) {
+
+	m := Find(ctx.Args["obj"])
+	if m == nil || !m.Cf.Graph {
+		ctx.Send404()
+		return
+	}
+
+	rng, err := argus.Timespec(ctx.Args["range"], 1)
+	if err != nil || rng <= 0 {
+		rng = GRAPHIMGRANGE
+	}
+	exp, err := argus.Timespec(ctx.Args["expire"], 1)
+	if err != nil || exp <= 0 {
+		exp = 365 * 86400
+	}
+
+	link := notify.GraphLink(m.Cf.Unique, rng, clock.Unix()+exp)
+	if link == "" {
+		ctx.SendResponseFinal(500, "web_url is not configured")
+		return
+	}
+
+	ctx.SendOK()
+	ctx.SendKVP("url", link)
+	ctx.SendFinal()
+}
@@ -0,0 +1,551 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 19:22 (EDT)
+// Function: incidents - group related notifications
+
+package monel
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/config"
+	"argus.domain/argus/configure"
+	"argus.domain/argus/notify"
+	"argus.domain/argus/sched"
+)
+
+type IncidentConf struct {
+	Related_Time  int64 `cfconv:"timespec"` // related objects within this time are grouped
+	Group_By_Time bool  // group any objects within the time, related or not
+	Keep          int64 `cfconv:"timespec"`
+	Auto_Resolve  bool
+	ACL_Incident  string
+}
+
+type Incident struct {
+	Id       int
+	Title    string
+	Created  int64
+	Updated  int64
+	Resolved int64
+	Acked    bool
+	Severity argus.Status // worst seen
+	Objects  []*IncidentObj
+	Notifies []int
+	Notes    []TimelineDat // incident events + free-form notes
+}
+
+type IncidentObj struct {
+	Unique    string
+	Ancestors []string // parent first
+	Depends   []string
+	OvStatus  argus.Status
+}
+
+type TimelineDat struct {
+	When int64
+	Type string // incident, transition, override, annotation, notify, note, ...
+	Obj  string
+	Who  string
+	Msg  string
+}
+
+type incidentSave struct {
+	NextId int
+	List   []*Incident
+}
+
+type incidentEvent struct {
+	m      *M
+	n      *notify.N // nil => status transition
+	status argus.Status
+}
+
+var incidentCf = IncidentConf{
+	Related_Time: 3600,
+	Keep:         90 * 24 * 3600,
+	Auto_Resolve: true,
+	ACL_Incident: "staff root",
+}
+
+var incidentLock sync.RWMutex
+var incidents []*Incident
+var incidentId = 1
+
+// events are queued without limit, so the sender never waits
+var incidentQLock sync.Mutex
+var incidentQueue []incidentEvent
+var incidentWake = make(chan struct{}, 1)
+
+func ConfigureIncidents(cf *configure.CF) {
+
+	cf.InitFromConfig(&incidentCf, "incident", "incident_")
+	loadIncidents()
+	go incidentWorker()
+
+	sched.NewFunc(&sched.Conf{
+		Freq: 3600,
+		Text: "incident clean up",
+		Auto: true,
+	}, cleanIncidents)
+}
+
+// lock is already held, do the work elsewhere
+func (m *M) incidentAdd(n *notify.N, st argus.Status) {
+	queueIncident(incidentEvent{m, n, st})
+}
+
+// lock is already held
+// the status changed. update (maybe resolve) any incident it is in
+func (m *M) incidentTransition() {
+	queueIncident(incidentEvent{m, nil, m.P.OvStatus})
+}
+
+func queueIncident(ev incidentEvent) {
+
+	incidentQLock.Lock()
+	incidentQueue = append(incidentQueue, ev)
+	incidentQLock.Unlock()
+
+	select {
+	case incidentWake <- struct{}{}:
+	default:
+	}
+}
+
+func incidentWorker() {
+
+	for range incidentWake {
+		incidentDrain()
+	}
+}
+
+func incidentDrain() {
+
+	incidentQLock.Lock()
+	q := incidentQueue
+	incidentQueue = nil
+	incidentQLock.Unlock()
+
+	for _, ev := range q {
+		incidentProcess(ev)
+	}
+}
+
+func incidentProcess(ev incidentEvent) {
+
+	obj := ev.m.incidentObj(ev.status)
+	now := clock.Unix()
+
+	incidentLock.Lock()
+	defer incidentLock.Unlock()
+
+	if ev.n == nil {
+		incidentStatus(obj, now)
+	} else {
+		incidentNotify(obj, ev.n, now)
+	}
+}
+
+// incidentLock is held
+func incidentNotify(obj *IncidentObj, n *notify.N, now int64) {
+
+	if obj.OvStatus == argus.CLEAR {
+		// the up notification joins the open incident, the transition resolves it
+		inc := findIncidentWith(obj.Unique)
+		if inc == nil {
+			return
+		}
+		inc.addNotify(n)
+		inc.Updated = now
+		saveIncidents()
+		return
+	}
+
+	inc := findIncidentFor(obj, now)
+
+	if inc == nil {
+		title := obj.Unique
+		if n != nil {
+			title = n.Message()
+		}
+		inc = &Incident{
+			Id:      incidentId,
+			Title:   title,
+			Created: now,
+		}
+		incidentId++
+		incidents = append(incidents, inc)
+		inc.note(now, "incident", "", "system", "created")
+		dl.Verbose("incident #%d created for %s", inc.Id, obj.Unique)
+	}
+
+	inc.addObj(obj)
+	inc.addNotify(n)
+	inc.Updated = now
+	if obj.OvStatus > inc.Severity {
+		inc.Severity = obj.OvStatus
+	}
+	saveIncidents()
+}
+
+// incidentLock is held
+func incidentStatus(obj *IncidentObj, now int64) {
+
+	inc := findIncidentWith(obj.Unique)
+	if inc == nil {
+		return
+	}
+
+	inc.addObj(obj)
+	inc.Updated = now
+	inc.maybeAutoResolve(now)
+	saveIncidents()
+}
+
+func (m *M) incidentObj(st argus.Status) *IncidentObj {
+
+	m.Lock.RLock()
+	obj := &IncidentObj{
+		Unique:   m.Cf.Unique,
+		Depends:  append([]string(nil), m.Depends...),
+		OvStatus: st,
+	}
+	parent := m.Parent
+	m.Lock.RUnlock()
+
+	for len(parent) != 0 {
+		p := parent[0]
+		p.Lock.RLock()
+		obj.Ancestors = append(obj.Ancestors, p.Cf.Unique)
+		parent = p.Parent
+		p.Lock.RUnlock()
+	}
+
+	return obj
+}
+
+// incidentLock is held
+func findIncidentFor(obj *IncidentObj, now int64) *Incident {
+
+	// newest first
+	for i := len(incidents) - 1; i >= 0; i-- {
+		inc := incidents[i]
+		if inc.Resolved != 0 {
+			continue
+		}
+		if inc.hasObj(obj.Unique) {
+			return inc
+		}
+		if inc.Updated+incidentCf.Related_Time < now {
+			continue
+		}
+		if incidentCf.Group_By_Time {
+			return inc
+		}
+		for _, o := range inc.Objects {
+			if o.relatedTo(obj) {
+				return inc
+			}
+		}
+	}
+	return nil
+}
+
+// incidentLock is held
+func findIncidentWith(unique string) *Incident {
+
+	for i := len(incidents) - 1; i >= 0; i-- {
+		inc := incidents[i]
+		if inc.Resolved == 0 && inc.hasObj(unique) {
+			return inc
+		}
+	}
+	return nil
+}
+
+// shared ancestor (other than the top), or a dependency
+func (a *IncidentObj) relatedTo(b *IncidentObj) bool {
+
+	if contains(a.Ancestors, b.Unique) || contains(b.Ancestors, a.Unique) {
+		return true
+	}
+	if contains(a.Depends, b.Unique) || contains(b.Depends, a.Unique) {
+		return true
+	}
+	// siblings, cousins, ...
+	for _, aa := range a.Ancestors {
+		if aa == rootName(a) {
+			break
+		}
+		if contains(b.Ancestors, aa) {
+			return true
+		}
+	}
+	return false
+}
+
+func rootName(o *IncidentObj) string {
+
+	if len(o.Ancestors) == 0 {
+		return ""
+	}
+	return o.Ancestors[len(o.Ancestors)-1]
+}
+
+func contains(list []string, s string) bool {
+
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ################################################################
+
+func (inc *Incident) hasObj(unique string) bool {
+
+	for _, o := range inc.Objects {
+		if o.Unique == unique {
+			return true
+		}
+	}
+	return false
+}
+
+func (inc *Incident) addObj(obj *IncidentObj) {
+
+	for i, o := range inc.Objects {
+		if o.Unique == obj.Unique {
+			inc.Objects[i] = obj
+			return
+		}
+	}
+	inc.Objects = append(inc.Objects, obj)
+}
+
+func (inc *Incident) addNotify(n *notify.N) {
+
+	if n == nil {
+		return
+	}
+	inc.Notifies = append(inc.Notifies, n.IdNo())
+}
+
+func (inc *Incident) note(when int64, typ, obj, who, msg string) {
+	inc.Notes = append(inc.Notes, TimelineDat{when, typ, obj, who, msg})
+}
+
+func (inc *Incident) maybeAutoResolve(now int64) {
+
+	if !incidentCf.Auto_Resolve {
+		return
+	}
+	for _, o := range inc.Objects {
+		if o.OvStatus != argus.CLEAR {
+			return
+		}
+	}
+	inc.resolve(now, "auto")
+}
+
+// incidentLock is held
+func (inc *Incident) resolve(now int64, who string) {
+
+	if inc.Resolved != 0 {
+		return
+	}
+	inc.Resolved = now
+	inc.note(now, "incident", "", who, "resolved")
+	dl.Verbose("incident #%d resolved by %s", inc.Id, who)
+
+	inc.ack(now, who)
+}
+
+// incidentLock is held
+func (inc *Incident) ack(now int64, who string) {
+
+	if !inc.Acked {
+		inc.Acked = true
+		inc.note(now, "incident", "", who, "acked")
+	}
+
+	for _, id := range inc.Notifies {
+		if n := notify.Find(id); n != nil {
+			n.Ack(who)
+		}
+	}
+}
+
+// ################################################################
+
+// merge the incident notes, object logs, and notification logs
+func (inc *Incident) Timeline() []TimelineDat {
+
+	end := inc.Resolved
+	if end == 0 {
+		end = clock.Unix()
+	}
+
+	tl := append([]TimelineDat(nil), inc.Notes...)
+
+	for _, o := range inc.Objects {
+		m := Find(o.Unique)
+		if m == nil {
+			continue
+		}
+		m.Lock.RLock()
+		for _, l := range m.P.Log {
+			w := l.When / SECSNANO
+			if w < inc.Created || w > end {
+				continue
+			}
+			msg := l.Msg
+			if l.Tag == "TRANSITION" {
+				msg = l.OvStatus.String() + " " + l.Msg
+			}
+			tl = append(tl, TimelineDat{w, lowerTag(l.Tag), o.Unique, "", msg})
+		}
+		m.Lock.RUnlock()
+	}
+
+	for _, id := range inc.Notifies {
+		n := notify.Find(id)
+		if n == nil {
+			continue
+		}
+		for _, l := range n.Logs() {
+			tl = append(tl, TimelineDat{l.When, "notify", fmt.Sprintf("%d", id), l.Who, l.Msg})
+		}
+	}
+
+	sort.SliceStable(tl, func(i, j int) bool { return tl[i].When < tl[j].When })
+	return tl
+}
+
+func lowerTag(t string) string {
+
+	switch t {
+	case "TRANSITION":
+		return "transition"
+	case "OVERRIDE":
+		return "override"
+	case "ANNOTATION":
+		return "annotation"
+	}
+	return "info"
+}
+
+func findIncident(id int) *Incident {
+
+	for _, inc := range incidents {
+		if inc.Id == id {
+			return inc
+		}
+	}
+	return nil
+}
+
+// IncidentAction acks, resolves, or adds a note to an incident
+func IncidentAction(id int, act string, who string, text string) error {
+
+	now := clock.Unix()
+
+	incidentLock.Lock()
+	defer incidentLock.Unlock()
+
+	inc := findIncident(id)
+	if inc == nil {
+		return fmt.Errorf("no such incident")
+	}
+
+	switch act {
+	case "ack":
+		inc.ack(now, who)
+	case "resolve":
+		inc.resolve(now, who)
+	case "note":
+		if text == "" {
+			return fmt.Errorf("empty note")
+		}
+		inc.note(now, "note", "", who, text)
+	default:
+		return fmt.Errorf("invalid action '%s'", act)
+	}
+
+	inc.Updated = now
+	saveIncidents()
+	return nil
+}
+
+// ################################################################
+
+func cleanIncidents() {
+
+	if incidentCf.Keep == 0 {
+		return
+	}
+	old := clock.Unix() - incidentCf.Keep
+
+	incidentLock.Lock()
+	defer incidentLock.Unlock()
+
+	var keep []*Incident
+	for _, inc := range incidents {
+		if inc.Resolved != 0 && inc.Resolved < old {
+			continue
+		}
+		keep = append(keep, inc)
+	}
+
+	if len(keep) != len(incidents) {
+		incidents = keep
+		saveIncidents()
+	}
+}
+
+func loadIncidents() {
+
+	cf := config.Cf()
+	if cf.Datadir == "" {
+		dl.Debug("datadir not configured. not loading")
+		return
+	}
+
+	var is incidentSave
+	err := argus.Load(cf.Datadir+"/incidents", &is)
+	if err != nil {
+		dl.Debug("cannot open file: %v", err)
+		return
+	}
+
+	incidentLock.Lock()
+	defer incidentLock.Unlock()
+
+	incidents = is.List
+	if is.NextId > incidentId {
+		incidentId = is.NextId
+	}
+}
+
+// incidentLock is already held
+func saveIncidents() {
+
+	cf := config.Cf()
+	if cf.Datadir == "" {
+		dl.Debug("datadir not configured. not saving")
+		return
+	}
+	file := cf.Datadir + "/incidents"
+
+	err := argus.Save(file, &incidentSave{incidentId, incidents})
+	if err != nil {
+		dl.Problem("cannot save incidents to '%s': %v", file, err)
+	}
+}
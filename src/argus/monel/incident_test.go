@@ -0,0 +1,183 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-24 16:30 (EDT)
+// Function:
+
+package monel
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/notify"
+)
+
+func incidentReset() {
+	incidentQLock.Lock()
+	incidentQueue = nil
+	incidentQLock.Unlock()
+	incidentLock.Lock()
+	incidents = nil
+	incidentLock.Unlock()
+}
+
+func testObj(unique string, st argus.Status, anc ...string) *IncidentObj {
+	return &IncidentObj{Unique: unique, Ancestors: append(anc, "Top"), OvStatus: st}
+}
+
+func TestIncidentGroup(t *testing.T) {
+
+	incidentReset()
+	defer incidentReset()
+	now := clock.Unix()
+
+	incidentLock.Lock()
+	defer incidentLock.Unlock()
+
+	incidentNotify(testObj("Top:DB:pg1", argus.CRITICAL, "Top:DB"), nil, now)
+	// sibling => same incident
+	incidentNotify(testObj("Top:DB:pg2", argus.MAJOR, "Top:DB"), nil, now+10)
+	// unrelated, even though at the same time
+	incidentNotify(testObj("Top:Web:www", argus.CRITICAL, "Top:Web"), nil, now+10)
+	// depends on pg1
+	dep := testObj("Top:App:api", argus.CRITICAL, "Top:App")
+	dep.Depends = []string{"Top:DB:pg1"}
+	incidentNotify(dep, nil, now+20)
+
+	if len(incidents) != 2 {
+		fmt.Printf("incidents: %d\n", len(incidents))
+		t.FailNow()
+	}
+	if len(incidents[0].Objects) != 3 || incidents[0].Severity != argus.CRITICAL || !incidents[0].hasObj("Top:App:api") {
+		fmt.Printf("db incident: %d objects\n", len(incidents[0].Objects))
+		t.Fail()
+	}
+	if len(incidents[1].Objects) != 1 || !incidents[1].hasObj("Top:Web:www") {
+		fmt.Printf("web incident: %d objects\n", len(incidents[1].Objects))
+		t.Fail()
+	}
+
+	// related, but too late
+	incidentNotify(testObj("Top:DB:pg3", argus.MAJOR, "Top:DB"), nil, now+20+incidentCf.Related_Time+1)
+	if len(incidents) != 3 {
+		fmt.Printf("late incident not created\n")
+		t.Fail()
+	}
+}
+
+func TestIncidentGroupByTime(t *testing.T) {
+
+	incidentReset()
+	defer incidentReset()
+	defer func() { incidentCf.Group_By_Time = false }()
+	incidentCf.Group_By_Time = true
+	now := clock.Unix()
+
+	incidentLock.Lock()
+	defer incidentLock.Unlock()
+
+	incidentNotify(testObj("Top:DB:pg1", argus.CRITICAL, "Top:DB"), nil, now)
+	// unrelated, at the same time => same incident
+	incidentNotify(testObj("Top:Web:www", argus.CRITICAL, "Top:Web"), nil, now+10)
+	// too late
+	incidentNotify(testObj("Top:Mail:mx", argus.CRITICAL, "Top:Mail"), nil, now+10+incidentCf.Related_Time+1)
+
+	if len(incidents) != 2 || len(incidents[0].Objects) != 2 || !incidents[0].hasObj("Top:Web:www") {
+		fmt.Printf("incidents: %d\n", len(incidents))
+		t.Fail()
+	}
+}
+
+func TestIncidentResolve(t *testing.T) {
+
+	incidentReset()
+	defer incidentReset()
+	now := clock.Unix()
+
+	incidentLock.Lock()
+	defer incidentLock.Unlock()
+
+	incidentNotify(testObj("Top:DB:pg1", argus.CRITICAL, "Top:DB"), nil, now)
+	incidentNotify(testObj("Top:DB:pg2", argus.CRITICAL, "Top:DB"), nil, now)
+	inc := incidents[0]
+
+	// an up notification does not resolve it
+	incidentNotify(testObj("Top:DB:pg1", argus.CLEAR, "Top:DB"), nil, now+10)
+	if inc.Resolved != 0 {
+		fmt.Printf("resolved by notification\n")
+		t.Fail()
+	}
+
+	// one up, the other still down
+	incidentStatus(testObj("Top:DB:pg1", argus.CLEAR, "Top:DB"), now+10)
+	if inc.Resolved != 0 {
+		fmt.Printf("resolved while down\n")
+		t.Fail()
+	}
+
+	// not in the incident
+	incidentStatus(testObj("Top:Web:www", argus.CLEAR, "Top:Web"), now+10)
+	if len(inc.Objects) != 2 || len(incidents) != 1 {
+		fmt.Printf("unrelated transition added\n")
+		t.Fail()
+	}
+
+	incidentStatus(testObj("Top:DB:pg2", argus.CLEAR, "Top:DB"), now+20)
+	if inc.Resolved != now+20 || !inc.Acked {
+		fmt.Printf("not resolved\n")
+		t.Fail()
+	}
+
+	// resolved incidents do not collect new objects
+	incidentNotify(testObj("Top:DB:pg1", argus.CRITICAL, "Top:DB"), nil, now+30)
+	if len(incidents) != 2 {
+		fmt.Printf("resolved incident reused\n")
+		t.Fail()
+	}
+}
+
+func TestIncidentLocked(t *testing.T) {
+
+	incidentReset()
+	defer incidentReset()
+
+	top := &M{Cf: Conf{Unique: "Top"}}
+	m := &M{Cf: Conf{Unique: "Top:DB:pg1"}, Parent: []*M{{Cf: Conf{Unique: "Top:DB"}, Parent: []*M{top}}}}
+
+	// many events with the object locked, and no worker running
+	done := make(chan bool)
+	go func() {
+		m.Lock.Lock()
+		for i := 0; i < 200; i++ {
+			m.incidentAdd(&notify.N{}, argus.CRITICAL)
+		}
+		m.P.OvStatus = argus.CLEAR
+		m.incidentTransition()
+		m.Lock.Unlock()
+		done <- true
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		fmt.Printf("blocked\n")
+		t.FailNow()
+	}
+
+	incidentDrain()
+
+	incidentLock.RLock()
+	defer incidentLock.RUnlock()
+
+	if len(incidents) != 1 || len(incidents[0].Objects) != 1 || incidents[0].Resolved == 0 {
+		fmt.Printf("incidents: %d\n", len(incidents))
+		t.Fail()
+	}
+	if anc := incidents[0].Objects[0].Ancestors; len(anc) != 2 || anc[0] != "Top:DB" {
+		fmt.Printf("ancestors: %v\n", anc)
+		t.Fail()
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 20:05 (EDT)
+// Function: incidents - web, api, export
+
+package monel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"argus.domain/argus/api"
+	"argus.domain/argus/argus"
+	"argus.domain/argus/web"
+)
+
+type incidentExport struct {
+	Incident
+	IsOpen   bool
+	Timeline []TimelineDat
+}
+
+func init() {
+	web.Add(web.PRIVATE, "/api/listincident", webIncidentList)
+	web.Add(web.PRIVATE, "/api/incident", webIncident)
+	web.Add(web.WRITE, "/api/incidentupdate", webIncidentUpdate)
+	web.Add(web.PRIVATE, "/api/incidentexport", webIncidentExport)
+
+	api.Add(true, "incidents", apiIncidentList)
+	api.Add(true, "incident", apiIncident)
+	api.Add(true, "incidentupdate", apiIncidentUpdate)
+}
+
+// copy, with or without the timeline
+func getIncident(id int, timeline bool) *incidentExport {
+
+	incidentLock.RLock()
+	inc := findIncident(id)
+	if inc == nil {
+		incidentLock.RUnlock()
+		return nil
+	}
+	ie := inc.export()
+	incidentLock.RUnlock()
+
+	// the timeline needs object locks, do not hold the incident lock
+	if timeline {
+		ie.Timeline = ie.Incident.Timeline()
+	}
+	return ie
+}
+
+// incidentLock is held
+func (inc *Incident) export() *incidentExport {
+
+	ie := &incidentExport{Incident: *inc, IsOpen: inc.Resolved == 0}
+	ie.Objects = append([]*IncidentObj(nil), inc.Objects...)
+	ie.Notifies = append([]int(nil), inc.Notifies...)
+	ie.Notes = append([]TimelineDat(nil), inc.Notes...)
+	return ie
+}
+
+// newest first
+func listIncidents() []*incidentExport {
+
+	incidentLock.RLock()
+	defer incidentLock.RUnlock()
+
+	var res []*incidentExport
+	for _, inc := range incidents {
+		res = append(res, inc.export())
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Id > res[j].Id })
+	return res
+}
+
+// ################################################################
+
+func (ie *incidentExport) markdown() []byte {
+
+	var b bytes.Buffer
+
+	tfmt := func(t int64) string {
+		if t == 0 {
+			return "-"
+		}
+		return time.Unix(t, 0).Format("2006-01-02 15:04:05 MST")
+	}
+
+	fmt.Fprintf(&b, "# Incident %d: %s\n\n", ie.Id, ie.Title)
+	fmt.Fprintf(&b, "* Severity: %s\n", ie.Severity)
+	fmt.Fprintf(&b, "* Created: %s\n", tfmt(ie.Created))
+	fmt.Fprintf(&b, "* Resolved: %s\n", tfmt(ie.Resolved))
+	if ie.Resolved != 0 {
+		fmt.Fprintf(&b, "* Duration: %s\n", time.Duration(ie.Resolved-ie.Created)*time.Second)
+	}
+	fmt.Fprintf(&b, "* Acked: %v\n\n", ie.Acked)
+
+	fmt.Fprintf(&b, "## Objects\n\n")
+	for _, o := range ie.Objects {
+		fmt.Fprintf(&b, "* `%s` - %s\n", o.Unique, o.OvStatus)
+	}
+
+	fmt.Fprintf(&b, "\n## Timeline\n\n")
+	fmt.Fprintf(&b, "| Time | Type | Object | Who | Event |\n")
+	fmt.Fprintf(&b, "|------|------|--------|-----|-------|\n")
+	for _, t := range ie.Timeline {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", tfmt(t.When), t.Type, t.Obj, t.Who,
+			strings.Replace(t.Msg, "|", "\\|", -1))
+	}
+
+	return b.Bytes()
+}
+
+// ################################################################
+
+func webIncidentCreds(ctx *web.Context) bool {
+
+	if ctx.User == nil || !aclIncident(ctx.User.Groups) {
+		ctx.W.WriteHeader(403)
+		return false
+	}
+	return true
+}
+
+func aclIncident(groups string) bool {
+	return argus.ACLPermitsUser(incidentCf.ACL_Incident, strings.Fields(groups))
+}
+
+func webIncidentList(ctx *web.Context) {
+
+	if !webIncidentCreds(ctx) {
+		return
+	}
+
+	d := make(map[string]interface{})
+	d["list"] = listIncidents()
+
+	js, _ := json.MarshalIndent(d, "", "  ")
+	ctx.W.Header().Set("Content-Type", "application/json; charset=utf-8")
+	ctx.W.Write(js)
+}
+
+func webIncident(ctx *web.Context) {
+
+	if !webIncidentCreds(ctx) {
+		return
+	}
+
+	id, _ := strconv.Atoi(ctx.Get("id"))
+	ie := getIncident(id, true)
+	if ie == nil {
+		ctx.W.WriteHeader(404)
+		return
+	}
+
+	js, _ := json.MarshalIndent(ie, "", "  ")
+	ctx.W.Header().Set("Content-Type", "application/json; charset=utf-8")
+	ctx.W.Write(js)
+}
+
+func webIncidentUpdate(ctx *web.Context) {
+
+	if !webIncidentCreds(ctx) {
+		return
+	}
+
+	id, _ := strconv.Atoi(ctx.Get("id"))
+	err := IncidentAction(id, ctx.Get("act"), ctx.User.Name, ctx.Get("text"))
+	if err != nil {
+		dl.Debug("incident update: %v", err)
+		ctx.W.WriteHeader(400)
+		return
+	}
+
+	webIncident(ctx)
+}
+
+// fmt=md|json
+func webIncidentExport(ctx *web.Context) {
+
+	if !webIncidentCreds(ctx) {
+		return
+	}
+
+	id, _ := strconv.Atoi(ctx.Get("id"))
+	ie := getIncident(id, true)
+	if ie == nil {
+		ctx.W.WriteHeader(404)
+		return
+	}
+
+	if ctx.Get("fmt") == "json" {
+		js, _ := json.MarshalIndent(ie, "", "  ")
+		ctx.W.Header().Set("Content-Type", "application/json; charset=utf-8")
+		ctx.W.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=incident-%d.json", id))
+		ctx.W.Write(js)
+		return
+	}
+
+	ctx.W.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	ctx.W.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=incident-%d.md", id))
+	ctx.W.Write(ie.markdown())
+}
+
+// ################################################################
+
+func apiIncidentList(ctx *api.Context) {
+
+	ctx.SendOK()
+	for _, ie := range listIncidents() {
+		state := "resolved"
+		if ie.IsOpen {
+			state = "open"
+			if ie.Acked {
+				state = "acked"
+			}
+		}
+		ctx.SendKVP(fmt.Sprintf("%d", ie.Id), fmt.Sprintf("%s %s %d objects, %d notifies - %s",
+			state, ie.Severity, len(ie.Objects), len(ie.Notifies), ie.Title))
+	}
+	ctx.SendFinal()
+}
+
+// id=N [fmt=md|json]
+func apiIncident(ctx *api.Context) {
+
+	id, _ := strconv.Atoi(ctx.Args["id"])
+	ie := getIncident(id, true)
+	if ie == nil {
+		ctx.Send404()
+		return
+	}
+
+	ctx.SendOK()
+	if ctx.Args["fmt"] == "json" {
+		js, _ := json.MarshalIndent(ie, "", "  ")
+		ctx.Send(string(js))
+	} else {
+		ctx.Send(string(ie.markdown()))
+	}
+	ctx.SendFinal()
+}
+
+// id=N act=ack|resolve|note [text=...] [user=...]
+func apiIncidentUpdate(ctx *api.Context) {
+
+	id, _ := strconv.Atoi(ctx.Args["id"])
+	who := ctx.Args["user"]
+	if who == "" {
+		who = "api"
+	}
+
+	err := IncidentAction(id, ctx.Args["act"], who, ctx.Args["text"])
+	if err != nil {
+		ctx.SendResponseFinal(400, err.Error())
+		return
+	}
+	ctx.SendOKFinal()
+}
@@ -8,14 +8,17 @@ package monel
 import (
 	"expvar"
 	"fmt"
+	"math"
 	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 
 	"argus.domain/argus/argus"
 	"argus.domain/argus/clock"
 	"argus.domain/argus/configure"
+	"argus.domain/argus/history"
 	"github.com/jaw0/acdiag"
 	"argus.domain/argus/notify"
 )
@@ -57,6 +60,7 @@ type Conf struct {
 	Details      string
 	Comment      string
 	Depends      string
+	Tags         string
 	Debug        bool
 	Sort         bool
 	Overridable  bool
@@ -67,6 +71,13 @@ type Conf struct {
 	Siren        [argus.CRITICAL + 1]bool            `cfconv:"dotsev"`
 	Sendnotify   [argus.CRITICAL + 1]*argus.Schedule `cfconv:"dotsev"`
 	Gravity      argus.Gravity
+	Gravity_Down [argus.CRITICAL + 1]float64 `cfconv:"dotsev"` // gravity percent - more than this percent of children down
+	Gravity_Up   [argus.CRITICAL + 1]float64 `cfconv:"dotsev"` // gravity percent - fewer than this many children up
+	Weight       float64                     // relative to siblings, for gravity percent + sla rollups
+	SLO          float64                     // availability target percent, for sla reports
+	Flap_Window  int64                       `cfconv:"timespec"`
+	Flap_High    float64
+	Flap_Low     float64
 	ACL_Page     string
 	ACL_Override string
 	ACL_Annotate string
@@ -83,6 +94,10 @@ var defaults = Conf{
 	Overridable:  true,
 	Siren:        [...]bool{true, false, false, false, false, false},
 	Gravity:      argus.GRAV_DN,
+	Weight:       1,
+	Flap_Window:  3600,
+	Flap_High:    0, // disabled. eg. 6
+	Flap_Low:     3,
 	ACL_Page:     "user staff root",
 	ACL_Override: "staff root",
 	ACL_Annotate: "staff root",
@@ -90,6 +105,14 @@ var defaults = Conf{
 	ACL_About:    "root",
 }
 
+func init() {
+
+	for i := argus.UNKNOWN; i <= argus.CRITICAL; i++ {
+		defaults.Gravity_Down[i] = math.NaN()
+		defaults.Gravity_Up[i] = math.NaN()
+	}
+}
+
 type Persist struct {
 	Status          argus.Status
 	OvStatus        argus.Status
@@ -98,12 +121,21 @@ type Persist struct {
 	Result          string // not current, only as of the most recent transition
 	Reason          string
 	AncInOv         bool
+	InMaint         bool  // in a scheduled maintenance window
+	MaintIds        []int // open windows. they may overlap
+	AncInMaint      bool
 	Alarm           bool
 	OvStatusSummary []int // NB - mapstructure cannot array, it can slice...
 	Interesting     bool
 	TransTime       int64
 	SirenTime       int64
 	Culprit         string
+	Flapping        bool
+	FlapStart       int64
+	FlapCount       int          // transitions while flapping
+	FlapWorst       argus.Status // worst status while flapping
+	FlapNotified    bool
+	FlapTrans       []int64 // recent transitions
 	Stats           Stats
 	Log             []*Log
 }
@@ -127,6 +159,7 @@ type M struct {
 	Depends      []string
 	Notifies     []*notify.N
 	Interesting  bool
+	availLast    *availRec // most recent availability history record
 }
 
 func New(me Moneler, parent *M) *M {
@@ -171,6 +204,7 @@ func stopper(c chan *M) {
 
 	for m := range c {
 		m.Persist()
+		m.availStop()
 	}
 }
 
@@ -188,6 +222,14 @@ func (m *M) Config(conf *configure.CF) error {
 	m.Name = conf.Name
 
 	conf.InitFromConfig(&m.Cf, "monel", "")
+	m.Cf.Tags = strings.ToLower(m.Cf.Tags)
+
+	if m.Cf.Gravity == argus.GRAV_PCT && (conf.Type == "group" || conf.Type == "host") {
+		pct := &argus.GravityPct{Down: m.Cf.Gravity_Down[:], Up: m.Cf.Gravity_Up[:]}
+		if !pct.IsSet() {
+			conf.Warning("gravity percent, but no gravity_down or gravity_up configured")
+		}
+	}
 
 	if m.Cf.Passive {
 		for i := 0; i < len(m.Cf.Sendnotify); i++ {
@@ -504,7 +546,18 @@ func (m *M) Loggit(tag string, msg string) {
 	m.loggitL(tag, msg)
 }
 
+// the user who did it
+func (m *M) LoggitUser(tag string, user string, msg string) {
+	m.Lock.Lock()
+	defer m.Lock.Unlock()
+	m.loggitUL(tag, user, msg)
+}
+
 func (m *M) loggitL(tag string, msg string) {
+	m.loggitUL(tag, "", msg)
+}
+
+func (m *M) loggitUL(tag string, user string, msg string) {
 
 	if tag == "TRANSITION" && msg == "" {
 		msg = m.P.OvStatus.String()
@@ -513,6 +566,14 @@ func (m *M) loggitL(tag string, msg string) {
 	diag.Verbose("%s %s %s", m.Cf.Unique, tag, msg)
 	m.appendToLog(tag, msg)
 
+	history.Add(&history.Event{
+		Type:   strings.ToLower(tag),
+		Obj:    m.Cf.Unique,
+		Tags:   m.Cf.Tags,
+		User:   user,
+		Status: m.P.OvStatus,
+		Msg:    msg,
+	})
 }
 
 // ################################################################
@@ -25,7 +25,7 @@ func (m *M) SetOverride(ov *argus.Override) {
 		m.Loggit("OVERRIDE", ov.Text)
 	}
 	if ov.User != "" {
-		m.Loggit("OVERRIDE", "enabled by "+ov.User)
+		m.LoggitUser("OVERRIDE", ov.User, "enabled by "+ov.User)
 	}
 
 	m.Lock.Lock()
@@ -80,7 +80,7 @@ func (m *M) DelOverride(user string, reason string) {
 		m.Loggit("OVERRIDE", reason)
 	}
 	if user != "" {
-		m.Loggit("OVERRIDE", "removed by "+user)
+		m.LoggitUser("OVERRIDE", user, "removed by "+user)
 	}
 
 	m.Lock.Lock()
@@ -110,6 +110,7 @@ func (m *M) ovPropDown() {
 	for _, c := range childs {
 		c.Lock.Lock()
 		c.P.AncInOv = v
+		c.availUpdate()
 		c.Lock.Unlock()
 
 		c.ovPropDown()
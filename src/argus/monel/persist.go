@@ -97,6 +97,12 @@ func (m *M) Restore() {
 
 		m.setOverrideExpire()
 	}
+
+	if m.P.Flapping {
+		lock.Lock()
+		flappers[m.Cf.Unique] = m
+		lock.Unlock()
+	}
 }
 
 func (m *M) persist(pm map[string]interface{}) {
@@ -0,0 +1,463 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-23 17:30 (EDT)
+// Function: rest api - objects, status, overrides, annotations, check now
+
+package monel
+
+import (
+	"strconv"
+	"strings"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/notify"
+	"argus.domain/argus/web"
+)
+
+type restObject struct {
+	Unique     string
+	Label      string
+	Status     string
+	OvStatus   string
+	TransTime  int64
+	Override   *argus.Override `json:",omitempty"`
+	InOverride bool            // this or an ancestor
+	InMaint    bool
+	Annotation string
+	Flapping   bool
+	Children   int
+}
+
+type restObjectDetail struct {
+	restObject
+	Reason   string
+	Result   string
+	Culprit  string
+	Children []string
+	Log      []*Log
+}
+
+const (
+	RESTMAXLIST = 10000
+	RESTDEFLIST = 1000
+)
+
+var restObjParam = web.RESTParam{Name: "obj", Type: "string", Required: true, Desc: "object name, eg. Top:Servers:www"}
+
+func init() {
+	web.AddREST(&web.RESTRoute{
+		Method:  "GET",
+		Path:    "/status",
+		Scope:   "read",
+		Tag:     "status",
+		Summary: "overall status summary",
+		F:       restStatus,
+	})
+	web.AddREST(&web.RESTRoute{
+		Method:  "GET",
+		Path:    "/objects",
+		Scope:   "read",
+		Tag:     "objects",
+		Summary: "list + search objects in a subtree",
+		Params: []web.RESTParam{
+			{Name: "obj", Type: "string", Desc: "top of the subtree. default: Top"},
+			{Name: "depth", Type: "integer", Desc: "how deep to descend. 0 => all the way. default: 1"},
+			{Name: "search", Type: "string", Desc: "name or label contains"},
+			{Name: "tag", Type: "string", Desc: "objects with the tag"},
+			{Name: "status", Type: "string", Desc: "objects at least this severe (warning, minor, major, critical)"},
+			{Name: "limit", Type: "integer", Desc: "maximum number of results. default: 1000"},
+		},
+		F: restObjects,
+	})
+	web.AddREST(&web.RESTRoute{
+		Method:  "GET",
+		Path:    "/object",
+		Scope:   "read",
+		Tag:     "objects",
+		Summary: "an object's status + details",
+		Params:  []web.RESTParam{restObjParam},
+		F:       restObjectGet,
+	})
+	web.AddREST(&web.RESTRoute{
+		Method:  "POST",
+		Path:    "/override",
+		Scope:   "write",
+		Tag:     "overrides",
+		Summary: "set an override",
+		Params: []web.RESTParam{
+			restObjParam,
+			{Name: "text", Type: "string", Desc: "reason"},
+			{Name: "expires", Type: "string", Desc: "how long, eg. 2h. default: never"},
+			{Name: "auto", Type: "boolean", Desc: "remove automatically when the object recovers"},
+		},
+		F: restOverrideSet,
+	})
+	web.AddREST(&web.RESTRoute{
+		Method:  "DELETE",
+		Path:    "/override",
+		Scope:   "write",
+		Tag:     "overrides",
+		Summary: "remove an override",
+		Params:  []web.RESTParam{restObjParam},
+		F:       restOverrideDel,
+	})
+	web.AddREST(&web.RESTRoute{
+		Method:  "GET",
+		Path:    "/overrides",
+		Scope:   "read",
+		Tag:     "overrides",
+		Summary: "list objects in override",
+		F:       restOverrideList,
+	})
+	web.AddREST(&web.RESTRoute{
+		Method:  "POST",
+		Path:    "/annotation",
+		Scope:   "write",
+		Tag:     "annotations",
+		Summary: "set an annotation",
+		Params: []web.RESTParam{
+			restObjParam,
+			{Name: "text", Type: "string", Required: true, Desc: "the annotation"},
+		},
+		F: restAnnotate,
+	})
+	web.AddREST(&web.RESTRoute{
+		Method:  "DELETE",
+		Path:    "/annotation",
+		Scope:   "write",
+		Tag:     "annotations",
+		Summary: "remove an annotation",
+		Params:  []web.RESTParam{restObjParam},
+		F:       restAnnotate,
+	})
+	web.AddREST(&web.RESTRoute{
+		Method:  "POST",
+		Path:    "/checknow",
+		Scope:   "write",
+		Tag:     "objects",
+		Summary: "check an object (and its descendants) now",
+		Params:  []web.RESTParam{restObjParam},
+		F:       restCheckNow,
+	})
+}
+
+// find the object, check the acls
+func restObjCheck(ctx *web.Context, def string, acl func(*M) string) (*M, []string) {
+
+	obj := ctx.Get("obj")
+	if obj == "" {
+		obj = def
+	}
+	if obj == "" {
+		ctx.SendError(400, "obj required")
+		return nil, nil
+	}
+
+	m := Find(obj)
+	creds := strings.Fields(ctx.User.Groups)
+
+	if m == nil || !argus.ACLPermitsUser(m.Cf.ACL_Page, creds) {
+		// do not reveal what exists
+		ctx.SendError(404, "not found")
+		return nil, nil
+	}
+
+	if acl != nil && !argus.ACLPermitsUser(acl(m), creds) {
+		ctx.SendError(403, "not permitted")
+		return nil, nil
+	}
+
+	return m, creds
+}
+
+func (m *M) restExport() restObject {
+
+	m.Lock.RLock()
+	defer m.Lock.RUnlock()
+
+	label := m.Cf.Label
+	if label == "" {
+		label = m.Name
+	}
+
+	return restObject{
+		Unique:     m.Cf.Unique,
+		Label:      label,
+		Status:     m.P.Status.String(),
+		OvStatus:   m.P.OvStatus.String(),
+		TransTime:  m.P.TransTime,
+		Override:   m.P.Override,
+		InOverride: m.P.Override != nil || m.P.AncInOv,
+		InMaint:    m.P.InMaint || m.P.AncInMaint,
+		Annotation: m.P.Annotation,
+		Flapping:   m.P.Flapping,
+		Children:   len(m.Children),
+	}
+}
+
+// ################################################################
+
+func restStatus(ctx *web.Context) {
+
+	m, _ := restObjCheck(ctx, "Top", nil)
+	if m == nil {
+		return
+	}
+
+	m.Lock.RLock()
+	sum := make(map[string]int)
+	for sev, n := range m.P.OvStatusSummary {
+		if n != 0 {
+			sum[argus.Status(sev).String()] = n
+		}
+	}
+	st := m.P.OvStatus.String()
+	m.Lock.RUnlock()
+
+	ctx.SendJSON(200, map[string]interface{}{
+		"status":    st,
+		"summary":   sum,
+		"unacked":   notify.NumActive(),
+		"hasErrors": argus.HasErrors(),
+		"hasWarns":  argus.HasWarnings(),
+		"time":      clock.Unix(),
+	})
+}
+
+func restObjects(ctx *web.Context) {
+
+	m, creds := restObjCheck(ctx, "Top", nil)
+	if m == nil {
+		return
+	}
+
+	depth := 1
+	limit := RESTDEFLIST
+	var err error
+
+	if v := ctx.Get("depth"); v != "" {
+		depth, err = strconv.Atoi(v)
+		if err != nil || depth < 0 {
+			ctx.SendError(400, "invalid depth")
+			return
+		}
+	}
+	if v := ctx.Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			ctx.SendError(400, "invalid limit")
+			return
+		}
+		if limit > RESTMAXLIST {
+			limit = RESTMAXLIST
+		}
+	}
+
+	minsev := argus.CLEAR
+	if v := ctx.Get("status"); v != "" {
+		minsev = argus.StatusValue(v)
+		if minsev == argus.UNKNOWN {
+			ctx.SendError(400, "invalid status")
+			return
+		}
+	}
+
+	search := strings.ToLower(ctx.Get("search"))
+	tag := strings.ToLower(ctx.Get("tag"))
+
+	match := func(o restObject, c *M) bool {
+		if search != "" && !strings.Contains(strings.ToLower(o.Unique), search) &&
+			!strings.Contains(strings.ToLower(o.Label), search) {
+			return false
+		}
+		if tag != "" && !argus.IncludesTag(c.Cf.Tags, tag, false) {
+			return false
+		}
+		if minsev != argus.CLEAR && (argus.StatusValue(o.OvStatus) < minsev || argus.StatusValue(o.OvStatus) > argus.CRITICAL) {
+			return false
+		}
+		return true
+	}
+
+	list := []restObject{}
+	seen := make(map[*M]bool)
+	more := false
+
+	var walk func(*M, int)
+	walk = func(p *M, d int) {
+		p.Lock.RLock()
+		childs := p.Me.Children()
+		p.Lock.RUnlock()
+
+		for _, c := range childs {
+			c = c.Me.Self() // alias redirect
+			if seen[c] || c.Cf.Hidden || !argus.ACLPermitsUser(c.Cf.ACL_Page, creds) {
+				continue
+			}
+			seen[c] = true
+
+			if o := c.restExport(); match(o, c) {
+				if len(list) >= limit {
+					more = true
+					return
+				}
+				list = append(list, o)
+			}
+			if depth == 0 || d < depth {
+				walk(c, d+1)
+			}
+		}
+	}
+	walk(m, 1)
+
+	ctx.SendJSON(200, map[string]interface{}{
+		"obj":       m.Cf.Unique,
+		"list":      list,
+		"truncated": more,
+	})
+}
+
+func restObjectGet(ctx *web.Context) {
+
+	m, creds := restObjCheck(ctx, "", nil)
+	if m == nil {
+		return
+	}
+
+	d := restObjectDetail{restObject: m.restExport(), Children: []string{}}
+
+	m.Lock.RLock()
+	d.Reason = m.P.Reason
+	d.Result = m.P.Result
+	d.Culprit = m.P.Culprit
+	childs := m.Me.Children()
+	for i := len(m.P.Log) - 1; i >= 0 && len(d.Log) < WEBMAXLOG; i-- {
+		d.Log = append(d.Log, m.P.Log[i])
+	}
+	m.Lock.RUnlock()
+
+	for _, c := range childs {
+		if c.Cf.Hidden || !argus.ACLPermitsUser(c.Cf.ACL_Page, creds) {
+			continue
+		}
+		d.Children = append(d.Children, c.Cf.Unique)
+	}
+
+	ctx.SendJSON(200, d)
+}
+
+// ################################################################
+
+func restOverrideSet(ctx *web.Context) {
+
+	m, _ := restObjCheck(ctx, "", func(m *M) string { return m.Cf.ACL_Override })
+	if m == nil {
+		return
+	}
+	if !m.Cf.Overridable {
+		ctx.SendError(403, "not overridable")
+		return
+	}
+
+	var expires int64
+	if e := ctx.Get("expires"); e != "" {
+		dur, err := argus.Timespec(e, 1)
+		if err != nil || dur <= 0 {
+			ctx.SendError(400, "invalid expires")
+			return
+		}
+		expires = clock.Unix() + dur
+	}
+
+	m.SetOverride(&argus.Override{
+		User:    ctx.User.Name,
+		Text:    ctx.Get("text"),
+		Auto:    argus.CheckBool(ctx.Get("auto")),
+		Expires: expires,
+	})
+
+	ctx.SendJSON(200, m.restExport())
+}
+
+func restOverrideDel(ctx *web.Context) {
+
+	m, _ := restObjCheck(ctx, "", func(m *M) string { return m.Cf.ACL_Override })
+	if m == nil {
+		return
+	}
+
+	m.Lock.RLock()
+	inov := m.P.Override != nil
+	m.Lock.RUnlock()
+
+	if !inov {
+		ctx.SendError(404, "not in override")
+		return
+	}
+
+	m.DelOverride(ctx.User.Name, "")
+	ctx.SendJSON(200, m.restExport())
+}
+
+func restOverrideList(ctx *web.Context) {
+
+	creds := strings.Fields(ctx.User.Groups)
+
+	lock.RLock()
+	var all []*M
+	for _, m := range inoverride {
+		all = append(all, m)
+	}
+	lock.RUnlock()
+
+	list := []restObject{}
+	for _, m := range all {
+		if argus.ACLPermitsUser(m.Cf.ACL_Page, creds) {
+			list = append(list, m.restExport())
+		}
+	}
+
+	ctx.SendJSON(200, map[string]interface{}{"list": list})
+}
+
+// POST sets, DELETE removes
+func restAnnotate(ctx *web.Context) {
+
+	m, _ := restObjCheck(ctx, "", func(m *M) string { return m.Cf.ACL_Annotate })
+	if m == nil {
+		return
+	}
+
+	text := ctx.Get("text")
+	if ctx.R.Method == "DELETE" {
+		text = ""
+	} else if text == "" {
+		ctx.SendError(400, "text required")
+		return
+	}
+
+	m.Lock.Lock()
+	m.P.Annotation = text
+	m.WebTime = clock.Nano()
+	m.Lock.Unlock()
+
+	if text == "" {
+		m.LoggitUser("ANNOTATION", ctx.User.Name, "removed by "+ctx.User.Name)
+	} else {
+		m.LoggitUser("ANNOTATION", ctx.User.Name, "added by "+ctx.User.Name)
+	}
+
+	ctx.SendJSON(200, m.restExport())
+}
+
+func restCheckNow(ctx *web.Context) {
+
+	m, _ := restObjCheck(ctx, "", func(m *M) string { return m.Cf.ACL_CheckNow })
+	if m == nil {
+		return
+	}
+
+	m.checkNow()
+	ctx.SendJSON(200, m.restExport())
+}
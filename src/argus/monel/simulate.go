@@ -0,0 +1,208 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 22:58 (EDT)
+// Function: notification dry-run
+
+package monel
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"argus.domain/argus/api"
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/darp"
+	"argus.domain/argus/notify"
+	"argus.domain/argus/web"
+)
+
+type SimResult struct {
+	Notify bool   // would a notification be created?
+	Why    string // if not, why not
+	Notes  []string
+	Plan   *notify.Plan
+}
+
+func init() {
+	web.Add(web.PRIVATE, "/api/notifysim", webSimulate)
+	api.Add(true, "notifysim", apiSimulate)
+}
+
+// SimulateNotify - what if the object went to status at the time?
+func (m *M) SimulateNotify(st argus.Status, when time.Time) *SimResult {
+
+	m.Lock.RLock()
+	defer m.Lock.RUnlock()
+
+	res := &SimResult{}
+
+	switch {
+	case m.NotifyCf == nil:
+		res.Why = "object does not send notifications"
+		return res
+	case st < argus.CLEAR || st > argus.CRITICAL:
+		res.Why = "invalid status"
+		return res
+	case !m.permitNotifyAt(st, when):
+		res.Why = "sendnotify not permitted at that time"
+		return res
+	}
+
+	if m.P.OvStatus == argus.OVERRIDE || m.P.AncInOv {
+		res.Notes = append(res.Notes, "the object is currently in override, nothing would be sent now")
+	}
+	if m.P.OvStatus == argus.DEPENDS {
+		res.Notes = append(res.Notes, "the object is currently depending, nothing would be sent now")
+	}
+
+	prev := argus.CLEAR
+	if st == argus.CLEAR {
+		prev = argus.CRITICAL
+	}
+
+	res.Notify = true
+	res.Plan = notify.Simulate(&notify.NewConf{
+		Unique:       m.Cf.Unique,
+		FriendlyName: m.Cf.Friendlyname,
+		ShortName:    m.Cf.Label,
+		Conf:         m.NotifyCf,
+		Reason:       "simulated",
+		Tags:         m.Cf.Tags,
+		Darp:         darp.MyId,
+		OvStatus:     st,
+		PrevOv:       prev,
+	}, when)
+
+	if len(res.Plan.Sends) == 0 {
+		res.Notify = false
+		res.Why = "nowhere to send"
+	}
+
+	return res
+}
+
+// unix time, "2006-01-02 15:04", "15:04", "sat 03:00" (the next saturday)
+func parseSimTime(s string, now time.Time) (time.Time, error) {
+
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return now, nil
+	}
+
+	if t, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(t, 0), nil
+	}
+
+	for _, f := range []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02 15:04:05"} {
+		if t, err := time.ParseInLocation(f, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	f := strings.Fields(s)
+	hm := f[len(f)-1]
+	t, err := time.ParseInLocation("15:04", hm, now.Location())
+	if err != nil {
+		return now, fmt.Errorf("invalid time '%s'", s)
+	}
+	t = time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+
+	switch len(f) {
+	case 1:
+		return t, nil
+	case 2:
+		dow := -1
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if strings.HasPrefix(strings.ToLower(d.String()), strings.ToLower(f[0])) && len(f[0]) >= 3 {
+				dow = int(d)
+			}
+		}
+		if dow == -1 {
+			return now, fmt.Errorf("invalid day '%s'", f[0])
+		}
+		days := (dow - int(t.Weekday()) + 7) % 7
+		t = t.AddDate(0, 0, days)
+		if t.Before(now) {
+			t = t.AddDate(0, 0, 7)
+		}
+		return t, nil
+	}
+
+	return now, fmt.Errorf("invalid time '%s'", s)
+}
+
+// obj=Top:DB status=critical time=...
+func simulateArgs(get func(string) string) (*SimResult, error) {
+
+	m := Find(get("obj"))
+	if m == nil {
+		return nil, fmt.Errorf("object not found")
+	}
+
+	st := argus.CRITICAL
+	if s := get("status"); s != "" {
+		st = argus.StatusValue(s)
+		if st == argus.UNKNOWN {
+			return nil, fmt.Errorf("invalid status '%s'", s)
+		}
+	}
+
+	when, err := parseSimTime(get("time"), clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return m.SimulateNotify(st, when), nil
+}
+
+func webSimulate(ctx *web.Context) {
+
+	m, creds := webObjUserCheck(ctx)
+	if m == nil {
+		return
+	}
+
+	if !notify.PermitsDetail(creds) {
+		dl.Debug("denied")
+		ctx.W.WriteHeader(403)
+		return
+	}
+
+	res, err := simulateArgs(ctx.Get)
+	if err != nil {
+		dl.Debug("simulate: %v", err)
+		ctx.W.WriteHeader(400)
+		return
+	}
+
+	js, _ := json.MarshalIndent(res, "", "  ")
+	ctx.W.Header().Set("Content-Type", "application/json; charset=utf-8")
+	ctx.W.Write(js)
+}
+
+func apiSimulate(ctx *api.Context) {
+
+	res, err := simulateArgs(func(k string) string { return ctx.Args[k] })
+	if err != nil {
+		ctx.SendResponseFinal(400, err.Error())
+		return
+	}
+
+	ctx.SendOK()
+	if res.Why != "" {
+		ctx.SendKVP("result", res.Why)
+	}
+	for _, n := range res.Notes {
+		ctx.SendKVP("note", n)
+	}
+	if res.Plan != nil {
+		for i, l := range res.Plan.Lines() {
+			ctx.SendKVP(fmt.Sprintf("plan%d", i), l)
+		}
+	}
+	ctx.SendFinal()
+}
@@ -0,0 +1,431 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-22 11:30 (EDT)
+// Function: sla / availability reports, over arbitrary periods
+
+package monel
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"argus.domain/argus/api"
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/configure"
+	"argus.domain/argus/web"
+)
+
+type SLAConf struct {
+	Keep int64 `cfconv:"timespec"` // how long to keep availability history
+}
+
+type SLAReport struct {
+	Unique     string
+	Label      string
+	Depth      int
+	Weight     float64
+	Up         int64 // seconds
+	Down       int64
+	Excluded   int64 // override + maintenance
+	NoData     int64
+	NDown      int
+	Avail      float64 // percent of counted time
+	Rollup     float64 // weighted, over the subtree
+	SLO        float64 // target percent
+	Budget     int64   // allowed down time
+	BudgetUsed float64 // percent
+	Met        bool
+	Downtime   string
+}
+
+type slaResult struct {
+	Start    int64
+	End      int64
+	Period   string
+	Severity argus.Status
+	List     []*SLAReport
+}
+
+type slaRequest struct {
+	start   int64
+	end     int64
+	period  string
+	sev     argus.Status
+	subtree bool
+	permit  func(*M) bool
+	seen    map[*M]bool
+	list    []*SLAReport
+}
+
+type availTotals struct {
+	Up       int64
+	Down     int64
+	Excluded int64
+	NoData   int64
+	NDown    int
+}
+
+var slaCf = SLAConf{
+	Keep: 400 * 24 * 3600,
+}
+
+var slaCSVHeader = []string{"object", "label", "depth", "weight", "up", "down", "excluded", "nodata", "ndown",
+	"avail", "rollup", "slo", "budget", "budgetused", "met"}
+
+func init() {
+	web.Add(web.PRIVATE, "/api/sla", webSLA)
+	api.Add(true, "sla", apiSLA)
+}
+
+func ConfigureSLA(cf *configure.CF) {
+
+	cf.InitFromConfig(&slaCf, "sla", "sla_")
+}
+
+// ################################################################
+
+// obj, period, start, end, subtree, severity, fmt
+func webSLA(ctx *web.Context) {
+
+	m, creds := webObjUserCheck(ctx)
+	if m == nil {
+		return
+	}
+
+	r, err := newSLARequest(ctx.Get, clock.Unix())
+	if err != nil {
+		ctx.W.WriteHeader(400)
+		return
+	}
+	r.permit = func(c *M) bool { return argus.ACLPermitsUser(c.Cf.ACL_Page, creds) }
+
+	res := r.run(m)
+
+	var buf bytes.Buffer
+	switch ctx.Get("fmt") {
+	case "csv":
+		writeSLACSV(&buf, res)
+		ctx.W.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		ctx.W.Header().Set("Content-Disposition", "attachment; filename=\"sla.csv\"")
+	case "", "json":
+		js, _ := json.MarshalIndent(res, "", "  ")
+		buf.Write(js)
+		ctx.W.Header().Set("Content-Type", "application/json; charset=utf-8")
+	default:
+		ctx.W.WriteHeader(400)
+		return
+	}
+
+	ctx.W.Write(buf.Bytes())
+}
+
+// argusctl -q sla obj=Top:Customer subtree=yes period=2026-Q3 fmt=csv
+func apiSLA(ctx *api.Context) {
+
+	m := Find(ctx.Args["obj"])
+	if m == nil {
+		ctx.Send404()
+		return
+	}
+
+	r, err := newSLARequest(func(k string) string { return ctx.Args[k] }, clock.Unix())
+	if err != nil {
+		ctx.SendResponseFinal(400, err.Error())
+		return
+	}
+
+	res := r.run(m)
+
+	var buf bytes.Buffer
+	switch ctx.Args["fmt"] {
+	case "", "csv":
+		writeSLACSV(&buf, res)
+	case "json":
+		js, _ := json.MarshalIndent(res, "", "  ")
+		buf.Write(js)
+		buf.WriteString("\n")
+	default:
+		ctx.SendResponseFinal(400, "invalid format")
+		return
+	}
+
+	ctx.SendOK()
+	ctx.Send(buf.String())
+	ctx.SendFinal()
+}
+
+// ################################################################
+
+func newSLARequest(get func(string) string, now int64) (*slaRequest, error) {
+
+	r := &slaRequest{
+		period:  get("period"),
+		sev:     argus.WARNING,
+		subtree: argus.CheckBool(get("subtree")),
+		permit:  func(*M) bool { return true },
+		seen:    make(map[*M]bool),
+	}
+
+	if s := get("severity"); s != "" {
+		r.sev = argus.StatusValue(s)
+		if r.sev == argus.UNKNOWN || r.sev == argus.CLEAR {
+			return nil, fmt.Errorf("invalid severity '%s'", s)
+		}
+	}
+
+	var err error
+	switch {
+	case get("start") != "":
+		r.period = ""
+		r.start, err = argus.ParseWhen(get("start"), now, 0)
+		if err != nil {
+			return nil, err
+		}
+		r.end, err = argus.ParseWhen(get("end"), now, now)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		if r.period == "" {
+			r.period = "thismonth"
+		}
+		r.start, r.end, err = slaPeriod(r.period, time.Unix(now, 0))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the future is not yet available
+	if r.end > now {
+		r.end = now
+	}
+	if r.end <= r.start {
+		return nil, fmt.Errorf("invalid time range")
+	}
+
+	return r, nil
+}
+
+func (r *slaRequest) run(m *M) *slaResult {
+
+	r.report(m, 0)
+	return &slaResult{Start: r.start, End: r.end, Period: r.period, Severity: r.sev, List: r.list}
+}
+
+// returns the rollup, and whether anything was counted
+func (r *slaRequest) report(m *M, depth int) (float64, bool) {
+
+	if r.seen[m] || !r.permit(m) {
+		return 0, false
+	}
+	r.seen[m] = true
+
+	tot := availTally(availRead(m.availFile()), r.start, r.end, r.sev)
+
+	m.Lock.RLock()
+	childs := m.Children
+	m.Lock.RUnlock()
+
+	label := m.Cf.Label
+	if label == "" {
+		label = m.Name
+	}
+
+	rep := &SLAReport{
+		Unique:   m.Cf.Unique,
+		Label:    label,
+		Depth:    depth,
+		Weight:   m.Cf.Weight,
+		Up:       tot.Up,
+		Down:     tot.Down,
+		Excluded: tot.Excluded,
+		NoData:   tot.NoData,
+		NDown:    tot.NDown,
+		SLO:      m.Cf.SLO,
+		Avail:    100,
+		Downtime: argus.Elapsed(tot.Down),
+	}
+	r.list = append(r.list, rep)
+
+	counted := tot.Up+tot.Down > 0
+	if counted {
+		rep.Avail = 100 * float64(tot.Up) / float64(tot.Up+tot.Down)
+	}
+	rep.Rollup = rep.Avail
+
+	if r.subtree {
+		var sum, wsum float64
+
+		for _, c := range childs {
+			v, ok := r.report(c, depth+1)
+			if ok && c.Cf.Weight > 0 {
+				sum += c.Cf.Weight * v
+				wsum += c.Cf.Weight
+			}
+		}
+		if wsum > 0 {
+			rep.Rollup = sum / wsum
+			counted = true
+		}
+	}
+
+	rep.errorBudget(tot.Up + tot.Down)
+
+	return rep.Rollup, counted
+}
+
+// how much of the allowed down time has been used?
+func (rep *SLAReport) errorBudget(counted int64) {
+
+	if rep.SLO <= 0 {
+		return
+	}
+
+	rep.Met = rep.Rollup >= rep.SLO
+	rep.Budget = int64(math.Round((100 - rep.SLO) / 100 * float64(counted)))
+
+	switch {
+	case rep.Rollup >= 100:
+		rep.BudgetUsed = 0
+	case rep.SLO >= 100:
+		rep.BudgetUsed = 100
+	default:
+		rep.BudgetUsed = 100 * (100 - rep.Rollup) / (100 - rep.SLO)
+	}
+}
+
+// ################################################################
+
+// add up the time spent in each state
+// each record holds until the next one. before the first, there is no data
+func availTally(recs []availRec, start, end int64, sev argus.Status) *availTotals {
+
+	tot := &availTotals{}
+	var cur *availRec
+	t := start
+
+	for i := range recs {
+		rec := &recs[i]
+		if rec.When >= end {
+			break
+		}
+		if rec.When > t {
+			tot.add(cur, rec.When-t, sev)
+			t = rec.When
+		}
+		if rec.When >= start && availDown(rec, sev) && (cur == nil || !availDown(cur, sev)) {
+			tot.NDown++
+		}
+		cur = rec
+	}
+
+	if end > t {
+		tot.add(cur, end-t, sev)
+	}
+
+	return tot
+}
+
+func (tot *availTotals) add(rec *availRec, dt int64, sev argus.Status) {
+
+	switch {
+	case rec == nil || rec.Excl == EXCL_NODATA || argus.Status(rec.Status) == argus.UNKNOWN:
+		tot.NoData += dt
+	case rec.Excl != EXCL_NONE:
+		tot.Excluded += dt
+	case availDown(rec, sev):
+		tot.Down += dt
+	default:
+		tot.Up += dt
+	}
+}
+
+func availDown(rec *availRec, sev argus.Status) bool {
+
+	st := argus.Status(rec.Status)
+	return rec.Excl == EXCL_NONE && st >= sev && st <= argus.CRITICAL
+}
+
+// ################################################################
+
+var rePeriodMonth = regexp.MustCompile(`^(\d{4})-(\d{1,2})$`)
+var rePeriodQuarter = regexp.MustCompile(`^(\d{4})-[Qq]([1-4])$`)
+var rePeriodYear = regexp.MustCompile(`^(\d{4})$`)
+
+// today, lastmonth, 2026-Q3, 2026-07, 2026, ...
+func slaPeriod(p string, now time.Time) (int64, int64, error) {
+
+	y, mo, d := now.Date()
+	loc := now.Location()
+
+	day := func(y int, m time.Month, d int) int64 { return time.Date(y, m, d, 0, 0, 0, 0, loc).Unix() }
+	qtr := time.Month((int(mo)-1)/3*3 + 1)
+
+	switch strings.ToLower(p) {
+	case "today":
+		return day(y, mo, d), day(y, mo, d+1), nil
+	case "yesterday":
+		return day(y, mo, d-1), day(y, mo, d), nil
+	case "thismonth":
+		return day(y, mo, 1), day(y, mo+1, 1), nil
+	case "lastmonth":
+		return day(y, mo-1, 1), day(y, mo, 1), nil
+	case "thisquarter":
+		return day(y, qtr, 1), day(y, qtr+3, 1), nil
+	case "lastquarter":
+		return day(y, qtr-3, 1), day(y, qtr, 1), nil
+	case "thisyear":
+		return day(y, 1, 1), day(y+1, 1, 1), nil
+	case "lastyear":
+		return day(y-1, 1, 1), day(y, 1, 1), nil
+	}
+
+	if v := rePeriodMonth.FindStringSubmatch(p); v != nil {
+		py, _ := strconv.Atoi(v[1])
+		pm, _ := strconv.Atoi(v[2])
+		if pm >= 1 && pm <= 12 {
+			return day(py, time.Month(pm), 1), day(py, time.Month(pm+1), 1), nil
+		}
+	}
+	if v := rePeriodQuarter.FindStringSubmatch(p); v != nil {
+		py, _ := strconv.Atoi(v[1])
+		pq, _ := strconv.Atoi(v[2])
+		pm := time.Month((pq-1)*3 + 1)
+		return day(py, pm, 1), day(py, pm+3, 1), nil
+	}
+	if v := rePeriodYear.FindStringSubmatch(p); v != nil {
+		py, _ := strconv.Atoi(v[1])
+		return day(py, 1, 1), day(py+1, 1, 1), nil
+	}
+
+	return 0, 0, fmt.Errorf("invalid period '%s'", p)
+}
+
+func writeSLACSV(w io.Writer, res *slaResult) {
+
+	cw := csv.NewWriter(w)
+	cw.Write(slaCSVHeader)
+
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) }
+	n := func(v int64) string { return strconv.FormatInt(v, 10) }
+
+	for _, r := range res.List {
+		slo := ""
+		if r.SLO > 0 {
+			slo = f(r.SLO)
+		}
+		cw.Write([]string{r.Unique, r.Label, strconv.Itoa(r.Depth), f(r.Weight), n(r.Up), n(r.Down), n(r.Excluded),
+			n(r.NoData), strconv.Itoa(r.NDown), f(r.Avail), f(r.Rollup), slo, n(r.Budget), f(r.BudgetUsed),
+			strconv.FormatBool(r.Met)})
+	}
+	cw.Flush()
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-22 13:05 (EDT)
+// Function:
+
+package monel
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"argus.domain/argus/argus"
+)
+
+func TestAvailTally(t *testing.T) {
+
+	recs := []availRec{
+		{When: 1000, Status: uint8(argus.CLEAR)},
+		{When: 2000, Status: uint8(argus.MAJOR)},
+		{When: 2100, Status: uint8(argus.MAJOR), Excl: EXCL_MAINT},
+		{When: 2500, Status: uint8(argus.CLEAR)},
+		{When: 2600, Status: uint8(argus.WARNING)},
+		{When: 2700, Status: uint8(argus.UNKNOWN), Excl: EXCL_NODATA},
+		{When: 2800, Status: uint8(argus.CLEAR)},
+	}
+
+	tot := availTally(recs, 500, 3000, argus.WARNING)
+
+	// 500-1000 no data, 1000-2000 up, 2000-2100 down, 2100-2500 maint
+	// 2500-2600 up, 2600-2700 down, 2700-2800 no data, 2800-3000 up
+	if tot.Up != 1300 || tot.Down != 200 || tot.Excluded != 400 || tot.NoData != 600 || tot.NDown != 2 {
+		fmt.Printf("tally %+v\n", tot)
+		t.Fail()
+	}
+
+	// warnings do not count
+	tot = availTally(recs, 1500, 3000, argus.MAJOR)
+	if tot.Down != 100 || tot.NDown != 1 {
+		fmt.Printf("tally major %+v\n", tot)
+		t.Fail()
+	}
+
+	rep := &SLAReport{Rollup: 99.95, SLO: 99.9}
+	rep.errorBudget(100000)
+	if !rep.Met || rep.Budget != 100 || rep.BudgetUsed < 49.9 || rep.BudgetUsed > 50.1 {
+		fmt.Printf("budget %+v\n", rep)
+		t.Fail()
+	}
+}
+
+func TestSLAPeriod(t *testing.T) {
+
+	now := time.Date(2026, 10, 22, 13, 0, 0, 0, time.UTC)
+	day := func(y int, m time.Month, d int) int64 { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() }
+
+	tests := []struct {
+		p     string
+		start int64
+		end   int64
+	}{
+		{"yesterday", day(2026, 10, 21), day(2026, 10, 22)},
+		{"lastmonth", day(2026, 9, 1), day(2026, 10, 1)},
+		{"thisquarter", day(2026, 10, 1), day(2027, 1, 1)},
+		{"lastquarter", day(2026, 7, 1), day(2026, 10, 1)},
+		{"2026-Q3", day(2026, 7, 1), day(2026, 10, 1)},
+		{"2025-12", day(2025, 12, 1), day(2026, 1, 1)},
+		{"2025", day(2025, 1, 1), day(2026, 1, 1)},
+	}
+
+	for _, x := range tests {
+		s, e, err := slaPeriod(x.p, now)
+		if err != nil || s != x.start || e != x.end {
+			fmt.Printf("period %s: %d - %d, %v\n", x.p, s, e, err)
+			t.Fail()
+		}
+	}
+
+	if _, _, err := slaPeriod("2026-Q5", now); err == nil {
+		fmt.Printf("2026-Q5 should fail\n")
+		t.Fail()
+	}
+}
+
+func TestAvailTrim(t *testing.T) {
+
+	f, err := os.CreateTemp("", "avail")
+	if err != nil {
+		t.FailNow()
+	}
+	file := f.Name()
+	f.Close()
+	defer os.Remove(file)
+
+	for i := int64(1); i <= 5; i++ {
+		availAppend(file, &availRec{When: i * 100, Status: uint8(argus.CLEAR)})
+	}
+
+	availTrim(file, 350)
+	recs := availRead(file)
+
+	// 300 is kept, it is the state at the cutoff
+	if len(recs) != 3 || recs[0].When != 300 {
+		fmt.Printf("trim: %+v\n", recs)
+		t.Fail()
+	}
+	if last := availLastRec(file); last == nil || last.When != 500 {
+		fmt.Printf("last: %+v\n", last)
+		t.Fail()
+	}
+}
+
+func TestMaintOverlap(t *testing.T) {
+
+	tm := &testMon{}
+	m := New(tm, nil)
+	tm.m = m
+	m.Cf.Unique = "Top:maint"
+	m.statsInit()
+
+	child := New(&testMon{}, m)
+	child.Cf.Unique = "Top:maint:child"
+	m.Children = []*M{child}
+
+	inMaint := func() bool {
+		m.Lock.RLock()
+		defer m.Lock.RUnlock()
+		return m.P.InMaint
+	}
+
+	// 1 opens, 2 opens, 1 closes => still in maintenance
+	m.SetMaintenance(1, true, "window #1")
+	m.SetMaintenance(2, true, "window #2")
+	m.SetMaintenance(1, false, "window #1")
+
+	if !inMaint() || len(m.P.MaintIds) != 1 || !child.P.AncInMaint {
+		fmt.Printf("closed early: %v %v\n", m.P.MaintIds, child.P.AncInMaint)
+		t.Fail()
+	}
+
+	// closing twice does not matter
+	m.SetMaintenance(1, false, "window #1")
+	if !inMaint() {
+		fmt.Printf("closed twice\n")
+		t.Fail()
+	}
+
+	m.SetMaintenance(2, false, "window #2")
+	if inMaint() || len(m.P.MaintIds) != 0 || child.P.AncInMaint {
+		fmt.Printf("not closed: %v\n", m.P.MaintIds)
+		t.Fail()
+	}
+}
@@ -94,6 +94,7 @@ func (m *M) statsInit() {
 func (m *M) statsTransition() {
 
 	m.statsUpdateMaybeRoll()
+	m.availUpdate()
 	s := &m.P.Stats
 	dl.Debug("%s -> %s", s.Status, m.P.Status)
 
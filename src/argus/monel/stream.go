@@ -0,0 +1,275 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-23 11:00 (EDT)
+// Function: live event streaming - server-sent events + argusctl tail
+
+package monel
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"argus.domain/argus/api"
+	"argus.domain/argus/argus"
+	"argus.domain/argus/history"
+	"argus.domain/argus/web"
+)
+
+const (
+	STREAMPING  = 30 * time.Second
+	STREAMRETRY = 5000 // msec
+)
+
+type streamFilter struct {
+	root   string
+	top    bool
+	types  map[string]bool
+	creds  []string
+	hist   bool // may see events not about an object
+	all    bool // trusted, no acl checks
+	permit map[string]bool
+}
+
+func init() {
+	web.Add(web.PRIVATE, "/api/stream", webStream)
+	api.Add(true, "tail", apiTail)
+}
+
+func newStreamFilter(m *M, types string) *streamFilter {
+
+	f := &streamFilter{
+		root:   m.Cf.Unique,
+		top:    len(m.Parent) == 0,
+		permit: make(map[string]bool),
+	}
+
+	if types != "" {
+		f.types = make(map[string]bool)
+		for _, t := range strings.Split(strings.ToLower(types), ",") {
+			f.types[strings.TrimSpace(t)] = true
+		}
+	}
+
+	return f
+}
+
+func (f *streamFilter) match(e *history.Event) bool {
+
+	if f.types != nil && !f.types[e.Type] {
+		return false
+	}
+
+	if e.Obj == "" {
+		// config, darp, ...
+		return f.top && (f.all || f.hist)
+	}
+
+	if e.Obj != f.root && !strings.HasPrefix(e.Obj, f.root+":") {
+		return false
+	}
+	if f.all {
+		return true
+	}
+
+	if ok, seen := f.permit[e.Obj]; seen {
+		return ok
+	}
+
+	m := Find(e.Obj)
+	ok := m != nil && argus.ACLPermitsUser(m.Cf.ACL_Page, f.creds) && !f.hidden(m)
+	f.permit[e.Obj] = ok
+	return ok
+}
+
+// is it, or anything between it and the root, hidden?
+// the root was asked for by name, it is not
+func (f *streamFilter) hidden(m *M) bool {
+
+	for m.Cf.Unique != f.root {
+		if m.Cf.Hidden {
+			return true
+		}
+		if len(m.Parent) == 0 {
+			break
+		}
+		m = m.Parent[0]
+	}
+	return false
+}
+
+// ################################################################
+
+// obj, types, lastid (or Last-Event-ID header)
+func webStream(ctx *web.Context) {
+
+	m, creds := webObjUserCheck(ctx)
+	if m == nil {
+		return
+	}
+
+	fl, ok := ctx.W.(http.Flusher)
+	if !ok {
+		ctx.W.WriteHeader(500)
+		return
+	}
+
+	after, err := streamLastId(ctx.R.Header.Get("Last-Event-ID"), ctx.Get("lastid"))
+	if err != nil {
+		ctx.W.WriteHeader(400)
+		return
+	}
+
+	f := newStreamFilter(m, ctx.Get("types"))
+	f.creds = creds
+	f.hist = ctx.User != nil && history.ACLPermits(ctx.User.Groups)
+
+	sub, replay := history.Subscribe(after)
+	defer sub.Close()
+
+	ctx.W.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+	ctx.W.Header().Set("Cache-Control", "no-cache")
+	ctx.W.Header().Set("X-Accel-Buffering", "no")
+	fmt.Fprintf(ctx.W, "retry: %d\n\n", STREAMRETRY)
+
+	last := after
+	send := func(e *history.Event) {
+		if e.Id <= last {
+			return
+		}
+		last = e.Id
+		if !f.match(e) {
+			return
+		}
+		js, _ := json.Marshal(e)
+		fmt.Fprintf(ctx.W, "id: %d\nevent: %s\ndata: %s\n\n", e.Id, e.Type, js)
+	}
+
+	for _, e := range replay {
+		send(e)
+	}
+	fl.Flush()
+
+	ping := time.NewTicker(STREAMPING)
+	defer ping.Stop()
+
+	for {
+		select {
+		case e, ok := <-sub.C:
+			if !ok {
+				// dropped, the client will reconnect
+				return
+			}
+			send(e)
+		case <-ping.C:
+			fmt.Fprintf(ctx.W, ": ping\n\n")
+		case <-ctx.R.Context().Done():
+			return
+		case <-web.Stopping():
+			return
+		}
+		fl.Flush()
+	}
+}
+
+// argusctl tail obj=Top:Foo type=transition,override lastid=... fmt=json
+func apiTail(ctx *api.Context) {
+
+	obj := ctx.Args["obj"]
+	if obj == "" {
+		obj = "Top"
+	}
+	m := Find(obj)
+	if m == nil {
+		ctx.Send404()
+		return
+	}
+
+	after, err := streamLastId("", ctx.Args["lastid"])
+	if err != nil {
+		ctx.SendResponseFinal(400, err.Error())
+		return
+	}
+
+	f := newStreamFilter(m, ctx.Args["type"])
+	f.all = true
+	asjson := ctx.Args["fmt"] == "json"
+
+	sub, replay := history.Subscribe(after)
+	defer sub.Close()
+
+	ctx.SendOK()
+
+	last := after
+	defer func() {
+		// however it ends, say where to resume, and hang up
+		ctx.SendKVP("lastid", strconv.FormatInt(last, 10))
+		ctx.SendFinal()
+		ctx.Conn.Close()
+	}()
+
+	// the client does not send anything more, a read returns when it goes away
+	done := make(chan struct{})
+	go func() {
+		buf := make([]byte, 1)
+		ctx.Conn.Read(buf)
+		close(done)
+	}()
+
+	send := func(e *history.Event) error {
+		if e.Id <= last || !f.match(e) {
+			return nil
+		}
+		last = e.Id
+		_, err := ctx.Conn.Write([]byte(tailLine(e, asjson) + "\n"))
+		return err
+	}
+
+	for _, e := range replay {
+		if send(e) != nil {
+			return
+		}
+	}
+
+	for {
+		select {
+		case e, ok := <-sub.C:
+			if !ok || send(e) != nil {
+				// dropped or gone
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// one line per event. a blank line would end the stream
+func tailLine(e *history.Event, asjson bool) string {
+
+	if asjson {
+		js, _ := json.Marshal(e)
+		return string(js)
+	}
+	return strings.NewReplacer("\r", `\r`, "\n", `\n`).Replace(e.String())
+}
+
+func streamLastId(hdr string, arg string) (int64, error) {
+
+	v := hdr
+	if v == "" {
+		v = arg
+	}
+	if v == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("invalid event id '%s'", v)
+	}
+	return id, nil
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-24 20:05 (EDT)
+// Function:
+
+package monel
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"argus.domain/argus/api"
+	"argus.domain/argus/history"
+)
+
+func streamObj(unique string, parent *M, acl string, hidden bool) *M {
+
+	tm := &testMon{}
+	m := New(tm, parent)
+	tm.m = m
+	m.Cf.Unique = unique
+	m.Cf.ACL_Page = acl
+	m.Cf.Hidden = hidden
+	m.Init()
+	return m
+}
+
+func TestStreamFilter(t *testing.T) {
+
+	root := streamObj("Top:Stream", nil, "user staff root", false)
+	streamObj("Top:Stream:pub", root, "user staff root", false)
+	streamObj("Top:Stream:priv", root, "root", false)
+	hid := streamObj("Top:Stream:hid", root, "user staff root", true)
+	streamObj("Top:Stream:hid:kid", hid, "user staff root", false)
+	streamObj("Top:Elsewhere", nil, "user staff root", false)
+
+	f := newStreamFilter(root, "")
+	f.creds = []string{"user"}
+
+	for _, x := range []struct {
+		obj string
+		exp bool
+	}{
+		{"Top:Stream", true},
+		{"Top:Stream:pub", true},
+		{"Top:Stream:priv", false},
+		{"Top:Stream:hid", false},
+		{"Top:Stream:hid:kid", false},
+		{"Top:Stream:gone", false},
+		{"Top:Elsewhere", false},
+		{"", false}, // config, darp, ...
+	} {
+		// twice, the second is cached
+		for i := 0; i < 2; i++ {
+			if f.match(&history.Event{Type: "transition", Obj: x.obj}) != x.exp {
+				fmt.Printf("%s: expected %v\n", x.obj, x.exp)
+				t.Fail()
+			}
+		}
+	}
+
+	f.hist = true
+	if !f.match(&history.Event{Type: "config"}) {
+		fmt.Printf("history acl not permitted\n")
+		t.Fail()
+	}
+
+	// a hidden object is visible from within
+	f = newStreamFilter(hid, "override")
+	f.creds = []string{"user"}
+	if f.match(&history.Event{Type: "transition", Obj: "Top:Stream:hid:kid"}) {
+		fmt.Printf("type not filtered\n")
+		t.Fail()
+	}
+	if !f.match(&history.Event{Type: "override", Obj: "Top:Stream:hid:kid"}) {
+		fmt.Printf("hidden root\n")
+		t.Fail()
+	}
+
+	// trusted
+	f = newStreamFilter(root, "")
+	f.all = true
+	if !f.match(&history.Event{Type: "transition", Obj: "Top:Stream:priv"}) {
+		fmt.Printf("trusted filtered\n")
+		t.Fail()
+	}
+}
+
+func TestTailLine(t *testing.T) {
+
+	e := &history.Event{When: 1700000000, Type: "annotation", Obj: "Top:Stream", Msg: "line one\n\nline three\r\n"}
+
+	line := tailLine(e, false)
+	if strings.ContainsAny(line, "\r\n") || !strings.Contains(line, `line one\n\nline three\r\n`) {
+		fmt.Printf("tail: %q\n", line)
+		t.Fail()
+	}
+	if line = tailLine(e, true); strings.ContainsAny(line, "\r\n") {
+		fmt.Printf("tail json: %q\n", line)
+		t.Fail()
+	}
+}
+
+func TestTailDropped(t *testing.T) {
+
+	streamObj("Top:Tail", nil, "root", false)
+
+	cli, srv := net.Pipe()
+	defer cli.Close()
+
+	done := make(chan struct{})
+	go func() {
+		apiTail(&api.Context{Conn: srv, Args: map[string]string{"obj": "Top:Tail", "fmt": "json"}})
+		close(done)
+	}()
+
+	r := bufio.NewReader(cli)
+	r.ReadString('\n')
+
+	// more than the subscription holds, while the client is not reading
+	for i := 0; i < history.SUBQUEUE+3; i++ {
+		history.Add(&history.Event{Type: "transition", Obj: "Top:Tail"})
+	}
+
+	var lastid string
+	var lines []string
+	for {
+		l, err := r.ReadString('\n')
+		if err != nil {
+			break
+		}
+		lines = append(lines, l)
+
+		var e history.Event
+		if json.Unmarshal([]byte(l), &e) == nil {
+			lastid = fmt.Sprintf("lastid: %d\n", e.Id)
+		}
+	}
+
+	// the events, where to resume, the end, then hung up
+	n := len(lines)
+	if n < 3 || lines[n-2] != lastid || lines[n-1] != "\n" {
+		fmt.Printf("tail ended: %d lines, expected %q\n", n, lastid)
+		t.Fail()
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		fmt.Printf("tail still running\n")
+		t.Fail()
+	}
+}
@@ -6,9 +6,10 @@
 package monel
 
 import (
+	"time"
+
 	"argus.domain/argus/argus"
 	"argus.domain/argus/clock"
-	"argus.domain/argus/notify"
 )
 
 // m is a service updating status
@@ -71,9 +72,14 @@ func (m *M) commonUpdate(prevOv argus.Status) {
 	m.loggitL("TRANSITION", m.P.Reason)
 	dl.Verbose("TRANSITION [%s -> %s] %s (%s)", prevOv, m.P.OvStatus, m.Cf.Unique, m.P.Reason)
 	m.statsTransition()
+	m.flapTransition()
 	m.determineSummary()
 	m.updateNotifies()
 	m.maybeNotify(prevOv)
+	if !m.P.Flapping {
+		// flapping objects are resolved when they stop
+		m.incidentTransition()
+	}
 
 	// RSN - audit hook
 	// RSN - if up + ov + auto -> remove
@@ -161,25 +167,12 @@ func (m *M) maybeNotify(prevOv argus.Status) {
 		return
 	}
 
-	// ...
-
-	m.Debug("send notify")
-
-	notif := notify.New(&notify.NewConf{
-		Unique:       m.Cf.Unique,
-		FriendlyName: m.Cf.Friendlyname,
-		ShortName:    m.Cf.Label,
-		Conf:         m.NotifyCf,
-		Reason:       m.P.Reason,
-		Result:       m.P.Result,
-		OvStatus:     st,
-		PrevOv:       prevOv,
-	}, m)
-
-	if notif != nil {
-		m.Notifies = append(m.Notifies, notif)
+	// flapping - notify once, then hold
+	if m.flapNotify() {
+		return
 	}
 
+	m.sendNotify(st, prevOv, "")
 }
 
 // tell existing notifications that the status changed
@@ -192,16 +185,20 @@ func (m *M) updateNotifies() {
 }
 
 func (m *M) permitNotify(status argus.Status) bool {
+	return m.permitNotifyAt(status, time.Now())
+}
+
+func (m *M) permitNotifyAt(status argus.Status, now time.Time) bool {
 
 	if int(status) >= len(m.Cf.Sendnotify) {
 		return false
 	}
 
 	if m.Cf.Sendnotify[int(status)] != nil {
-		return m.Cf.Sendnotify[int(status)].PermitNow("yes")
+		return m.Cf.Sendnotify[int(status)].PermitAt(now, "yes")
 	}
 	if m.Cf.Sendnotify[int(argus.UNKNOWN)] != nil {
-		return m.Cf.Sendnotify[int(argus.UNKNOWN)].PermitNow("yes")
+		return m.Cf.Sendnotify[int(argus.UNKNOWN)].PermitAt(now, "yes")
 	}
 	return false
 }
@@ -267,17 +264,29 @@ func (m *M) determineAggrStatus() {
 	nchild := 0
 	rsum := [argus.MAXSTATUS + 1]int{}
 	osum := [argus.MAXSTATUS + 1]int{}
+	rwgt := [argus.MAXSTATUS + 1]float64{}
+	owgt := [argus.MAXSTATUS + 1]float64{}
 
 	for _, child := range children {
 		rs, os := child.Status()
 		dl.Debug("%s %s : rs %v, os %v", m.Cf.Unique, child.Cf.Unique, rs, os)
 		rsum[rs]++
 		osum[os]++
+		rwgt[rs] += child.Cf.Weight
+		owgt[os] += child.Cf.Weight
 		nchild++
 	}
 
-	rs := calcAggrStatus(m.Cf.Gravity, nchild, argus.CRITICAL, rsum[:])
-	os := calcAggrStatus(m.Cf.Gravity, nchild, argus.MAXSTATUS, osum[:])
+	var rs, os argus.Status
+
+	if m.Cf.Gravity == argus.GRAV_PCT {
+		pct := &argus.GravityPct{Down: m.Cf.Gravity_Down[:], Up: m.Cf.Gravity_Up[:]}
+		rs = pct.Status(argus.CRITICAL, rwgt[:])
+		os = pct.Status(argus.MAXSTATUS, owgt[:])
+	} else {
+		rs = calcAggrStatus(m.Cf.Gravity, nchild, argus.CRITICAL, rsum[:])
+		os = calcAggrStatus(m.Cf.Gravity, nchild, argus.MAXSTATUS, osum[:])
+	}
 
 	dl.Debug("%s rs %v %v, os %v %v", m.Cf.Unique, rsum, rs, osum, os)
 	m.P.Status = rs
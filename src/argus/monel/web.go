@@ -157,6 +157,11 @@ func (m *M) webJson(creds []string, md map[string]interface{}) {
 	md["override"] = m.P.Override
 	md["annotation"] = m.P.Annotation
 	md["reason"] = m.P.Reason
+	md["flapping"] = m.P.Flapping
+	if m.P.Flapping {
+		md["flapstart"] = m.P.FlapStart
+		md["flapcount"] = m.P.FlapCount
+	}
 	md["stats"] = m.ExportStats()
 
 	// reverse and truncate notifies, logs
@@ -311,9 +316,9 @@ func webAnnotate(ctx *web.Context) {
 	m.Lock.Unlock()
 
 	if text == "" {
-		m.Loggit("ANNOTATION", "removed by "+ctx.User.Name)
+		m.LoggitUser("ANNOTATION", ctx.User.Name, "removed by "+ctx.User.Name)
 	} else {
-		m.Loggit("ANNOTATION", "added by "+ctx.User.Name)
+		m.LoggitUser("ANNOTATION", ctx.User.Name, "added by "+ctx.User.Name)
 	}
 
 	js, _ := json.MarshalIndent(d, "", "  ")
@@ -325,9 +330,9 @@ func webAnnotate(ctx *web.Context) {
 
 func webGraphDJson(ctx *web.Context) {
 
-	// obj, tag, since, which, width
+	// obj, tag, since, which, width, offset, series
 
-	m, _ := webObjUserCheck(ctx)
+	m, creds := webObjUserCheck(ctx)
 	if m == nil {
 		return
 	}
@@ -348,7 +353,39 @@ func webGraphDJson(ctx *web.Context) {
 		tag = ""
 	}
 
-	d["data"] = graph.Get(m.Pathname(tag, ""), which, since, int(width))
+	offset, err := graphOffset(ctx.Get("offset"))
+	if err != nil {
+		ctx.W.WriteHeader(400)
+		return
+	}
+
+	if offset != 0 {
+		d["offset"] = offset
+		d["data"] = graph.GetShifted(m.Pathname(tag, ""), which, since, int(width), offset)
+	} else {
+		d["data"] = graph.Get(m.Pathname(tag, ""), which, since, int(width))
+	}
+
+	// additional series, all on the same time axis
+	// series=Top:Foo:Bar,Top:Foo:Baz@7d
+	if sl := ctx.Get("series"); sl != "" {
+		var res []graphSeries
+
+		for _, sp := range strings.Split(sl, ",") {
+			o, off, err := parseGraphSeries(sp)
+			if err != nil {
+				ctx.W.WriteHeader(400)
+				return
+			}
+			sm := Find(o)
+			if sm == nil || !sm.Cf.Graph || !argus.ACLPermitsUser(sm.Cf.ACL_Page, creds) {
+				continue
+			}
+			res = append(res, graphSeries{sm.Cf.Unique, off,
+				graph.GetShifted(sm.Pathname(tag, ""), which, since, int(width), off)})
+		}
+		d["series"] = res
+	}
 
 	js, _ := json.MarshalIndent(d, "", "  ")
 	ctx.W.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -357,7 +394,9 @@ func webGraphDJson(ctx *web.Context) {
 
 func webGraphInfo(ctx *web.Context) {
 
-	m, _ := webObjUserCheck(ctx)
+	// obj, offsets, also
+
+	m, creds := webObjUserCheck(ctx)
 	if m == nil {
 		return
 	}
@@ -370,13 +409,43 @@ func webGraphInfo(ctx *web.Context) {
 	}
 
 	gi := struct {
-		Title  string
-		YLabel string
-		MyId   string
-		List   []interface{}
-	}{m.Cf.Title, m.Cf.YLabel, darp.MyId, nil}
+		Title   string
+		YLabel  string
+		MyId    string
+		List    []interface{}
+		Offsets []graphOffsetInfo
+	}{m.Cf.Title, m.Cf.YLabel, darp.MyId, nil, nil}
 
 	gi.List = m.graphList("", gi.List)
+
+	// compare against other objects
+	if al := ctx.Get("also"); al != "" {
+		for _, o := range strings.Split(al, ",") {
+			am := Find(strings.TrimSpace(o))
+			if am == nil || am == m || !am.Cf.Graph || !argus.ACLPermitsUser(am.Cf.ACL_Page, creds) {
+				continue
+			}
+			label := am.Cf.GraphLabel
+			if label == "" {
+				label = am.Cf.Unique
+			}
+			gi.List = am.graphList(label, gi.List)
+		}
+	}
+
+	// comparison choices for the ui
+	offs := defaultGraphOffsets
+	if ol := ctx.Get("offsets"); ol != "" {
+		offs = strings.Split(ol, ",")
+	}
+	for _, o := range offs {
+		off, err := graphOffset(o)
+		if err != nil || off == 0 {
+			continue
+		}
+		gi.Offsets = append(gi.Offsets, graphOffsetInfo{graph.OffsetLabel(off), off})
+	}
+
 	d["graph"] = gi
 
 	js, _ := json.MarshalIndent(d, "", "  ")
@@ -384,6 +453,56 @@ func webGraphInfo(ctx *web.Context) {
 	ctx.W.Write(js)
 }
 
+type graphSeries struct {
+	Obj    string
+	Offset int64
+	Data   []*graph.Point
+}
+
+type graphOffsetInfo struct {
+	Label  string
+	Offset int64
+}
+
+var defaultGraphOffsets = []string{"1d", "7d", "14d"}
+
+// timespec, eg. 7d
+func graphOffset(v string) (int64, error) {
+
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, nil
+	}
+
+	off, err := argus.Timespec(v, 1)
+	if err != nil || off < 0 {
+		return 0, fmt.Errorf("invalid offset '%s'", v)
+	}
+	return off, nil
+}
+
+// obj[@offset]
+func parseGraphSeries(v string) (string, int64, error) {
+
+	v = strings.TrimSpace(v)
+	obj := v
+	var off int64
+
+	if i := strings.LastIndex(v, "@"); i != -1 {
+		obj = v[:i]
+		o, err := graphOffset(v[i+1:])
+		if err != nil {
+			return "", 0, err
+		}
+		off = o
+	}
+
+	if obj == "" {
+		return "", 0, fmt.Errorf("invalid series '%s'", v)
+	}
+	return obj, off, nil
+}
+
 func (m *M) graphList(label string, gl []interface{}) []interface{} {
 
 	m.Lock.RLock()
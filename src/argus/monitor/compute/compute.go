@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 
+	"argus.domain/argus/argus"
 	"argus.domain/argus/configure"
 	"github.com/jaw0/acdiag"
 	"argus.domain/argus/expr"
@@ -17,12 +18,14 @@ import (
 )
 
 type Conf struct {
-	Expr string
+	Expr         string
+	Compute_Mode string // value - check the value against thresholds, status - the expr returns a status
 }
 
 type Compute struct {
 	S     *service.Service
 	Cf    Conf
+	expr  []string
 	objs  map[string]bool
 	srvc  []*service.Service
 	valid bool
@@ -52,12 +55,19 @@ func (c *Compute) Config(conf *configure.CF, s *service.Service) error {
 		return errors.New("expr not specified")
 	}
 
-	expr, obj, err := expr.Parse(c.Cf.Expr)
+	switch c.Cf.Compute_Mode {
+	case "", "value", "status":
+	default:
+		return fmt.Errorf("invalid compute_mode '%s'", c.Cf.Compute_Mode)
+	}
+
+	prog, obj, err := expr.Parse(c.Cf.Expr)
 	if err != nil {
 		return fmt.Errorf("invalid compute expr: %v", err)
 	}
+	c.expr = prog
 	c.objs = obj
-	dl.Debug("expr: %#v", expr)
+	dl.Debug("expr: %#v", prog)
 
 	// set names + labels
 	uname := "COMPUTE_" + c.Cf.Expr
@@ -84,10 +94,37 @@ func (c *Compute) Start(s *service.Service) {
 		}
 	}
 
+	if c.Cf.Compute_Mode == "status" {
+		c.checkStatus(s)
+		return
+	}
+
 	// CheckValue will run the computation
 	s.CheckValue("0", "data")
 }
 
+// the expr determines the status
+func (c *Compute) checkStatus(s *service.Service) {
+
+	st, nrdy, err := expr.RunExprS(c.expr, nil)
+
+	if nrdy != "" {
+		s.Debug("not ready: %s", nrdy)
+		return
+	}
+	if err != nil {
+		s.Debug("expr: %v", err)
+		s.Fail("COMPUTE " + err.Error())
+		return
+	}
+	if st == argus.UNKNOWN {
+		s.Fail("COMPUTE expr is unknown")
+		return
+	}
+
+	s.CheckStatus(st, st.String(), "COMPUTE expr is "+st.String())
+}
+
 func (c *Compute) Init() error {
 	return nil
 }
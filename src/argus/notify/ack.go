@@ -7,10 +7,12 @@ package notify
 
 import (
 	"encoding/json"
+	"fmt"
 	"strconv"
 
 	"argus.domain/argus/api"
 	"argus.domain/argus/argus"
+	"argus.domain/argus/history"
 	"argus.domain/argus/web"
 )
 
@@ -74,16 +76,33 @@ func (n *N) maybeAutoAck() {
 func (n *N) ack(who string) {
 
 	n.p.IsActive = false
+	n.p.AckExpires = 0
 	delete(actives, n.p.IdNo)
 	NActive.Set(int64(len(actives)))
 	n.Save()
 	n.log(who, "acked")
+	n.history("ack", who, "acked")
 
 	for dst, _ := range n.p.Status {
 		n.p.Status[dst] = "acked"
 	}
 }
 
+// Ack acks the notification, if it is active
+func (n *N) Ack(who string) bool {
+
+	lock.Lock()
+	defer lock.Unlock()
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if !n.p.IsActive {
+		return false
+	}
+	n.ack(who)
+	return true
+}
+
 func webAck(ctx *web.Context) {
 
 	n, creds := webGetNotifyCreds(ctx)
@@ -97,8 +116,18 @@ func webAck(ctx *web.Context) {
 		return
 	}
 
+	dur, snooze, err := ackArgs(ctx.Get)
+	if err != nil {
+		ctx.W.WriteHeader(400)
+		return
+	}
+
 	n.lock.Lock()
-	n.ack(ctx.User.Name)
+	if snooze {
+		n.snooze(ctx.User.Name, dur)
+	} else {
+		n.ackFor(ctx.User.Name, dur)
+	}
 	js, _ := json.MarshalIndent(n.p, "", "  ")
 	n.lock.Unlock()
 
@@ -118,8 +147,53 @@ func apiAck(ctx *api.Context) {
 		return
 	}
 
+	dur, snooze, err := ackArgs(func(k string) string { return ctx.Args[k] })
+	if err != nil {
+		ctx.SendResponseFinal(400, err.Error())
+		return
+	}
+
 	n.lock.Lock()
-	n.ack("api")
+	if snooze {
+		n.snooze("api", dur)
+	} else {
+		n.ackFor("api", dur)
+	}
 	n.lock.Unlock()
 	ctx.SendOKFinal()
 }
+
+// for=2h => ack for 2 hours; snooze=30m => delay renotify 30 minutes
+func ackArgs(get func(string) string) (int64, bool, error) {
+
+	if s := get("snooze"); s != "" {
+		dur, err := argus.Timespec(s, 60)
+		if err != nil || dur <= 0 {
+			return 0, true, fmt.Errorf("invalid snooze '%s'", s)
+		}
+		return dur, true, nil
+	}
+
+	if f := get("for"); f != "" {
+		dur, err := argus.Timespec(f, 60)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid ack time '%s'", f)
+		}
+		return dur, false, nil
+	}
+
+	return 0, false, nil
+}
+
+// record in the event history
+func (n *N) history(typ string, who string, msg string) {
+
+	history.Add(&history.Event{
+		Type:   typ,
+		Obj:    n.p.Unique,
+		Tags:   n.p.Tags,
+		User:   who,
+		Status: n.p.OvStatus,
+		Msg:    fmt.Sprintf("notification %d %s", n.p.IdNo, msg),
+	})
+}
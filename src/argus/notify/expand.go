@@ -39,6 +39,10 @@ func (n *N) expand(templ string, content string, param map[string]interface{}) s
 		"RESULT":       n.p.Result,
 		"STATUS":       status,
 		"SEVERITY":     n.p.OvStatus.String(),
+		"ACKURL":       n.signedLink("ack", 0),
+		"ACKTMPURL":    n.signedLink("ack", globalDefaults.Link_Ack_Time),
+		"SNOOZEURL":    n.signedLink("snooze", globalDefaults.Link_Snooze_Time),
+		"GRAPHURL":     n.graphLink(),
 		// RSN - objecturl, notifyurl, object-info/details ?
 	}
 
@@ -47,7 +51,7 @@ func (n *N) expand(templ string, content string, param map[string]interface{}) s
 		dat[key] = val
 	}
 
-	t := template.New("x")
+	t := template.New("x").Funcs(template.FuncMap{"json": jsonQuote})
 	_, err := t.Parse(templ)
 	if err != nil {
 		dl.Problem("cannot parse template '%s': %v", templ, err)
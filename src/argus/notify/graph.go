@@ -0,0 +1,137 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 23:00 (EDT)
+// Function: graphs in notifications - signed links, mail attachments
+
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/graph"
+)
+
+const (
+	GRAPHWIDTH  = 600
+	GRAPHHEIGHT = 200
+	MAXATTACH   = 5
+)
+
+type attachment struct {
+	name string
+	data []byte
+}
+
+// signed link to a graph image, for use in messages, or embedding elsewhere
+// only the object, range, and expiration are signed. other params are ignored
+func GraphLink(obj string, rng int64, exp int64) string {
+
+	if globalDefaults.Web_Url == "" {
+		return ""
+	}
+
+	v := url.Values{}
+	v.Set("obj", obj)
+	v.Set("range", fmt.Sprintf("%d", rng))
+	v.Set("exp", fmt.Sprintf("%d", exp))
+	v.Set("sig", graphSig(obj, rng, exp))
+
+	return globalDefaults.Web_Url + "/api/graphimg?" + v.Encode()
+}
+
+func GraphLinkValid(obj string, rng int64, exp int64, sig string) bool {
+
+	if exp < clock.Unix() {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(graphSig(obj, rng, exp)))
+}
+
+func graphSig(obj string, rng int64, exp int64) string {
+
+	mac := hmac.New(sha256.New, getLinkKey())
+	fmt.Fprintf(mac, "graph|%s|%d|%d", obj, rng, exp)
+	return argus.Encode64Url(string(mac.Sum(nil)))
+}
+
+// lock is already held
+func (n *N) graphLink() string {
+
+	if n.p.GraphFile == "" {
+		return ""
+	}
+	return GraphLink(n.p.Unique, globalDefaults.Notify_Graph_Range, n.p.Created+LINKEXPIRE)
+}
+
+// lock is already held
+func (n *N) graphImage() *attachment {
+
+	if n.p.GraphFile == "" {
+		return nil
+	}
+
+	now := clock.Unix()
+	title := n.p.FriendlyName
+	if title == "" {
+		title = n.p.Unique
+	}
+
+	var buf bytes.Buffer
+	err := graph.Image(&buf, "png", n.p.GraphFile, &graph.ImageConf{
+		Title:  title,
+		Width:  GRAPHWIDTH,
+		Height: GRAPHHEIGHT,
+		Start:  now - globalDefaults.Notify_Graph_Range,
+		End:    now,
+	})
+	if err != nil {
+		dl.Debug("cannot render graph: %v", err)
+		return nil
+	}
+
+	return &attachment{fmt.Sprintf("graph-%d.png", n.p.IdNo), buf.Bytes()}
+}
+
+// convert a plain mail message into a multipart one
+func mimeAttach(msg string, atts []*attachment) string {
+
+	if len(atts) == 0 {
+		return msg
+	}
+
+	hdrs := msg
+	body := ""
+	if i := strings.Index(msg, "\n\n"); i != -1 {
+		hdrs = msg[:i]
+		body = msg[i+2:]
+	}
+
+	bndry := fmt.Sprintf("argus-%x", clock.Nano())
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\nMIME-Version: 1.0\nContent-Type: multipart/mixed; boundary=\"%s\"\n\n", hdrs, bndry)
+	fmt.Fprintf(&b, "--%s\nContent-Type: text/plain; charset=utf-8\n\n%s\n", bndry, body)
+
+	for _, a := range atts {
+		fmt.Fprintf(&b, "--%s\nContent-Type: image/png\nContent-Transfer-Encoding: base64\n", bndry)
+		fmt.Fprintf(&b, "Content-Disposition: attachment; filename=\"%s\"\n\n", a.name)
+
+		enc := base64.StdEncoding.EncodeToString(a.data)
+		for len(enc) > 76 {
+			b.WriteString(enc[:76] + "\n")
+			enc = enc[76:]
+		}
+		b.WriteString(enc + "\n")
+	}
+
+	fmt.Fprintf(&b, "--%s--\n", bndry)
+	return b.String()
+}
@@ -7,16 +7,24 @@ package notify
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"argus.domain/argus/argus"
 	"argus.domain/argus/configure"
+	"argus.domain/argus/users"
 )
 
 type Method struct {
 	builtin bool
+	webhook bool // post to the address, no command
+	ticket  *Ticket
 	Command string
 	Send    string
+	Mail    bool                                // send is a mail message - graphs can be attached
 	Qtime   int64                               `cfconv:"timespec"`
 	Permit  [argus.CRITICAL + 1]*argus.Schedule `cfconv:"dotsev"`
 }
@@ -24,6 +32,7 @@ type Method struct {
 var methods = map[string]*Method{
 	"mail": &Method{
 		builtin: true,
+		Mail:    true,
 		Qtime:   300,
 		Command: "sendmail -t -f {{.MAILFROM}}",
 		Send:    "To: {{.MAILTO}}\nFrom: {{.MAILFROM}}\nSubject: {{.SUBJECT}}\n\n{{.CONTENT}}\n",
@@ -33,11 +42,17 @@ var methods = map[string]*Method{
 		Command: "qpage",
 		Send:    "{{.CONTENT}}{{.ESCALATED}}",
 	},
+	"webhook": &Method{
+		builtin: true,
+		webhook: true,
+		Send:    "{{.SUBJECT}}\n\n{{.CONTENT}}\n",
+	},
 }
 
 func NewMethod(conf *configure.CF) error {
 
-	m := &Method{}
+	// a replacement mail method is still mail, unless configured otherwise
+	m := &Method{Mail: conf.Name == "mail"}
 	conf.InitFromConfig(m, "method", "")
 
 	if m.Command == "" {
@@ -62,17 +77,41 @@ func methodForDst(dst string) (*Method, string) {
 	return methods[f[0]], f[1]
 }
 
+// is the method permitted to send at this time?
+// NB - only reported by the simulator, delivery does not enforce it
+func (m *Method) permitAt(sev argus.Status, now time.Time) bool {
+
+	p := m.Permit[int(sev)]
+	if p == nil {
+		p = m.Permit[int(argus.UNKNOWN)]
+	}
+	if p == nil {
+		return true
+	}
+	return p.PermitAt(now, "yes")
+}
+
 // ################################################################
 
 // called with package lock held
 func (m *Method) transmit(dst string, addr string, notes []*N) {
 
+	if m.ticket != nil {
+		m.ticket.transmit(dst, addr, notes)
+		return
+	}
+
 	// build content
 
 	subj := "Argus"
 	esced := false
 	joinWith := "\n"
 	msgs := []string{}
+	var atts []*attachment
+
+	// only mail can take attachments
+	attach := globalDefaults.Notify_Graph == "attach" && m.Mail
+	link := globalDefaults.Notify_Graph == "link" || globalDefaults.Notify_Graph == "attach" && !attach
 
 	for _, n := range notes {
 		n.lock.RLock()
@@ -85,7 +124,16 @@ func (m *Method) transmit(dst string, addr string, notes []*N) {
 		if n.p.Escalated {
 			esced = true
 		}
-		msgs = append(msgs, n.p.MessageFmted)
+		msg := n.p.MessageFmted
+		if gl := n.graphLink(); link && gl != "" {
+			msg += "\n" + gl
+		}
+		if attach && len(atts) < MAXATTACH {
+			if a := n.graphImage(); a != nil {
+				atts = append(atts, a)
+			}
+		}
+		msgs = append(msgs, msg)
 		n.lock.RUnlock()
 	}
 
@@ -110,7 +158,53 @@ func (m *Method) transmit(dst string, addr string, notes []*N) {
 	send := notes[0].expand(m.Send, content, dat)
 	notes[0].lock.RUnlock()
 
+	if attach {
+		send = mimeAttach(send, atts)
+	}
+
+	if m.webhook {
+		postWebhook(addr, send)
+		return
+	}
+
 	dl.Debug("cmd: %s; send %s", command, send)
 
-	runCommand(command, send)
+	// the address is also in the environment, for commands that
+	// want it without having it interpolated into the shell command
+	runCommand(command, send, "ARGUS_ADDR="+addr)
+}
+
+func init() {
+	users.ValidWebhook = ValidWebhook
+}
+
+// ValidWebhook - only http + https
+func ValidWebhook(addr string) error {
+
+	u, err := url.Parse(addr)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook url '%s'", addr)
+	}
+	return nil
+}
+
+func postWebhook(addr string, send string) {
+
+	if err := ValidWebhook(addr); err != nil {
+		dl.Problem("%v", err)
+		return
+	}
+
+	client := &http.Client{Timeout: TIMEOUT}
+	res, err := client.Post(addr, "text/plain; charset=utf-8", strings.NewReader(send))
+	if err != nil {
+		dl.Problem("webhook failed: %v", err)
+		return
+	}
+	ioutil.ReadAll(res.Body)
+	res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		dl.Problem("webhook %s: %s", addr, res.Status)
+	}
 }
@@ -0,0 +1,79 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-24 14:05 (EDT)
+// Function:
+
+package notify
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"argus.domain/argus/argus"
+)
+
+func TestExpandAddr(t *testing.T) {
+
+	n := &N{cf: &Conf{}, p: Persist{IdNo: 7, Unique: "Top:web", OvStatus: argus.CRITICAL}}
+
+	got := n.expand("mail {{.ADDR}} #{{.IDNO}} {{.STATUS}}", "", map[string]interface{}{"ADDR": "ops@example.com"})
+	if got != "mail ops@example.com #7 DOWN" {
+		fmt.Printf("expand: %s\n", got)
+		t.Fail()
+	}
+}
+
+func TestValidWebhook(t *testing.T) {
+
+	for _, x := range []struct {
+		addr string
+		ok   bool
+	}{
+		{"https://hooks.example.com/T0/B0", true},
+		{"http://10.0.0.1:8080/alert", true},
+		{"ftp://example.com/x", false},
+		{"file:///etc/passwd", false},
+		{"https://", false},
+		{"x'; rm -rf / #", false},
+	} {
+		err := ValidWebhook(x.addr)
+		if (err == nil) != x.ok {
+			fmt.Printf("%s: %v\n", x.addr, err)
+			t.Fail()
+		}
+	}
+}
+
+func TestWebhook(t *testing.T) {
+
+	var got []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		got = append(got, r.Method+" "+r.URL.RawQuery+" "+string(body))
+	}))
+	defer srv.Close()
+
+	n := &N{cf: &Conf{}, p: Persist{IdNo: 1, Unique: "Top:web", MessageFmted: "web is DOWN", OvStatus: argus.CRITICAL}}
+
+	// quotes in the address go to the server, not a shell
+	addr := srv.URL + "/hook?x='$(touch%20/tmp/pwned)'"
+	methods["webhook"].transmit("webhook:"+addr, addr, []*N{n})
+
+	if len(got) != 1 || !strings.HasPrefix(got[0], "POST ") ||
+		!strings.Contains(got[0], "$(touch") || !strings.Contains(got[0], "web is DOWN") {
+		fmt.Printf("webhook: %v\n", got)
+		t.Fail()
+	}
+
+	// not sent
+	methods["webhook"].transmit("webhook:file:///etc/passwd", "file:///etc/passwd", []*N{n})
+	if len(got) != 1 {
+		fmt.Printf("invalid webhook sent\n")
+		t.Fail()
+	}
+}
@@ -6,7 +6,9 @@
 package notify
 
 import (
+	"sort"
 	"strings"
+	"time"
 
 	"argus.domain/argus/argus"
 	"argus.domain/argus/clock"
@@ -30,6 +32,8 @@ func New(ncf *NewConf, mon Remover) *N {
 			FriendlyName: ncf.FriendlyName,
 			Reason:       ncf.Reason,
 			Result:       ncf.Result,
+			Tags:         ncf.Tags,
+			GraphFile:    ncf.GraphFile,
 			OvStatus:     ncf.OvStatus,
 			PrevOv:       ncf.PrevOv,
 			CurrOv:       ncf.OvStatus,
@@ -38,7 +42,7 @@ func New(ncf *NewConf, mon Remover) *N {
 	}
 
 	n.determineMessage(ncf)
-	n.determineSendTo()
+	n.determineSendTo(ncf, clock.Now())
 	dl.Debug("new notification %d - %s [%s] => %#v", n.p.IdNo, n.p.Unique, n.p.Message, n.p.SendTo)
 
 	n.p.MessageFmted = n.expand(globalDefaults.Message_Fmt, n.p.Message, nil)
@@ -49,6 +53,7 @@ func New(ncf *NewConf, mon Remover) *N {
 	}
 
 	n.log("system", "created")
+	n.history("notify", "", "created")
 	n.Save()
 	notechan <- n
 	return n
@@ -58,6 +63,11 @@ func (n *N) determineMessage(ncf *NewConf) {
 
 	st := n.p.OvStatus
 
+	if ncf.Message != "" {
+		n.p.Message = ncf.Message
+		return
+	}
+
 	if st == argus.CLEAR {
 		if n.cf.MessageUp != "" {
 			n.p.Message = n.expand(n.cf.MessageUp, "", nil)
@@ -84,10 +94,13 @@ func (n *N) determineMessage(ncf *NewConf) {
 		n.p.Message = bmsg + " is UP"
 	} else {
 		n.p.Message = bmsg + " is DOWN/" + st.String()
+		if ncf.Detail != "" {
+			n.p.Message += " - " + ncf.Detail
+		}
 	}
 }
 
-func (n *N) determineSendTo() {
+func (n *N) determineSendTo(ncf *NewConf, now time.Time) {
 
 	ns := n.cf.Notify[int(n.p.OvStatus)]
 	if ns == nil {
@@ -98,7 +111,7 @@ func (n *N) determineSendTo() {
 
 	// current notify value
 	if ns != nil {
-		nv := strings.Fields(ns.ResultNow(""))
+		nv := strings.Fields(ns.ResultAt(now, ""))
 		if len(nv) > 0 {
 			dst = append(dst, nv...)
 		}
@@ -110,6 +123,24 @@ func (n *N) determineSendTo() {
 		dst = append(dst, nv...)
 	}
 
+	n.p.Routing = []RouteDat{{Rule: "object", Match: len(dst) > 0, Why: "notify: " + strings.Join(dst, " ")}}
+
+	// routing rules
+	rdst, resc, rlog := routeRules(&routeInfo{
+		unique: ncf.Unique,
+		tags:   ncf.Tags,
+		darp:   ncf.Darp,
+		status: n.p.OvStatus,
+		when:   now,
+	})
+	n.p.Routing = append(n.p.Routing, rlog...)
+
+	for _, d := range rdst {
+		if !contains(dst, d) {
+			dst = append(dst, d)
+		}
+	}
+
 	if len(dst) > 0 {
 		n.p.SendTo = []SendDat{{When: 0, Dst: dst}}
 	}
@@ -122,9 +153,18 @@ func (n *N) determineSendTo() {
 		esc = n.cf.Escalate[int(argus.UNKNOWN)]
 	}
 
-	if esc == "" {
-		return
+	if esc != "" {
+		n.addEscalate(esc)
 	}
+	for _, e := range resc {
+		n.addEscalate(e)
+	}
+
+	// rules may have interleaved the steps
+	sort.SliceStable(n.p.SendTo, func(i, j int) bool { return n.p.SendTo[i].When < n.p.SendTo[j].When })
+}
+
+func (n *N) addEscalate(esc string) {
 
 	escl := strings.Split(esc, ";")
 	hasErr := false
@@ -147,3 +187,13 @@ func (n *N) determineSendTo() {
 		dl.Problem("invalid escalate '%s'", esc)
 	}
 }
+
+func contains(list []string, s string) bool {
+
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
@@ -31,7 +31,15 @@ type GlobalConf struct {
 	ACL_NotifyDetail string
 	ACL_NotifyList   string
 	ACL_NotifyAck    string
-	Notify_Discard   int64 `cfconv:"timespec"`
+	ACL_Silence      string
+	Web_Url          string // for links in messages
+	Notify_Discard   int64  `cfconv:"timespec"`
+	Silence_Keep     int64  `cfconv:"timespec"`
+	Link_Ack_Time    int64  `cfconv:"timespec"`
+	Link_Snooze_Time int64  `cfconv:"timespec"`
+
+	Notify_Graph       string // none, link, attach
+	Notify_Graph_Range int64  `cfconv:"timespec"`
 }
 
 type NewConf struct {
@@ -41,6 +49,11 @@ type NewConf struct {
 	FriendlyName string
 	Reason       string
 	Result       string
+	Tags         string
+	Darp         string // darp id where the notification originated
+	Message      string // instead of the usual up/down message
+	Detail       string // appended to the usual down message
+	GraphFile    string // for graphs in messages
 	OvStatus     argus.Status
 	PrevOv       argus.Status
 }
@@ -66,6 +79,9 @@ type Persist struct {
 	Created      int64
 	LastSent     int64
 	IsActive     bool
+	AckExpires   int64 // sticky ack - unack at this time
+	UnAcked      int64 // when the sticky ack expired
+	SnoozeUntil  int64 // no renotify until
 	StepNo       int
 	Escalated    bool
 	Message      string
@@ -75,11 +91,16 @@ type Persist struct {
 	FriendlyName string
 	Reason       string
 	Result       string
+	Tags         string
+	GraphFile    string
+	Silenced     int          // id of silence currently suppressing delivery
 	OvStatus     argus.Status // status that caused the notification
 	PrevOv       argus.Status // status prior to OvStatus
 	CurrOv       argus.Status // current status
 	Status       map[string]string
+	Tickets      map[string]*TicketDat // external ticket ids, by dst
 	SendTo       []SendDat
+	Routing      []RouteDat // which routing rules matched, and why
 	Log          []LogDat
 }
 type ExportInfo struct {
@@ -123,7 +144,14 @@ var globalDefaults = GlobalConf{
 	ACL_NotifyDetail: "staff root",
 	ACL_NotifyList:   "staff root",
 	ACL_NotifyAck:    "staff root",
+	ACL_Silence:      "staff root",
 	Notify_Discard:   30 * 24 * 3600,
+	Silence_Keep:     90 * 24 * 3600,
+	Link_Ack_Time:    2 * 3600,
+	Link_Snooze_Time: 3600,
+
+	Notify_Graph:       "none",
+	Notify_Graph_Range: 6 * 3600,
 }
 var NotifyCfDefaults = Conf{
 	Renotify:      300,
@@ -161,11 +189,9 @@ func (n *N) updateStatus(status argus.Status) bool {
 	n.lock.Lock()
 	defer n.lock.Unlock()
 
-	if !n.p.IsActive {
-		return false
-	}
+	// keep tracking while acked, in case the ack expires
 	n.p.CurrOv = status
-	return true
+	return n.p.IsActive
 }
 
 func (n *N) log(who string, msg string) {
@@ -181,6 +207,31 @@ func (n *N) IdNo() int {
 	return n.p.IdNo
 }
 
+func (n *N) IsActive() bool {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	return n.p.IsActive
+}
+
+// copy of the audit log
+func (n *N) Logs() []LogDat {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	return append([]LogDat(nil), n.p.Log...)
+}
+
+func (n *N) Message() string {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	return n.p.Message
+}
+
+func Find(idno int) *N {
+	lock.RLock()
+	defer lock.RUnlock()
+	return byid[idno]
+}
+
 func (n *N) WebExport() *ExportInfo {
 	n.lock.RLock()
 	defer n.lock.RUnlock()
@@ -208,6 +259,7 @@ func nextIdNo() int {
 
 func Init() {
 	loadIdNo()
+	loadSilences()
 	go worker()
 
 	sched.NewFunc(&sched.Conf{
@@ -229,6 +281,12 @@ func Stop() {
 
 func Configure(cf *configure.CF) {
 	cf.InitFromConfig(&globalDefaults, "notify", "")
+
+	switch globalDefaults.Notify_Graph {
+	case "none", "link", "attach":
+	default:
+		cf.Error("invalid notify_graph '%s'", globalDefaults.Notify_Graph)
+	}
 }
 
 // ################################################################
@@ -300,9 +358,18 @@ func notifMaintenance() {
 	lock.Lock()
 	defer lock.Unlock()
 
+	// sticky acks expired?
+	for _, n := range byid {
+		n.maybeUnAck(now)
+	}
+
 	// resend? timeout?
 	for _, n := range actives {
-		if n.cf.UnAck_Timeout > 0 && n.p.Created+n.cf.UnAck_Timeout < now {
+		start := n.p.Created
+		if n.p.UnAcked > start {
+			start = n.p.UnAcked
+		}
+		if n.cf.UnAck_Timeout > 0 && start+n.cf.UnAck_Timeout < now {
 			n.ack("timeout")
 			continue
 		}
@@ -320,6 +387,7 @@ func notifMaintenance() {
 
 func janitor() {
 	cleanOldFiles()
+	cleanSilences()
 }
 
 func cleanOldFiles() {
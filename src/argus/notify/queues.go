@@ -7,6 +7,7 @@ package notify
 
 import (
 	"argus.domain/argus/clock"
+	"argus.domain/argus/users"
 )
 
 type queuedat struct {
@@ -31,6 +32,11 @@ func (n *N) maybeQueue() {
 		return
 	}
 
+	// delivery is suppressed, status is not
+	if n.isSilenced() {
+		return
+	}
+
 	// initial send/escalate
 	if len(n.p.SendTo) > n.p.StepNo {
 		s := &n.p.SendTo[n.p.StepNo]
@@ -39,6 +45,7 @@ func (n *N) maybeQueue() {
 			addToQueue(n, s.Dst)
 			if n.p.StepNo > 0 {
 				n.p.Escalated = true
+				n.ticketsEscalate(s.Dst)
 			}
 			n.p.StepNo++
 		}
@@ -48,6 +55,9 @@ func (n *N) maybeQueue() {
 	if n.cf.Renotify == 0 {
 		return
 	}
+	if n.p.SnoozeUntil > now {
+		return
+	}
 
 	for i := range n.p.SendTo {
 		s := &n.p.SendTo[i]
@@ -68,7 +78,23 @@ func (n *N) maybeQueue() {
 // called with package+notify locks held
 func addToQueue(n *N, dst []string) {
 
+	now := clock.Now()
+
+	// expand user:name + group:name into their contacts
+	var all []string
 	for _, d := range dst {
+		exp := users.Expand(d, n.p.OvStatus, now)
+		if len(exp) == 0 {
+			n.log(d, "no permitted contacts")
+		}
+		for _, c := range exp {
+			if !contains(all, c) {
+				all = append(all, c)
+			}
+		}
+	}
+
+	for _, d := range all {
 		qd, ok := dstQueue[d]
 		if !ok {
 			meth, addr := methodForDst(d)
@@ -129,6 +155,7 @@ func runQueues() {
 				n.lock.Lock()
 				n.p.LastSent = now
 				n.log(dst, "transmit")
+				n.history("notify", "", "sent to "+dst)
 				n.p.Status[dst] = "sent"
 				n.lock.Unlock()
 
@@ -0,0 +1,205 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-23 18:20 (EDT)
+// Function: rest api - notifications + acks
+
+package notify
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/web"
+)
+
+type restNotify struct {
+	Id          int
+	Created     int64
+	LastSent    int64
+	Active      bool
+	Status      string
+	Obj         string
+	Message     string
+	AckExpires  int64
+	SnoozeUntil int64
+}
+
+var restIdParam = web.RESTParam{Name: "id", Type: "integer", Required: true, Desc: "notification id"}
+
+func init() {
+	web.AddREST(&web.RESTRoute{
+		Method:  "GET",
+		Path:    "/notifications",
+		Scope:   "read",
+		Tag:     "notifications",
+		Summary: "list notifications, newest first",
+		Params: []web.RESTParam{
+			{Name: "active", Type: "boolean", Desc: "only unacked notifications"},
+			{Name: "obj", Type: "string", Desc: "only for objects under this one"},
+			{Name: "limit", Type: "integer", Desc: "maximum number of results. default: 1000"},
+		},
+		F: restList,
+	})
+	web.AddREST(&web.RESTRoute{
+		Method:  "GET",
+		Path:    "/notification",
+		Scope:   "read",
+		Tag:     "notifications",
+		Summary: "a notification's details + log",
+		Params:  []web.RESTParam{restIdParam},
+		F:       restGet,
+	})
+	web.AddREST(&web.RESTRoute{
+		Method:  "POST",
+		Path:    "/ack",
+		Scope:   "write",
+		Tag:     "notifications",
+		Summary: "ack (or snooze) a notification",
+		Params: []web.RESTParam{
+			restIdParam,
+			{Name: "for", Type: "string", Desc: "sticky ack, for this long, eg. 2h"},
+			{Name: "snooze", Type: "string", Desc: "do not renotify for this long, eg. 30m"},
+		},
+		F: restAck,
+	})
+}
+
+func restCreds(ctx *web.Context, acls ...string) []string {
+
+	creds := strings.Fields(ctx.User.Groups)
+
+	for _, acl := range acls {
+		if !argus.ACLPermitsUser(acl, creds) {
+			ctx.SendError(403, "not permitted")
+			return nil
+		}
+	}
+	return creds
+}
+
+func restFind(ctx *web.Context) *N {
+
+	idno, err := strconv.Atoi(ctx.Get("id"))
+	if err != nil {
+		ctx.SendError(400, "invalid id")
+		return nil
+	}
+
+	lock.RLock()
+	n := byid[idno]
+	lock.RUnlock()
+
+	if n == nil {
+		ctx.SendError(404, "not found")
+	}
+	return n
+}
+
+func (n *N) restExport() restNotify {
+
+	return restNotify{n.p.IdNo, n.p.Created, n.p.LastSent, n.p.IsActive, n.p.OvStatus.String(),
+		n.p.Unique, n.p.Message, n.p.AckExpires, n.p.SnoozeUntil}
+}
+
+// ################################################################
+
+func restList(ctx *web.Context) {
+
+	if restCreds(ctx, globalDefaults.ACL_NotifyList, globalDefaults.ACL_NotifyDetail) == nil {
+		return
+	}
+
+	limit := 1000
+	if v := ctx.Get("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil || l <= 0 {
+			ctx.SendError(400, "invalid limit")
+			return
+		}
+		limit = l
+	}
+
+	active := argus.CheckBool(ctx.Get("active"))
+	obj := ctx.Get("obj")
+
+	lock.RLock()
+	var all []*N
+	for _, n := range byid {
+		all = append(all, n)
+	}
+	lock.RUnlock()
+
+	list := []restNotify{}
+	for _, n := range all {
+		n.lock.RLock()
+		ok := (!active || n.p.IsActive) &&
+			(obj == "" || n.p.Unique == obj || strings.HasPrefix(n.p.Unique, obj+":"))
+		if ok {
+			list = append(list, n.restExport())
+		}
+		n.lock.RUnlock()
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Id > list[j].Id })
+	if len(list) > limit {
+		list = list[:limit]
+	}
+
+	ctx.SendJSON(200, map[string]interface{}{
+		"unacked": NumActive(),
+		"list":    list,
+	})
+}
+
+func restGet(ctx *web.Context) {
+
+	creds := restCreds(ctx, globalDefaults.ACL_NotifyDetail)
+	if creds == nil {
+		return
+	}
+	n := restFind(ctx)
+	if n == nil {
+		return
+	}
+
+	canAck := argus.ACLPermitsUser(globalDefaults.ACL_NotifyAck, creds)
+
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	ctx.SendJSON(200, struct {
+		restNotify
+		CanAck bool
+		Detail *Persist
+	}{n.restExport(), canAck, &n.p})
+}
+
+func restAck(ctx *web.Context) {
+
+	if restCreds(ctx, globalDefaults.ACL_NotifyDetail, globalDefaults.ACL_NotifyAck) == nil {
+		return
+	}
+	n := restFind(ctx)
+	if n == nil {
+		return
+	}
+
+	dur, snooze, err := ackArgs(ctx.Get)
+	if err != nil {
+		ctx.SendError(400, err.Error())
+		return
+	}
+
+	n.lock.Lock()
+	if snooze {
+		n.snooze(ctx.User.Name, dur)
+	} else {
+		n.ackFor(ctx.User.Name, dur)
+	}
+	res := n.restExport()
+	n.lock.Unlock()
+
+	ctx.SendJSON(200, res)
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 10:12 (EDT)
+// Function: notification routing rules
+
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"argus.domain/argus/api"
+	"argus.domain/argus/argus"
+	"argus.domain/argus/configure"
+	"argus.domain/argus/web"
+)
+
+// rule name {
+//     match_path:     Top:Databases:*
+//     match_tags:     db prod
+//     match_severity: major+
+//     match_darp:     nyc
+//     schedule match_time {
+//         mon-fri 0900 - 1700 => yes
+//     }
+//     route:          qpage:dba-oncall
+//     route_escalate: 30 mail:dba-mgr
+//     continue:       no
+// }
+
+type Rule struct {
+	name           string
+	severity       [argus.CRITICAL + 1]bool
+	Match_Path     string
+	Match_Tags     string
+	Match_Severity string
+	Match_Darp     string
+	Match_Time     *argus.Schedule
+	Route          string
+	Route_Escalate string
+	Continue       bool
+}
+
+type RouteDat struct {
+	Rule  string
+	Match bool
+	Why   string
+}
+
+// the info we match against
+type routeInfo struct {
+	unique string
+	tags   string
+	darp   string
+	status argus.Status
+	when   time.Time
+}
+
+// no lock, these are never modified after startup
+var rules []*Rule
+
+func init() {
+	web.Add(web.PRIVATE, "/api/notifywhy", webWhy)
+	api.Add(true, "notifywhy", apiWhy)
+}
+
+func NewRule(conf *configure.CF) error {
+
+	r := &Rule{name: conf.Name}
+	conf.InitFromConfig(r, "rule", "")
+
+	if r.Route == "" && r.Route_Escalate == "" {
+		return fmt.Errorf("Invalid Notification Rule - route not specified")
+	}
+
+	for _, rr := range rules {
+		if rr.name == conf.Name {
+			return fmt.Errorf("Duplicate Rule '%s'", conf.Name)
+		}
+	}
+
+	r.Match_Tags = strings.ToLower(r.Match_Tags)
+
+	if r.Match_Path != "" {
+		if _, err := path.Match(r.Match_Path, ""); err != nil {
+			return fmt.Errorf("Invalid Notification Rule - bad match_path '%s'", r.Match_Path)
+		}
+	}
+
+	err := r.parseSeverity()
+	if err != nil {
+		return err
+	}
+
+	conf.CheckTypos()
+
+	rules = append(rules, r)
+	return nil
+}
+
+// "major critical", or "minor+" for minor and worse
+func (r *Rule) parseSeverity() error {
+
+	if r.Match_Severity == "" {
+		for i := range r.severity {
+			r.severity[i] = true
+		}
+		return nil
+	}
+
+	for _, s := range strings.Fields(r.Match_Severity) {
+		orWorse := strings.HasSuffix(s, "+")
+		s = strings.TrimSuffix(s, "+")
+
+		st := argus.StatusValue(s)
+		if st == argus.UNKNOWN {
+			return fmt.Errorf("Invalid Notification Rule - bad severity '%s'", s)
+		}
+
+		r.severity[int(st)] = true
+		if orWorse {
+			for ; st <= argus.CRITICAL; st++ {
+				r.severity[int(st)] = true
+			}
+		}
+	}
+
+	return nil
+}
+
+// does the rule match? and why (not)?
+func (r *Rule) match(ri *routeInfo) (bool, string) {
+
+	if int(ri.status) >= len(r.severity) || !r.severity[int(ri.status)] {
+		return false, "severity " + ri.status.String() + " not in '" + r.Match_Severity + "'"
+	}
+
+	if r.Match_Path != "" {
+		if ok, _ := path.Match(r.Match_Path, ri.unique); !ok {
+			return false, "path does not match '" + r.Match_Path + "'"
+		}
+	}
+
+	for _, t := range strings.Fields(r.Match_Tags) {
+		if !argus.IncludesTag(ri.tags, t, false) {
+			return false, "object is not tagged '" + t + "'"
+		}
+	}
+
+	if r.Match_Darp != "" && !argus.IncludesTag(r.Match_Darp, ri.darp, true) {
+		return false, "darp '" + ri.darp + "' not in '" + r.Match_Darp + "'"
+	}
+
+	if r.Match_Time != nil && !r.Match_Time.PermitAt(ri.when, "no") {
+		return false, "outside of scheduled time"
+	}
+
+	why := "matched"
+	if r.Continue {
+		why = "matched, continue"
+	}
+	return true, why
+}
+
+// evaluate the rules, in order. returns the destinations + escalations
+func routeRules(ri *routeInfo) ([]string, []string, []RouteDat) {
+
+	var dst, esc []string
+	var log []RouteDat
+
+	for i, r := range rules {
+		ok, why := r.match(ri)
+		log = append(log, RouteDat{Rule: r.name, Match: ok, Why: why})
+
+		if !ok {
+			continue
+		}
+
+		dst = append(dst, strings.Fields(r.Route)...)
+		if r.Route_Escalate != "" {
+			esc = append(esc, r.Route_Escalate)
+		}
+
+		if !r.Continue {
+			for _, rr := range rules[i+1:] {
+				log = append(log, RouteDat{Rule: rr.name, Why: "not checked, stopped by '" + r.name + "'"})
+			}
+			break
+		}
+	}
+
+	return dst, esc, log
+}
+
+// ################################################################
+
+func webWhy(ctx *web.Context) {
+
+	n, _ := webGetNotifyCreds(ctx)
+	if n == nil {
+		return
+	}
+
+	n.lock.RLock()
+	d := struct {
+		IdNo    int
+		Unique  string
+		Routing []RouteDat
+		SendTo  []SendDat
+	}{n.p.IdNo, n.p.Unique, n.p.Routing, n.p.SendTo}
+	js, _ := json.MarshalIndent(d, "", "  ")
+	n.lock.RUnlock()
+
+	ctx.W.Header().Set("Content-Type", "application/json; charset=utf-8")
+	ctx.W.Write(js)
+}
+
+func apiWhy(ctx *api.Context) {
+
+	idno, _ := strconv.ParseInt(ctx.Args["idno"], 10, 32)
+	lock.RLock()
+	n := byid[int(idno)]
+	lock.RUnlock()
+
+	if n == nil {
+		ctx.Send404()
+		return
+	}
+
+	ctx.SendOK()
+	n.lock.RLock()
+	for _, r := range n.p.Routing {
+		ctx.SendKVP(r.Rule, r.Why)
+	}
+	for i, s := range n.p.SendTo {
+		ctx.SendKVP(fmt.Sprintf("step%d", i), fmt.Sprintf("%d %s", s.When, strings.Join(s.Dst, " ")))
+	}
+	n.lock.RUnlock()
+	ctx.SendFinal()
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 11:02 (EDT)
+// Function:
+
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+)
+
+func ruleTest(t *testing.T, ri *routeInfo, exp string) {
+
+	dst, _, log := routeRules(ri)
+	got := strings.Join(dst, " ")
+
+	if got != exp {
+		fmt.Printf("%s/%s -> '%s' != '%s'\n%v\n", ri.unique, ri.status, got, exp, log)
+		t.Fail()
+	}
+}
+
+func TestRules(t *testing.T) {
+
+	rules = []*Rule{
+		{name: "db", Match_Path: "Top:DB:*", Match_Severity: "major+", Route: "qpage:dba", Continue: true},
+		{name: "prod", Match_Tags: "prod", Route: "mail:ops"},
+		{name: "nyc", Match_Darp: "nyc", Route: "mail:nyc"},
+		{name: "all", Route: "mail:noc"},
+	}
+	for _, r := range rules {
+		r.parseSeverity()
+	}
+	defer func() { rules = nil }()
+
+	ruleTest(t, &routeInfo{"Top:DB:Pg", "prod", "local", argus.CRITICAL, clock.Now()}, "qpage:dba mail:ops")
+	ruleTest(t, &routeInfo{"Top:DB:Pg", "", "local", argus.MINOR, clock.Now()}, "mail:noc")
+	ruleTest(t, &routeInfo{"Top:Web:X", "", "nyc", argus.MAJOR, clock.Now()}, "mail:nyc")
+	ruleTest(t, &routeInfo{"Top:Web:X", "dev prod", "sfo", argus.MAJOR, clock.Now()}, "mail:ops")
+}
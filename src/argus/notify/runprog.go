@@ -8,19 +8,21 @@ package notify
 import (
 	"context"
 	"io"
+	"os"
 	"os/exec"
 	"time"
 )
 
 const TIMEOUT = 15 * time.Second
 
-func runCommand(command string, send string) {
+func runCommand(command string, send string, env ...string) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), TIMEOUT)
 	defer cancel()
 
 	dl.Debug("running '%s'", command)
 	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), env...)
 
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
@@ -0,0 +1,435 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 12:40 (EDT)
+// Function: silences - suppress delivery, status is unchanged
+
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"argus.domain/argus/api"
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/config"
+	"argus.domain/argus/web"
+)
+
+type Silence struct {
+	Id          int
+	Match_Path  string
+	Match_Tag   string
+	Match_Regex string
+	Start       int64
+	End         int64 // required for new silences. 0 (never) only from older save files
+	Creator     string
+	Comment     string
+	Created     int64
+	EndedBy     string // if ended early
+	re          *regexp.Regexp
+}
+
+type silenceSave struct {
+	NextId int
+	List   []*Silence
+}
+
+var silenceLock sync.RWMutex
+var silences []*Silence
+var silenceId = 1
+
+func init() {
+	web.Add(web.PRIVATE, "/api/listsilence", webSilenceList)
+	web.Add(web.WRITE, "/api/silence", webSilence)
+	api.Add(true, "silence", apiSilence)
+	api.Add(true, "unsilence", apiUnSilence)
+	api.Add(true, "silences", apiSilenceList)
+}
+
+func (s *Silence) compile() error {
+
+	if s.Match_Path == "" && s.Match_Tag == "" && s.Match_Regex == "" {
+		return errors.New("silence must specify a path, tag, or regex")
+	}
+	if s.Match_Path != "" {
+		if _, err := path.Match(s.Match_Path, ""); err != nil {
+			return fmt.Errorf("invalid path '%s'", s.Match_Path)
+		}
+	}
+	if s.Match_Regex != "" {
+		re, err := regexp.Compile(s.Match_Regex)
+		if err != nil {
+			return fmt.Errorf("invalid regex '%s': %v", s.Match_Regex, err)
+		}
+		s.re = re
+	}
+	if s.End != 0 && s.End <= s.Start {
+		return errors.New("silence ends before it starts")
+	}
+
+	s.Match_Tag = strings.ToLower(s.Match_Tag)
+	return nil
+}
+
+func (s *Silence) isActive(now int64) bool {
+
+	if s.Start > now {
+		return false
+	}
+	if s.End != 0 && s.End <= now {
+		return false
+	}
+	return true
+}
+
+func (s *Silence) matches(unique string, tags string) bool {
+
+	if s.Match_Path != "" {
+		if ok, _ := path.Match(s.Match_Path, unique); !ok {
+			return false
+		}
+	}
+	if s.Match_Tag != "" && !argus.IncludesTag(tags, s.Match_Tag, false) {
+		return false
+	}
+	if s.re != nil && !s.re.MatchString(unique) {
+		return false
+	}
+	return true
+}
+
+// a silence that never ends is too easily forgotten, an end is required
+func AddSilence(s *Silence) error {
+
+	if s.End == 0 {
+		return errors.New("silence must specify an end or duration")
+	}
+
+	err := s.compile()
+	if err != nil {
+		return err
+	}
+
+	s.Created = clock.Unix()
+	if s.Start == 0 {
+		s.Start = s.Created
+	}
+
+	silenceLock.Lock()
+	s.Id = silenceId
+	silenceId++
+	silences = append(silences, s)
+	saveSilences()
+	silenceLock.Unlock()
+
+	dl.Verbose("silence #%d added by %s", s.Id, s.Creator)
+	return nil
+}
+
+// end the silence now. it is kept for audit
+func EndSilence(id int, who string) bool {
+
+	now := clock.Unix()
+
+	silenceLock.Lock()
+	defer silenceLock.Unlock()
+
+	for _, s := range silences {
+		if s.Id != id {
+			continue
+		}
+		if s.End == 0 || s.End > now {
+			s.End = now
+			s.EndedBy = who
+			saveSilences()
+		}
+		return true
+	}
+	return false
+}
+
+// find an active silence for the object
+func silencedBy(unique string, tags string) *Silence {
+	return silencedAt(unique, tags, clock.Unix())
+}
+
+func silencedAt(unique string, tags string, now int64) *Silence {
+
+	silenceLock.RLock()
+	defer silenceLock.RUnlock()
+
+	for _, s := range silences {
+		if s.isActive(now) && s.matches(unique, tags) {
+			return s
+		}
+	}
+	return nil
+}
+
+// n.lock is already held
+func (n *N) isSilenced() bool {
+
+	s := silencedBy(n.p.Unique, n.p.Tags)
+
+	if s == nil {
+		if n.p.Silenced != 0 {
+			n.log("system", fmt.Sprintf("silence #%d ended", n.p.Silenced))
+			n.p.Silenced = 0
+		}
+		return false
+	}
+
+	if n.p.Silenced != s.Id {
+		n.log("system", fmt.Sprintf("silenced by #%d", s.Id))
+		n.p.Silenced = s.Id
+	}
+	return true
+}
+
+// remove expired silences older than Silence_Keep
+func cleanSilences() {
+
+	if globalDefaults.Silence_Keep == 0 {
+		return
+	}
+
+	old := clock.Unix() - globalDefaults.Silence_Keep
+
+	silenceLock.Lock()
+	defer silenceLock.Unlock()
+
+	var keep []*Silence
+	for _, s := range silences {
+		if s.End != 0 && s.End < old {
+			continue
+		}
+		keep = append(keep, s)
+	}
+
+	if len(keep) != len(silences) {
+		silences = keep
+		saveSilences()
+	}
+}
+
+// ################################################################
+
+func loadSilences() {
+
+	cf := config.Cf()
+	if cf.Datadir == "" {
+		dl.Debug("datadir not configured. not loading")
+		return
+	}
+
+	var ss silenceSave
+	err := argus.Load(cf.Datadir+"/silences", &ss)
+	if err != nil {
+		dl.Debug("cannot open file: %v", err)
+		return
+	}
+
+	silenceLock.Lock()
+	defer silenceLock.Unlock()
+
+	for _, s := range ss.List {
+		err := s.compile()
+		if err != nil {
+			dl.Problem("discarding silence #%d: %v", s.Id, err)
+			continue
+		}
+		silences = append(silences, s)
+	}
+	if ss.NextId > silenceId {
+		silenceId = ss.NextId
+	}
+}
+
+// silenceLock is already held
+func saveSilences() {
+
+	cf := config.Cf()
+	if cf.Datadir == "" {
+		dl.Debug("datadir not configured. not saving")
+		return
+	}
+	file := cf.Datadir + "/silences"
+
+	err := argus.Save(file, &silenceSave{silenceId, silences})
+
+	if err != nil {
+		dl.Problem("cannot save silences to '%s': %v", file, err)
+	}
+}
+
+// ################################################################
+
+type silenceExport struct {
+	Silence
+	IsActive bool
+}
+
+// newest first
+func listSilences() []silenceExport {
+
+	now := clock.Unix()
+
+	silenceLock.RLock()
+	defer silenceLock.RUnlock()
+
+	var res []silenceExport
+	for _, s := range silences {
+		res = append(res, silenceExport{*s, s.isActive(now)})
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Id > res[j].Id })
+	return res
+}
+
+func webSilenceList(ctx *web.Context) {
+
+	if ctx.User == nil {
+		ctx.W.WriteHeader(403)
+		return
+	}
+
+	creds := strings.Fields(ctx.User.Groups)
+
+	if !argus.ACLPermitsUser(globalDefaults.ACL_NotifyList, creds) {
+		ctx.W.WriteHeader(403)
+		return
+	}
+
+	d := make(map[string]interface{})
+	d["list"] = listSilences()
+	d["canEdit"] = argus.ACLPermitsUser(globalDefaults.ACL_Silence, creds)
+
+	js, _ := json.MarshalIndent(d, "", "  ")
+	ctx.W.Header().Set("Content-Type", "application/json; charset=utf-8")
+	ctx.W.Write(js)
+}
+
+func webSilence(ctx *web.Context) {
+
+	if ctx.User == nil {
+		ctx.W.WriteHeader(403)
+		return
+	}
+
+	creds := strings.Fields(ctx.User.Groups)
+
+	if !argus.ACLPermitsUser(globalDefaults.ACL_Silence, creds) {
+		dl.Debug("denied")
+		ctx.W.WriteHeader(403)
+		return
+	}
+
+	if id := ctx.Get("remove"); id != "" {
+		idno, _ := strconv.Atoi(id)
+		if !EndSilence(idno, ctx.User.Name) {
+			ctx.W.WriteHeader(404)
+			return
+		}
+	} else {
+		s, err := silenceFromArgs(ctx.Get)
+		if err == nil {
+			s.Creator = ctx.User.Name
+			err = AddSilence(s)
+		}
+		if err != nil {
+			dl.Debug("invalid silence: %v", err)
+			ctx.W.WriteHeader(400)
+			return
+		}
+	}
+
+	webSilenceList(ctx)
+}
+
+// path, tag, regex, comment, start, end | duration
+func silenceFromArgs(get func(string) string) (*Silence, error) {
+
+	s := &Silence{
+		Match_Path:  get("path"),
+		Match_Tag:   get("tag"),
+		Match_Regex: get("regex"),
+		Comment:     get("comment"),
+	}
+
+	s.Start, _ = strconv.ParseInt(get("start"), 10, 64)
+	s.End, _ = strconv.ParseInt(get("end"), 10, 64)
+
+	if d := get("duration"); d != "" {
+		dur, err := argus.Timespec(d, 60)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration '%s'", d)
+		}
+		start := s.Start
+		if start == 0 {
+			start = clock.Unix()
+		}
+		s.End = start + dur
+	}
+
+	return s, nil
+}
+
+func apiSilence(ctx *api.Context) {
+
+	s, err := silenceFromArgs(func(k string) string { return ctx.Args[k] })
+	if err == nil {
+		s.Creator = ctx.Args["user"]
+		if s.Creator == "" {
+			s.Creator = "api"
+		}
+		err = AddSilence(s)
+	}
+
+	if err != nil {
+		ctx.SendResponseFinal(400, err.Error())
+		return
+	}
+
+	ctx.SendOK()
+	ctx.SendKVP("id", fmt.Sprintf("%d", s.Id))
+	ctx.SendFinal()
+}
+
+func apiUnSilence(ctx *api.Context) {
+
+	idno, _ := strconv.Atoi(ctx.Args["id"])
+	who := ctx.Args["user"]
+	if who == "" {
+		who = "api"
+	}
+
+	if !EndSilence(idno, who) {
+		ctx.Send404()
+		return
+	}
+	ctx.SendOKFinal()
+}
+
+func apiSilenceList(ctx *api.Context) {
+
+	ctx.SendOK()
+	for _, s := range listSilences() {
+		state := "expired"
+		if s.IsActive {
+			state = "active"
+		} else if s.Start > clock.Unix() {
+			state = "pending"
+		}
+		match := strings.TrimSpace(fmt.Sprintf("%s %s %s", s.Match_Path, s.Match_Tag, s.Match_Regex))
+		ctx.SendKVP(fmt.Sprintf("%d", s.Id), fmt.Sprintf("%s %d %d %s [%s] %s", state, s.Start, s.End, s.Creator, match, s.Comment))
+	}
+	ctx.SendFinal()
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-24 09:40 (EDT)
+// Function:
+
+package notify
+
+import (
+	"fmt"
+	"testing"
+
+	"argus.domain/argus/clock"
+	"argus.domain/argus/config"
+)
+
+func TestSilenceMatch(t *testing.T) {
+
+	tests := []struct {
+		s      Silence
+		unique string
+		tags   string
+		exp    bool
+	}{
+		{Silence{Match_Path: "Top:DB:*"}, "Top:DB:pg1", "", true},
+		{Silence{Match_Path: "Top:DB:*"}, "Top:Web:www", "", false},
+		{Silence{Match_Tag: "Prod"}, "Top:Web:www", "web prod", true},
+		{Silence{Match_Tag: "prod"}, "Top:Web:www", "web dev", false},
+		{Silence{Match_Regex: "pg[0-9]$"}, "Top:DB:pg1", "", true},
+		{Silence{Match_Path: "Top:DB:*", Match_Tag: "prod"}, "Top:DB:pg1", "dev", false},
+	}
+
+	for i, x := range tests {
+		s := x.s
+		if err := s.compile(); err != nil {
+			fmt.Printf("%d: %v\n", i, err)
+			t.Fail()
+			continue
+		}
+		if s.matches(x.unique, x.tags) != x.exp {
+			fmt.Printf("%d: %s expected %v\n", i, x.unique, x.exp)
+			t.Fail()
+		}
+	}
+
+	if err := (&Silence{}).compile(); err == nil {
+		fmt.Printf("empty silence accepted\n")
+		t.Fail()
+	}
+}
+
+func TestSilenceExpire(t *testing.T) {
+
+	config.Cf().Datadir = ""
+
+	reset := func() {
+		silenceLock.Lock()
+		silences = nil
+		silenceLock.Unlock()
+	}
+	reset()
+	defer reset()
+
+	s := &Silence{Match_Path: "*", Start: 1000, End: 2000}
+	if s.isActive(999) || !s.isActive(1000) || !s.isActive(1999) || s.isActive(2000) {
+		fmt.Printf("active range\n")
+		t.Fail()
+	}
+
+	// open-ended silences, from older save files, do not expire
+	old := &Silence{Match_Path: "*", Start: 1000}
+	if !old.isActive(1 << 40) {
+		fmt.Printf("open ended\n")
+		t.Fail()
+	}
+
+	// but new ones must end
+	if err := AddSilence(&Silence{Match_Path: "Top:X"}); err == nil {
+		fmt.Printf("silence without end accepted\n")
+		t.Fail()
+	}
+
+	ns := &Silence{Match_Path: "Top:Y:*", Start: 1000, End: 2000}
+	if err := AddSilence(ns); err != nil {
+		t.FailNow()
+	}
+	if silencedAt("Top:Y:z", "", 1500) != ns || silencedAt("Top:Y:z", "", 2500) != nil {
+		fmt.Printf("silencedAt\n")
+		t.Fail()
+	}
+
+	now := clock.Unix()
+	cur := &Silence{Match_Tag: "db", Start: now - 60, End: now + 3600}
+	AddSilence(cur)
+	if silencedBy("Top:Y:z", "db") != cur {
+		fmt.Printf("current silence\n")
+		t.Fail()
+	}
+
+	EndSilence(cur.Id, "bob")
+	if cur.EndedBy != "bob" || silencedBy("Top:Y:z", "db") != nil {
+		fmt.Printf("ended: %+v\n", cur)
+		t.Fail()
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 22:31 (EDT)
+// Function: notification dry-run - who would be paged?
+
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/users"
+)
+
+type Plan struct {
+	Unique   string
+	OvStatus argus.Status
+	Time     int64
+	Message  string
+	Silenced int   // would be suppressed by this silence
+	Renotify int64 // resend interval, until acked
+	AutoAck  bool  // sent once, then acked
+	Routing  []RouteDat
+	Sends    []PlanDat
+}
+
+type PlanDat struct {
+	Step    int
+	Delay   int64 // after the event
+	When    int64
+	Dst     string // as configured
+	Contact string // after user + group expansion
+	Method  string
+	Result  string // send, or why not
+}
+
+// Simulate determines who would be sent what, when, and how - without creating a notification
+func Simulate(ncf *NewConf, when time.Time) *Plan {
+
+	n := &N{
+		cf: ncf.Conf,
+		p: Persist{
+			Created:      when.Unix(),
+			Unique:       ncf.Unique,
+			ShortName:    ncf.ShortName,
+			FriendlyName: ncf.FriendlyName,
+			Reason:       ncf.Reason,
+			Result:       ncf.Result,
+			Tags:         ncf.Tags,
+			OvStatus:     ncf.OvStatus,
+			PrevOv:       ncf.PrevOv,
+			CurrOv:       ncf.OvStatus,
+		},
+	}
+
+	n.determineMessage(ncf)
+	n.determineSendTo(ncf, when)
+
+	plan := &Plan{
+		Unique:   n.p.Unique,
+		OvStatus: n.p.OvStatus,
+		Time:     when.Unix(),
+		Message:  n.p.Message,
+		Renotify: n.cf.Renotify,
+		AutoAck:  n.cf.AutoAck[int(n.p.OvStatus)] || n.cf.AutoAck[int(argus.UNKNOWN)],
+		Routing:  n.p.Routing,
+	}
+
+	if s := silencedAt(n.p.Unique, n.p.Tags, when.Unix()); s != nil {
+		plan.Silenced = s.Id
+	}
+
+	for i, s := range n.p.SendTo {
+		at := when.Add(time.Duration(s.When) * time.Second)
+
+		for _, d := range s.Dst {
+			pd := PlanDat{Step: i, Delay: s.When, When: at.Unix(), Dst: d}
+
+			exp := users.Expand(d, n.p.OvStatus, at)
+			if len(exp) == 0 {
+				pd.Result = "no permitted contacts"
+				plan.Sends = append(plan.Sends, pd)
+				continue
+			}
+
+			for _, c := range exp {
+				pd.Contact = c
+				pd.Method, pd.Result = simulateMethod(c, n.p.OvStatus, at)
+				plan.Sends = append(plan.Sends, pd)
+			}
+		}
+	}
+
+	return plan
+}
+
+func simulateMethod(dst string, sev argus.Status, at time.Time) (string, string) {
+
+	name := "mail"
+	if f := strings.SplitN(dst, ":", 2); len(f) == 2 {
+		name = f[0]
+	}
+
+	meth, _ := methodForDst(dst)
+	switch {
+	case meth == nil:
+		return name, "unknown method"
+	case !meth.permitAt(sev, at):
+		// delivery does not check the permit schedule, say so
+		return name, "send (Permit is not enforced)"
+	case meth.ticket != nil:
+		return name, "open ticket"
+	case meth.Qtime > 0:
+		return name, "send, batched up to " + argus.Elapsed(meth.Qtime)
+	}
+	return name, "send"
+}
+
+// ################################################################
+
+// text version, for the control api
+func (p *Plan) Lines() []string {
+
+	var res []string
+
+	res = append(res, fmt.Sprintf("%s %s at %s: %s", p.Unique, p.OvStatus,
+		time.Unix(p.Time, 0).Format("Mon 2006-01-02 15:04 MST"), p.Message))
+
+	if p.Silenced != 0 {
+		res = append(res, fmt.Sprintf("delivery suppressed by silence #%d", p.Silenced))
+	}
+	for _, r := range p.Routing {
+		res = append(res, fmt.Sprintf("rule %s: %s", r.Rule, r.Why))
+	}
+	if len(p.Sends) == 0 {
+		res = append(res, "nobody would be notified")
+	}
+	for _, s := range p.Sends {
+		c := s.Contact
+		if c == "" {
+			c = s.Dst
+		} else if c != s.Dst {
+			c = s.Dst + " => " + c
+		}
+		res = append(res, fmt.Sprintf("+%s %s [%s] %s",
+			argus.Elapsed(s.Delay), c, s.Method, s.Result))
+	}
+	if p.AutoAck {
+		res = append(res, "auto-acked after sending")
+	} else if p.Renotify != 0 {
+		res = append(res, "resent every "+argus.Elapsed(p.Renotify)+" until acked")
+	}
+
+	return res
+}
+
+func PermitsDetail(creds []string) bool {
+	return argus.ACLPermitsUser(globalDefaults.ACL_NotifyDetail, creds)
+}
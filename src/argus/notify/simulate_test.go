@@ -0,0 +1,60 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 23:20 (EDT)
+// Function:
+
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"argus.domain/argus/argus"
+)
+
+func TestSimulate(t *testing.T) {
+
+	// only at night
+	sched := &argus.Schedule{}
+	sched.Append(-1, 0, 600, "yes")
+	sched.Append(-1, 600, 2400, "no")
+	night := &Method{Command: "cat"}
+	night.Permit[int(argus.UNKNOWN)] = sched
+	methods["night"] = night
+	defer delete(methods, "night")
+
+	cf := NewCF()
+	cf.Notify[int(argus.UNKNOWN)] = argus.ScheduleAlways("mail:ops night:oncall")
+	cf.Escalate[int(argus.UNKNOWN)] = "30 qpage:boss"
+
+	ncf := &NewConf{Conf: cf, Unique: "Top:DB:primary", FriendlyName: "DB primary", OvStatus: argus.CRITICAL}
+
+	sat := time.Date(2026, 10, 24, 3, 0, 0, 0, time.Local)
+	simTest(t, Simulate(ncf, sat), []string{
+		"0 mail:ops send, batched up to 00:05:00",
+		"0 night:oncall send",
+		"1800 qpage:boss send",
+	})
+
+	noon := time.Date(2026, 10, 24, 12, 0, 0, 0, time.Local)
+	simTest(t, Simulate(ncf, noon), []string{
+		"0 mail:ops send, batched up to 00:05:00",
+		"0 night:oncall send (Permit is not enforced)",
+		"1800 qpage:boss send",
+	})
+}
+
+func simTest(t *testing.T, p *Plan, exp []string) {
+
+	var got []string
+	for _, s := range p.Sends {
+		got = append(got, fmt.Sprintf("%d %s %s", s.Delay, s.Contact, s.Result))
+	}
+
+	if strings.Join(got, "; ") != strings.Join(exp, "; ") {
+		fmt.Printf("plan: %s\n", strings.Join(p.Lines(), "\n"))
+		t.Fail()
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 18:10 (EDT)
+// Function: sticky acks, snooze, signed links
+
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/config"
+	"argus.domain/argus/web"
+)
+
+const LINKEXPIRE = 7 * 24 * 3600
+
+var linkKey []byte
+var linkKeyLock sync.Mutex
+
+func init() {
+	web.Add(web.PUBLIC, "/api/notifylink", webLink)
+}
+
+// package lock + notify are already held
+func (n *N) ackFor(who string, dur int64) {
+
+	n.ack(who)
+
+	if dur <= 0 {
+		return
+	}
+
+	n.p.AckExpires = clock.Unix() + dur
+	n.log(who, "ack expires in "+argus.Elapsed(dur))
+	n.Save()
+}
+
+// package lock + notify are already held
+func (n *N) snooze(who string, dur int64) {
+
+	n.p.SnoozeUntil = clock.Unix() + dur
+	n.log(who, "snoozed for "+argus.Elapsed(dur))
+	n.history("snooze", who, "snoozed for "+argus.Elapsed(dur))
+	n.Save()
+}
+
+// package lock is already held
+// un-ack if the ack has expired and the object is still down
+func (n *N) maybeUnAck(now int64) {
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.p.IsActive || n.p.AckExpires == 0 || n.p.AckExpires > now {
+		return
+	}
+
+	n.p.AckExpires = 0
+
+	if n.p.OvStatus == argus.CLEAR || n.p.CurrOv < argus.WARNING || n.p.CurrOv > argus.CRITICAL {
+		// it's up (or overridden), leave it acked
+		n.log("system", "ack expired")
+		n.Save()
+		return
+	}
+
+	n.p.IsActive = true
+	n.p.UnAcked = now
+	actives[n.p.IdNo] = n
+	NActive.Set(int64(len(actives)))
+
+	n.log("system", "ack expired, unacked")
+	n.history("ack", "system", "ack expired, unacked")
+	for dst, _ := range n.p.Status {
+		n.p.Status[dst] = "unacked"
+	}
+	n.Save()
+}
+
+// ################################################################
+
+// signed ack/snooze links, for use in messages
+func (n *N) signedLink(act string, dur int64) string {
+
+	if globalDefaults.Web_Url == "" {
+		return ""
+	}
+
+	exp := n.p.Created + LINKEXPIRE
+	v := url.Values{}
+	v.Set("idno", fmt.Sprintf("%d", n.p.IdNo))
+	v.Set("act", act)
+	v.Set("for", fmt.Sprintf("%d", dur))
+	v.Set("exp", fmt.Sprintf("%d", exp))
+	v.Set("sig", linkSig(n.p.IdNo, act, dur, exp))
+
+	return globalDefaults.Web_Url + "/api/notifylink?" + v.Encode()
+}
+
+func linkSig(idno int, act string, dur int64, exp int64) string {
+
+	mac := hmac.New(sha256.New, getLinkKey())
+	fmt.Fprintf(mac, "%d|%s|%d|%d", idno, act, dur, exp)
+	return argus.Encode64Url(string(mac.Sum(nil)))
+}
+
+// the key is generated once, and saved
+func getLinkKey() []byte {
+
+	linkKeyLock.Lock()
+	defer linkKeyLock.Unlock()
+
+	if linkKey != nil {
+		return linkKey
+	}
+
+	cf := config.Cf()
+	file := cf.Datadir + "/linkkey"
+
+	if cf.Datadir != "" {
+		err := argus.Load(file, &linkKey)
+		if err == nil && len(linkKey) != 0 {
+			return linkKey
+		}
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		// never sign with a predictable key
+		dl.Fatal("cannot generate link key: %v", err)
+	}
+	linkKey = key
+
+	if cf.Datadir != "" {
+		err := argus.Save(file, linkKey)
+		if err != nil {
+			dl.Problem("cannot save link key to '%s': %v", file, err)
+		}
+	}
+
+	return linkKey
+}
+
+func webLink(ctx *web.Context) {
+
+	idno, _ := strconv.Atoi(ctx.Get("idno"))
+	act := ctx.Get("act")
+	dur, _ := strconv.ParseInt(ctx.Get("for"), 10, 64)
+	exp, _ := strconv.ParseInt(ctx.Get("exp"), 10, 64)
+	sig := ctx.Get("sig")
+
+	if !hmac.Equal([]byte(sig), []byte(linkSig(idno, act, dur, exp))) || exp < clock.Unix() {
+		dl.Debug("invalid link")
+		ctx.W.WriteHeader(403)
+		return
+	}
+	if act != "ack" && act != "snooze" {
+		ctx.W.WriteHeader(400)
+		return
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	n := byid[idno]
+	if n == nil {
+		ctx.W.WriteHeader(404)
+		return
+	}
+
+	// link scanners + prefetchers fetch links. only act on a post
+	if ctx.R.Method != "POST" {
+		n.lock.RLock()
+		obj := n.p.Unique
+		n.lock.RUnlock()
+		linkConfirm(ctx, obj)
+		return
+	}
+
+	n.lock.Lock()
+	active := n.p.IsActive
+	if active {
+		if act == "ack" {
+			n.ackFor("link", dur)
+		} else {
+			n.snooze("link", dur)
+		}
+	}
+	n.lock.Unlock()
+
+	msg := fmt.Sprintf("notification %d: %s ok\n", idno, act)
+	if !active {
+		msg = fmt.Sprintf("notification %d is already acked\n", idno)
+	}
+
+	ctx.W.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	ctx.W.Write([]byte(msg))
+}
+
+// post the same signed params back
+func linkConfirm(ctx *web.Context, obj string) {
+
+	act := ctx.Get("act")
+	dur, _ := strconv.ParseInt(ctx.Get("for"), 10, 64)
+
+	what := "Acknowledge"
+	if act == "snooze" {
+		what = "Snooze"
+	}
+	if dur > 0 {
+		what += " for " + argus.Elapsed(dur)
+	}
+
+	var hidden string
+	for _, k := range []string{"idno", "act", "for", "exp", "sig"} {
+		hidden += fmt.Sprintf("<input type=\"hidden\" name=\"%s\" value=\"%s\">\n", k, html.EscapeString(ctx.Get(k)))
+	}
+
+	ctx.W.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(ctx.W, "<html><head><title>argus</title></head><body>\n"+
+		"<p>notification %s: %s</p>\n<form method=\"post\" action=\"/api/notifylink\">\n%s"+
+		"<input type=\"submit\" value=\"%s\">\n</form></body></html>\n",
+		html.EscapeString(ctx.Get("idno")), html.EscapeString(obj), hidden, html.EscapeString(what))
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-24 15:10 (EDT)
+// Function:
+
+package notify
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/web"
+)
+
+func testNotify(idno int) *N {
+
+	now := clock.Unix()
+	return &N{cf: &Conf{Renotify: 60}, p: Persist{
+		IdNo:     idno,
+		Created:  now - 7200,
+		IsActive: true,
+		Unique:   "Top:web",
+		OvStatus: argus.CRITICAL,
+		CurrOv:   argus.CRITICAL,
+		Status:   map[string]string{"mail:ops@example.com": "sent"},
+		SendTo:   []SendDat{{Last: now - 3600, Dst: []string{"mail:ops@example.com"}}},
+		StepNo:   1,
+	}}
+}
+
+func TestAckExpire(t *testing.T) {
+
+	now := clock.Unix()
+	n := testNotify(9901)
+
+	lock.Lock()
+	defer lock.Unlock()
+	actives[n.p.IdNo] = n
+	defer delete(actives, n.p.IdNo)
+
+	n.lock.Lock()
+	n.ackFor("alice", 3600)
+	n.lock.Unlock()
+
+	if n.p.IsActive || n.p.AckExpires < now+3600 || actives[n.p.IdNo] != nil {
+		fmt.Printf("ack for: %v %d\n", n.p.IsActive, n.p.AckExpires)
+		t.Fail()
+	}
+
+	// not yet
+	n.maybeUnAck(now + 60)
+	if n.p.IsActive {
+		fmt.Printf("unacked early\n")
+		t.Fail()
+	}
+
+	// still down => unack
+	n.maybeUnAck(now + 3700)
+	if !n.p.IsActive || n.p.AckExpires != 0 || actives[n.p.IdNo] != n || n.p.Status["mail:ops@example.com"] != "unacked" {
+		fmt.Printf("not unacked: %v %d\n", n.p.IsActive, n.p.AckExpires)
+		t.Fail()
+	}
+
+	// up => stays acked
+	n.lock.Lock()
+	n.ackFor("alice", 3600)
+	n.lock.Unlock()
+	n.p.CurrOv = argus.CLEAR
+
+	n.maybeUnAck(now + 3700)
+	if n.p.IsActive || n.p.AckExpires != 0 {
+		fmt.Printf("unacked while up\n")
+		t.Fail()
+	}
+}
+
+func TestSnoozeExpire(t *testing.T) {
+
+	now := clock.Unix()
+	n := testNotify(9902)
+	last := n.p.SendTo[0].Last
+
+	lock.Lock()
+	defer lock.Unlock()
+	defer delete(dstQueue, "mail:ops@example.com")
+
+	n.lock.Lock()
+	n.snooze("alice", 600)
+	n.lock.Unlock()
+
+	if !n.p.IsActive || n.p.SnoozeUntil < now+600 {
+		fmt.Printf("snooze: %v %d\n", n.p.IsActive, n.p.SnoozeUntil)
+		t.Fail()
+	}
+
+	// snoozed => no renotify
+	n.maybeQueue()
+	if n.p.SendTo[0].Last != last {
+		fmt.Printf("renotified while snoozed\n")
+		t.Fail()
+	}
+
+	// expired => renotify
+	n.p.SnoozeUntil = now - 1
+	n.maybeQueue()
+	if n.p.SendTo[0].Last == last {
+		fmt.Printf("not renotified after snooze\n")
+		t.Fail()
+	}
+}
+
+func TestSignedLink(t *testing.T) {
+
+	defer func(u string) { globalDefaults.Web_Url = u }(globalDefaults.Web_Url)
+	globalDefaults.Web_Url = "https://argus.example.com"
+
+	n := testNotify(9903)
+	n.p.Created = clock.Unix()
+
+	lock.Lock()
+	byid[n.p.IdNo] = n
+	lock.Unlock()
+	defer func() {
+		lock.Lock()
+		delete(byid, n.p.IdNo)
+		delete(actives, n.p.IdNo)
+		lock.Unlock()
+	}()
+
+	link := n.signedLink("snooze", 600)
+	u, err := url.Parse(link)
+	if err != nil || u.Query().Get("sig") == "" {
+		fmt.Printf("link: %s\n", link)
+		t.FailNow()
+	}
+
+	send := func(method string, q url.Values) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/api/notifylink?"+q.Encode(), nil)
+		if method == "POST" {
+			r = httptest.NewRequest("POST", "/api/notifylink", strings.NewReader(q.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		r.ParseForm()
+		webLink(&web.Context{W: w, R: r})
+		return w
+	}
+	get := func(q url.Values) int {
+		return send("POST", q).Code
+	}
+
+	// tampered: a longer snooze, an ack, another notification, later expiration
+	for _, x := range []struct{ k, v string }{
+		{"for", "86400"},
+		{"act", "ack"},
+		{"idno", "9904"},
+		{"exp", fmt.Sprintf("%d", clock.Unix()+30*24*3600)},
+		{"sig", strings.ToUpper(u.Query().Get("sig"))},
+	} {
+		q := u.Query()
+		q.Set(x.k, x.v)
+		if code := get(q); code != 403 {
+			fmt.Printf("tampered %s: %d\n", x.k, code)
+			t.Fail()
+		}
+	}
+	if n.p.SnoozeUntil != 0 {
+		fmt.Printf("snoozed by a bad link\n")
+		t.Fail()
+	}
+
+	// fetching the link only asks
+	w := send("GET", u.Query())
+	if w.Code != 200 || n.p.SnoozeUntil != 0 || !strings.Contains(w.Body.String(), `method="post"`) ||
+		!strings.Contains(w.Body.String(), u.Query().Get("sig")) {
+		fmt.Printf("get link: %d %s\n", w.Code, w.Body.String())
+		t.Fail()
+	}
+
+	if code := get(u.Query()); code != 200 || n.p.SnoozeUntil == 0 {
+		fmt.Printf("valid link: %d\n", code)
+		t.Fail()
+	}
+
+	// expired
+	n.p.Created -= LINKEXPIRE + 10
+	if code := get(url.Values{}); code != 403 {
+		t.Fail()
+	}
+	old := n.signedLink("ack", 0)
+	u, _ = url.Parse(old)
+	if code := get(u.Query()); code != 403 {
+		fmt.Printf("expired link: %d\n", code)
+		t.Fail()
+	}
+}
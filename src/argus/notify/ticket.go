@@ -0,0 +1,321 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 21:10 (EDT)
+// Function: ticketing system integration
+
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/configure"
+)
+
+// ticket NAME {
+//     create_url:  https://tickets.example.com/api/issue
+//     create_body: {"project": "{{.ADDR}}", "summary": {{json .SUBJECT}}, "text": {{json .CONTENT}}}
+//     create_id:   result.key
+//     update_url:  https://tickets.example.com/api/issue/{{.TICKET}}/comment
+//     ...
+// }
+// notify: NAME:project
+
+type Ticket struct {
+	Create_Url    string
+	Create_Method string
+	Create_Body   string
+	Create_Id     string // where in the response is the ticket id
+	Update_Url    string
+	Update_Method string
+	Update_Body   string
+	Close_Url     string
+	Close_Method  string
+	Close_Body    string
+	Content_Type  string
+	Header        string // Name: value; Name: value
+}
+
+type TicketDat struct {
+	Id      string
+	Created int64
+	Updated int64
+	Closed  int64
+}
+
+func NewTicket(conf *configure.CF) error {
+
+	t := &Ticket{
+		Create_Method: "POST",
+		Update_Method: "POST",
+		Close_Method:  "POST",
+		Create_Id:     "id",
+		Content_Type:  "application/json",
+	}
+	conf.InitFromConfig(t, "ticket", "")
+
+	if t.Create_Url == "" {
+		return fmt.Errorf("Invalid Ticket - create_url not specified")
+	}
+
+	if methods[conf.Name] != nil {
+		return fmt.Errorf("Duplicate Method '%s'", conf.Name)
+	}
+
+	conf.CheckTypos()
+	// qtime 0 => one ticket per notification
+	methods[conf.Name] = &Method{ticket: t}
+	return nil
+}
+
+// ################################################################
+
+// called with package lock held
+// create, update, or close the ticket
+func (t *Ticket) transmit(dst string, addr string, notes []*N) {
+
+	for _, n := range notes {
+		if n.p.OvStatus == argus.CLEAR {
+			t.close(dst, addr, n)
+		} else {
+			t.createOrUpdate(dst, addr, n)
+		}
+	}
+}
+
+func (t *Ticket) createOrUpdate(dst string, addr string, n *N) {
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	now := clock.Unix()
+	tk := n.p.Tickets[dst]
+
+	if tk == nil || tk.Closed != 0 {
+		dat := n.ticketParams("create", addr, "")
+		body, err := t.request(n, t.Create_Method, t.Create_Url, t.Create_Body, dat)
+		if err != nil {
+			dl.Problem("ticket create failed: %v", err)
+			n.log(dst, "ticket create failed")
+			return
+		}
+		id, err := jsonExtract(body, t.Create_Id)
+		if err != nil {
+			dl.Problem("ticket create: %v", err)
+			n.log(dst, "ticket create failed")
+			return
+		}
+
+		if n.p.Tickets == nil {
+			n.p.Tickets = make(map[string]*TicketDat)
+		}
+		n.p.Tickets[dst] = &TicketDat{Id: id, Created: now}
+		n.log(dst, "ticket "+id+" created")
+		n.Save()
+		return
+	}
+
+	if t.Update_Url == "" {
+		return
+	}
+
+	dat := n.ticketParams("update", addr, tk.Id)
+	_, err := t.request(n, t.Update_Method, t.Update_Url, t.Update_Body, dat)
+	if err != nil {
+		dl.Problem("ticket update failed: %v", err)
+		n.log(dst, "ticket "+tk.Id+" update failed")
+		return
+	}
+
+	tk.Updated = now
+	n.log(dst, "ticket "+tk.Id+" updated")
+	n.Save()
+}
+
+// the object is up - close the ticket opened by the down notification
+func (t *Ticket) close(dst string, addr string, up *N) {
+
+	down := findOpenTicket(up.p.Unique, dst, up.p.IdNo)
+	if down == nil {
+		dl.Debug("no open ticket for %s on %s", up.p.Unique, dst)
+		return
+	}
+
+	down.lock.Lock()
+	tk := down.p.Tickets[dst]
+
+	// use the up notification for the message, the down for the ticket
+	var err error
+	if t.Close_Url != "" {
+		up.lock.RLock()
+		dat := up.ticketParams("close", addr, tk.Id)
+		_, err = t.request(up, t.Close_Method, t.Close_Url, t.Close_Body, dat)
+		up.lock.RUnlock()
+	}
+
+	if err != nil {
+		dl.Problem("ticket close failed: %v", err)
+		down.log(dst, "ticket "+tk.Id+" close failed")
+		down.lock.Unlock()
+		return
+	}
+
+	tk.Closed = clock.Unix()
+	down.log(dst, "ticket "+tk.Id+" closed")
+	down.Save()
+	down.lock.Unlock()
+
+	up.lock.Lock()
+	up.log(dst, "closed ticket "+tk.Id)
+	up.lock.Unlock()
+}
+
+// package lock is already held
+// the most recent notification for the object with an open ticket on dst
+func findOpenTicket(unique string, dst string, before int) *N {
+
+	var found *N
+
+	for id, n := range byid {
+		if id >= before {
+			continue
+		}
+		if found != nil && found.p.IdNo > id {
+			continue
+		}
+		n.lock.RLock()
+		tk := n.p.Tickets[dst]
+		if n.p.Unique == unique && tk != nil && tk.Closed == 0 {
+			found = n
+		}
+		n.lock.RUnlock()
+	}
+
+	return found
+}
+
+// package lock is already held
+// on escalation, also comment on the existing tickets
+func (n *N) ticketsEscalate(dst []string) {
+
+	for d, tk := range n.p.Tickets {
+		if tk.Closed == 0 && !contains(dst, d) {
+			addToQueue(n, []string{d})
+		}
+	}
+}
+
+// ################################################################
+
+// notify lock is already held
+func (n *N) ticketParams(act string, addr string, id string) map[string]interface{} {
+
+	subj := "Argus"
+	if n.p.OvStatus != argus.CLEAR {
+		subj = "Argus - DOWN"
+	}
+	esc := ""
+	if n.p.Escalated {
+		esc = " (Escalated)"
+		subj = subj + esc
+	}
+
+	return map[string]interface{}{
+		"ADDR":      addr,
+		"SUBJECT":   subj + " - " + n.p.Message,
+		"ACTION":    act,
+		"TICKET":    id,
+		"ESCALATED": esc,
+	}
+}
+
+// notify lock is already held
+func (t *Ticket) request(n *N, method string, urlt string, bodyt string, dat map[string]interface{}) ([]byte, error) {
+
+	url := n.expand(urlt, n.p.MessageFmted, dat)
+	body := n.expand(bodyt, n.p.MessageFmted, dat)
+
+	dl.Debug("ticket %s %s: %s", method, url, body)
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if t.Content_Type != "" {
+		req.Header.Set("Content-Type", t.Content_Type)
+	}
+	for _, h := range strings.Split(t.Header, ";") {
+		kv := strings.SplitN(h, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		req.Header.Set(strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1]))
+	}
+
+	client := &http.Client{Timeout: TIMEOUT}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	rbody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return rbody, fmt.Errorf("%s %s: %s", method, url, res.Status)
+	}
+
+	return rbody, nil
+}
+
+// jsonExtract finds a value by dotted path: "key", "result.id", "items.0.id"
+func jsonExtract(body []byte, path string) (string, error) {
+
+	var d interface{}
+
+	err := json.Unmarshal(body, &d)
+	if err != nil {
+		return "", fmt.Errorf("invalid json response: %v", err)
+	}
+
+	for _, p := range strings.Split(path, ".") {
+		switch v := d.(type) {
+		case map[string]interface{}:
+			d = v[p]
+		case []interface{}:
+			i, err := strconv.Atoi(p)
+			if err != nil || i < 0 || i >= len(v) {
+				return "", fmt.Errorf("cannot find '%s' in response", path)
+			}
+			d = v[i]
+		default:
+			return "", fmt.Errorf("cannot find '%s' in response", path)
+		}
+	}
+
+	switch v := d.(type) {
+	case string:
+		if v != "" {
+			return v, nil
+		}
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+
+	return "", fmt.Errorf("cannot find '%s' in response", path)
+}
+
+func jsonQuote(s string) string {
+
+	js, _ := json.Marshal(s)
+	return string(js)
+}
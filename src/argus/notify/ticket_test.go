@@ -0,0 +1,104 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 21:52 (EDT)
+// Function:
+
+package notify
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"argus.domain/argus/argus"
+)
+
+func TestJsonExtract(t *testing.T) {
+
+	body := []byte(`{"id": 123, "result": {"key": "OPS-7"}, "items": [{"id": "a"}, {"id": "b"}]}`)
+
+	for _, x := range []struct{ path, exp string }{
+		{"id", "123"},
+		{"result.key", "OPS-7"},
+		{"items.1.id", "b"},
+		{"items.2.id", ""},
+		{"nope", ""},
+	} {
+		got, _ := jsonExtract(body, x.path)
+		if got != x.exp {
+			fmt.Printf("extract %s -> %s != %s\n", x.path, got, x.exp)
+			t.Fail()
+		}
+	}
+}
+
+func TestTicket(t *testing.T) {
+
+	var reqs []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		reqs = append(reqs, r.Method+" "+r.URL.Path+" "+string(body))
+		if r.URL.Path == "/issue" {
+			w.Write([]byte(`{"result": {"key": "OPS-7"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	tk := &Ticket{
+		Create_Method: "POST",
+		Create_Url:    srv.URL + "/issue",
+		Create_Body:   `{"project": "{{.ADDR}}", "summary": {{json .SUBJECT}}}`,
+		Create_Id:     "result.key",
+		Update_Method: "POST",
+		Update_Url:    srv.URL + "/issue/{{.TICKET}}/comment",
+		Update_Body:   `{{.ACTION}}`,
+		Close_Method:  "PUT",
+		Close_Url:     srv.URL + "/issue/{{.TICKET}}/close",
+		Close_Body:    `{{.ACTION}}`,
+	}
+
+	down := &N{cf: &Conf{}, p: Persist{IdNo: 1, Unique: "Top:web", Message: `web is "DOWN"`, OvStatus: argus.CRITICAL}}
+	up := &N{cf: &Conf{}, p: Persist{IdNo: 2, Unique: "Top:web", Message: "web is UP", OvStatus: argus.CLEAR}}
+
+	lock.Lock()
+	byid[1] = down
+	byid[2] = up
+	lock.Unlock()
+	defer func() {
+		lock.Lock()
+		delete(byid, 1)
+		delete(byid, 2)
+		lock.Unlock()
+	}()
+
+	lock.Lock()
+	tk.transmit("tix:OPS", "OPS", []*N{down}) // create
+	tk.transmit("tix:OPS", "OPS", []*N{down}) // renotify
+	tk.transmit("tix:OPS", "OPS", []*N{up})   // close
+	lock.Unlock()
+
+	exp := []string{
+		`POST /issue {"project": "OPS", "summary": "Argus - DOWN - web is \"DOWN\""}`,
+		`POST /issue/OPS-7/comment update`,
+		`PUT /issue/OPS-7/close close`,
+	}
+
+	if len(reqs) != len(exp) {
+		fmt.Printf("requests: %#v\n", reqs)
+		t.FailNow()
+	}
+	for i := range exp {
+		if reqs[i] != exp[i] {
+			fmt.Printf("request %s != %s\n", reqs[i], exp[i])
+			t.Fail()
+		}
+	}
+
+	if tk := down.p.Tickets["tix:OPS"]; tk == nil || tk.Id != "OPS-7" || tk.Closed == 0 {
+		fmt.Printf("ticket %+v\n", down.p.Tickets)
+		t.Fail()
+	}
+}
@@ -20,6 +20,7 @@ func init() {
 	api.Add(true, "darp_list", apiDarpList)
 	api.Add(true, "update", apiSetResultFor)
 	api.Add(true, "hwab_reset", apiHwabReset)
+	api.Add(true, "detector_reset", apiHwabReset)
 }
 
 // so slave can configure itself
@@ -106,12 +107,12 @@ func apiHwabReset(ctx *api.Context) {
 		return
 	}
 
-	if obj.p.Hwab == nil {
-		ctx.SendResponseFinal(404, "HWAB not enabled")
+	if obj.detector == nil {
+		ctx.SendResponseFinal(404, "anomaly detection not enabled")
 		return
 	}
 
-	obj.p.Hwab.Reset()
+	obj.detector.Reset()
 	ctx.SendOKFinal()
 }
 
@@ -125,6 +126,7 @@ func (s *Service) Dump(dx argus.Dumper) {
 	dx.Dump("service/Tries", fmt.Sprintf("%d", s.Tries))
 	argus.Dump(dx, "service", &s.p)
 	argus.Dump(dx, "service/CF", &s.Cf)
+	s.dumpHyst(dx)
 
 	cm := s.check.DumpInfo()
 	for pre, d := range cm {
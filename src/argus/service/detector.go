@@ -0,0 +1,275 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 12:30 (EDT)
+// Function: pluggable anomaly detectors
+
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"argus.domain/argus/configure"
+)
+
+// an anomaly detector learns what a value should be
+type Detector interface {
+	Name() string
+	Init(*Service) error // after persisted data is loaded
+	Add(float64)
+	Deviation(float64) (float64, bool) // how far outside the predicted band
+	Band() (float64, float64)          // predicted value, width - for graphs
+	Reset()
+}
+
+type EwmaConf struct {
+	Alpha     float64
+	Min_Count int // learn this many samples first
+}
+
+// exponentially weighted mean + variance => z-score
+type EWMA struct {
+	cf    EwmaConf
+	lock  sync.RWMutex
+	Mean  float64
+	Var   float64
+	Count int
+	PMean float64 // prediction, before the most recent value
+	PVar  float64
+}
+
+type MadConf struct {
+	Window    int // number of samples
+	Min_Count int
+}
+
+// median + median absolute deviation - robust against outliers
+type MAD struct {
+	cf     MadConf
+	lock   sync.RWMutex
+	Window []float64
+	Center float64
+	Width  float64
+}
+
+var ewmadefaults = EwmaConf{
+	Alpha:     0.05,
+	Min_Count: 30,
+}
+
+var maddefaults = MadConf{
+	Window:    60,
+	Min_Count: 10,
+}
+
+// NB - 1.4826 * MAD estimates the std dev of a normal distribution
+const MADSCALE = 1.4826
+
+func (s *Service) DetectorConfig(conf *configure.CF) error {
+
+	switch s.Cf.Detector {
+	case "", "hwab":
+		s.HwabConfig(conf)
+		s.detector = s.p.Hwab
+	case "ewma":
+		e := &EWMA{cf: ewmadefaults}
+		conf.InitFromConfig(&e.cf, "service", "ewma_")
+		if e.cf.Alpha <= 0 || e.cf.Alpha > 1 {
+			return fmt.Errorf("invalid ewma_alpha %f", e.cf.Alpha)
+		}
+		s.p.Ewma = e
+		s.detector = e
+	case "mad", "median":
+		d := &MAD{cf: maddefaults}
+		conf.InitFromConfig(&d.cf, "service", "mad_")
+		if d.cf.Window < 3 {
+			d.cf.Window = 3
+		}
+		s.p.Mad = d
+		s.detector = d
+	case "percentile", "seasonal":
+		p := &SeasonPctl{cf: pctldefaults}
+		conf.InitFromConfig(&p.cf, "service", "pctl_")
+		err := p.make()
+		if err != nil {
+			return err
+		}
+		s.p.Pctl = p
+		s.detector = p
+	default:
+		return fmt.Errorf("unknown detector '%s'", s.Cf.Detector)
+	}
+
+	return nil
+}
+
+// ################################################################
+
+func (h *HWAB) Name() string {
+	return "hwab"
+}
+
+func (h *HWAB) Band() (float64, float64) {
+
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return float64(h.yn), float64(h.dn)
+}
+
+// ################################################################
+
+func (e *EWMA) Name() string {
+	return "ewma"
+}
+
+func (e *EWMA) Init(s *Service) error {
+	return nil
+}
+
+func (e *EWMA) Add(val float64) {
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.PMean = e.Mean
+	e.PVar = e.Var
+
+	if e.Count == 0 {
+		e.Mean = val
+		e.Var = 0
+	} else {
+		d := val - e.Mean
+		incr := e.cf.Alpha * d
+		e.Mean += incr
+		e.Var = (1 - e.cf.Alpha) * (e.Var + d*incr)
+	}
+	e.Count++
+}
+
+func (e *EWMA) Deviation(val float64) (float64, bool) {
+
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	if e.Count <= e.cf.Min_Count || e.PVar <= 0 {
+		return 0, false
+	}
+
+	return math.Abs(val-e.PMean) / math.Sqrt(e.PVar), true
+}
+
+func (e *EWMA) Band() (float64, float64) {
+
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.Mean, math.Sqrt(e.Var)
+}
+
+func (e *EWMA) Reset() {
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.Mean = 0
+	e.Var = 0
+	e.PMean = 0
+	e.PVar = 0
+	e.Count = 0
+}
+
+// ################################################################
+
+func (d *MAD) Name() string {
+	return "mad"
+}
+
+func (d *MAD) Init(s *Service) error {
+
+	if len(d.Window) > d.cf.Window {
+		d.Window = d.Window[len(d.Window)-d.cf.Window:]
+	}
+	return nil
+}
+
+// predict from the previous values, then add
+func (d *MAD) Add(val float64) {
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if len(d.Window) >= d.cf.Min_Count {
+		d.Center, d.Width = medianMAD(d.Window)
+	}
+
+	d.Window = append(d.Window, val)
+	if len(d.Window) > d.cf.Window {
+		d.Window = append([]float64(nil), d.Window[len(d.Window)-d.cf.Window:]...)
+	}
+}
+
+func (d *MAD) Deviation(val float64) (float64, bool) {
+
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	if d.Width == 0 {
+		return 0, false
+	}
+
+	return math.Abs(val-d.Center) / d.Width, true
+}
+
+func (d *MAD) Band() (float64, float64) {
+
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.Center, d.Width
+}
+
+func (d *MAD) Reset() {
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.Window = nil
+	d.Center = 0
+	d.Width = 0
+}
+
+func medianMAD(vals []float64) (float64, float64) {
+
+	med := median(vals)
+
+	dev := make([]float64, len(vals))
+	for i, v := range vals {
+		dev[i] = math.Abs(v - med)
+	}
+
+	return med, MADSCALE * median(dev)
+}
+
+func median(vals []float64) float64 {
+	return percentile(vals, 50)
+}
+
+// linear interpolation between closest ranks
+func percentile(vals []float64, pct float64) float64 {
+
+	if len(vals) == 0 {
+		return 0
+	}
+
+	v := append([]float64(nil), vals...)
+	sort.Float64s(v)
+
+	r := pct / 100 * float64(len(v)-1)
+	lo := int(math.Floor(r))
+	hi := int(math.Ceil(r))
+	if lo == hi {
+		return v[lo]
+	}
+
+	return v[lo] + (v[hi]-v[lo])*(r-float64(lo))
+}
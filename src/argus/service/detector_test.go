@@ -0,0 +1,102 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 13:50 (EDT)
+// Function:
+
+package service
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func devTest(t *testing.T, d Detector, val float64, lo, hi float64) {
+
+	dev, ok := d.Deviation(val)
+	if !ok || dev < lo || dev > hi {
+		fmt.Printf("%s: %f -> %f, %v; expected %f - %f\n", d.Name(), val, dev, ok, lo, hi)
+		t.Fail()
+	}
+}
+
+func TestPercentile(t *testing.T) {
+
+	v := []float64{5, 1, 4, 2, 3}
+
+	if median(v) != 3 || percentile(v, 0) != 1 || percentile(v, 100) != 5 || percentile(v, 25) != 2 {
+		fmt.Printf("percentile %v\n", v)
+		t.Fail()
+	}
+	if m, d := medianMAD([]float64{1, 2, 3, 4, 100}); m != 3 || math.Abs(d-MADSCALE) > 0.0001 {
+		fmt.Printf("mad %f %f\n", m, d)
+		t.Fail()
+	}
+}
+
+func TestEWMA(t *testing.T) {
+
+	e := &EWMA{cf: ewmadefaults}
+
+	for i := 0; i < 200; i++ {
+		v := 100.0 + float64(i%5) - 2
+		e.Add(v)
+	}
+
+	devTest(t, e, 101, 0, 1)
+	devTest(t, e, 150, 10, 1000)
+
+	e.Reset()
+	if _, ok := e.Deviation(150); ok {
+		fmt.Printf("ewma reset\n")
+		t.Fail()
+	}
+}
+
+func TestMAD(t *testing.T) {
+
+	d := &MAD{cf: maddefaults}
+
+	for i := 0; i < 100; i++ {
+		v := 50.0 + float64(i%3)
+		if i%10 == 0 {
+			v = 1000 // outliers do not move it
+		}
+		d.Add(v)
+	}
+
+	devTest(t, d, 51, 0, 1)
+	devTest(t, d, 70, 10, 1000)
+}
+
+func TestSeasonPctl(t *testing.T) {
+
+	p := &SeasonPctl{cf: pctldefaults}
+	p.cf.Seasons = "1h"
+	if err := p.make(); err != nil {
+		fmt.Printf("%v\n", err)
+		t.FailNow()
+	}
+
+	// busy on the half hour, 3 cycles
+	start := int64(1800000000)
+	start -= start % 3600
+
+	for tm := start; tm < start+3*3600; tm += 60 {
+		v := 10.0
+		if (tm/60)%60 >= 30 {
+			v = 100
+		}
+		p.AddAt(v+float64(tm%7), tm)
+	}
+
+	// quiet half
+	p.AddAt(10, start+3*3600+600)
+	devTest(t, p, 12, 0, 3)
+	devTest(t, p, 100, 5, 1000)
+
+	// busy half
+	p.AddAt(100, start+3*3600+2400)
+	devTest(t, p, 102, 0, 3)
+	devTest(t, p, 10, 5, 1000)
+}
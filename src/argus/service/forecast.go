@@ -0,0 +1,244 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 14:40 (EDT)
+// Function: capacity forecasting - when will the value cross the threshold?
+
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/graph"
+)
+
+const (
+	FORECASTFREQ = 3600 // refit hourly
+	FORECASTMIN  = 6    // need at least this many summaries
+)
+
+// fitted trend line: value = Base + Slope * (t - Time)
+type forecast struct {
+	Lastrun int64
+	Time    int64
+	Base    float64
+	Slope   float64 // per second
+	Count   int
+}
+
+func (s *Service) forecastConfig() error {
+
+	if s.Cf.Forecast_Horizon == 0 {
+		return nil
+	}
+
+	switch s.Cf.Forecast_Fit {
+	case "linear", "robust":
+	default:
+		return fmt.Errorf("invalid forecast_fit '%s'", s.Cf.Forecast_Fit)
+	}
+
+	switch s.Cf.Forecast_Data {
+	case "hours", "days":
+	default:
+		return fmt.Errorf("invalid forecast_data '%s'", s.Cf.Forecast_Data)
+	}
+
+	return nil
+}
+
+// refit from the graph data, if it is time
+func (s *Service) forecastUpdate() {
+
+	if s.Cf.Forecast_Horizon == 0 || !s.mon.Cf.Graph || !graphIsLocal {
+		return
+	}
+
+	now := clock.Unix()
+	if s.p.Forecast.Lastrun+FORECASTFREQ > now {
+		return
+	}
+
+	ts, vs := graph.Trend(s.mon.Pathname("", ""), s.Cf.Forecast_Data, now-s.Cf.Forecast_Window)
+
+	var f forecast
+	f.Lastrun = now
+	f.Count = len(ts)
+
+	if len(ts) >= FORECASTMIN {
+		var slope, base float64
+		if s.Cf.Forecast_Fit == "robust" {
+			slope, base = fitTheilSen(ts, vs, now)
+		} else {
+			slope, base = fitLinear(ts, vs, now)
+		}
+		f.Time = now
+		f.Slope = slope
+		f.Base = base
+		s.Debug("forecast: %d points, slope %g/day, value %g", f.Count, slope*86400, base)
+	}
+
+	s.mon.Lock.Lock()
+	s.p.Forecast = f
+	s.mon.Lock.Unlock()
+}
+
+// when will the trend cross lim? 0 if never, or not enough data
+func (f *forecast) crossing(lim float64, above bool, now int64) int64 {
+
+	if f.Time == 0 || f.Slope == 0 || math.IsNaN(lim) {
+		return 0
+	}
+	if above != (f.Slope > 0) {
+		// heading the other way
+		return 0
+	}
+
+	t := f.Time + int64((lim-f.Base)/f.Slope)
+	if t < now {
+		t = now
+	}
+	return t
+}
+
+// will the value cross the threshold within the horizon?
+func (s *Service) forecastSev(sev argus.Status) string {
+
+	if s.Cf.Forecast_Horizon == 0 {
+		return ""
+	}
+
+	now := clock.Unix()
+	f := &s.p.Forecast
+
+	if t := f.crossing(s.Cf.Maxvalue[sev], true, now); t != 0 && t-now <= s.Cf.Forecast_Horizon {
+		return "TEST forecast to exceed max " + forecastWhen(t, now)
+	}
+	if t := f.crossing(s.Cf.Minvalue[sev], false, now); t != 0 && t-now <= s.Cf.Forecast_Horizon {
+		return "TEST forecast to drop below min " + forecastWhen(t, now)
+	}
+
+	return ""
+}
+
+func forecastWhen(t int64, now int64) string {
+
+	if t <= now {
+		return "now"
+	}
+	return fmt.Sprintf("in %s (at %s)", argus.Elapsed(t-now), time.Unix(t, 0).Format("2006-Jan-02 15:04"))
+}
+
+// for the notification message
+func (s *Service) NotifyDetail(st argus.Status) string {
+
+	if st == argus.CLEAR || s.Cf.Forecast_Horizon == 0 {
+		return ""
+	}
+	// lock is already held
+	if strings.HasPrefix(s.mon.P.Reason, "TEST forecast") {
+		return strings.TrimPrefix(s.mon.P.Reason, "TEST ")
+	}
+	return ""
+}
+
+type forecastCross struct {
+	Sev   argus.Status
+	What  string
+	Limit float64
+	Time  int64
+}
+
+// for the web page
+func (s *Service) forecastJson(md map[string]interface{}) {
+
+	if s.Cf.Forecast_Horizon == 0 || s.p.Forecast.Time == 0 {
+		return
+	}
+
+	now := clock.Unix()
+	f := &s.p.Forecast
+	var cross []forecastCross
+
+	for sev := argus.CRITICAL; sev >= argus.UNKNOWN; sev-- {
+		rsev := sev
+		if sev == argus.CLEAR {
+			continue
+		}
+		if sev == argus.UNKNOWN {
+			rsev = s.Cf.Severity
+		}
+
+		if t := f.crossing(s.Cf.Maxvalue[sev], true, now); t != 0 {
+			cross = append(cross, forecastCross{rsev, "max", s.Cf.Maxvalue[sev], t})
+		}
+		if t := f.crossing(s.Cf.Minvalue[sev], false, now); t != 0 {
+			cross = append(cross, forecastCross{rsev, "min", s.Cf.Minvalue[sev], t})
+		}
+	}
+
+	md["forecast"] = map[string]interface{}{
+		"slope":  f.Slope * 86400, // per day
+		"value":  f.Base,
+		"fitted": f.Time,
+		"points": f.Count,
+		"cross":  cross,
+	}
+}
+
+// ################################################################
+
+// least squares. returns slope, value at time now
+func fitLinear(ts []int64, vs []float64, now int64) (float64, float64) {
+
+	n := float64(len(ts))
+	var sx, sy, sxx, sxy float64
+
+	for i, t := range ts {
+		x := float64(t - now)
+		sx += x
+		sy += vs[i]
+		sxx += x * x
+		sxy += x * vs[i]
+	}
+
+	d := n*sxx - sx*sx
+	if d == 0 {
+		return 0, sy / n
+	}
+
+	slope := (n*sxy - sx*sy) / d
+	return slope, (sy - slope*sx) / n
+}
+
+// Theil-Sen - median of the pairwise slopes. robust against outliers
+func fitTheilSen(ts []int64, vs []float64, now int64) (float64, float64) {
+
+	var slopes []float64
+
+	for i := 0; i < len(ts); i++ {
+		for j := i + 1; j < len(ts); j++ {
+			if ts[j] == ts[i] {
+				continue
+			}
+			slopes = append(slopes, (vs[j]-vs[i])/float64(ts[j]-ts[i]))
+		}
+	}
+
+	if len(slopes) == 0 {
+		return 0, median(vs)
+	}
+
+	slope := median(slopes)
+
+	icpt := make([]float64, len(ts))
+	for i, t := range ts {
+		icpt[i] = vs[i] - slope*float64(t-now)
+	}
+
+	return slope, median(icpt)
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 15:25 (EDT)
+// Function:
+
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/monel"
+)
+
+func TestForecastFit(t *testing.T) {
+
+	// 1 per hour, growing
+	now := int64(1800000000)
+	var ts []int64
+	var vs []float64
+
+	for i := 0; i < 48; i++ {
+		ts = append(ts, now-int64(48-i)*3600)
+		vs = append(vs, 50+float64(i))
+	}
+
+	slope, base := fitLinear(ts, vs, now)
+	if math.Abs(slope*3600-1) > 0.001 || math.Abs(base-98) > 0.01 {
+		fmt.Printf("linear: slope %f, base %f\n", slope*3600, base)
+		t.Fail()
+	}
+
+	// an outlier throws off least squares, but not theil-sen
+	vs[40] = 1000
+
+	slope, base = fitTheilSen(ts, vs, now)
+	if math.Abs(slope*3600-1) > 0.001 || math.Abs(base-98) > 0.01 {
+		fmt.Printf("robust: slope %f, base %f\n", slope*3600, base)
+		t.Fail()
+	}
+}
+
+func TestForecastSev(t *testing.T) {
+
+	now := clock.Unix()
+	s := &Service{mon: &monel.M{}, Cf: defaults}
+	s.Cf.Forecast_Horizon = 7 * 86400
+	s.Cf.Maxvalue[argus.WARNING] = 80
+	s.Cf.Minvalue[argus.CRITICAL] = 10
+
+	// 60, up 5 per day => crosses 80 in 4 days
+	s.p.Forecast = forecast{Lastrun: now, Time: now, Base: 60, Slope: 5.0 / 86400}
+
+	if why := s.forecastSev(argus.WARNING); !strings.HasPrefix(why, "TEST forecast to exceed max in 4d") {
+		fmt.Printf("warning: %s\n", why)
+		t.Fail()
+	}
+	if why := s.forecastSev(argus.CRITICAL); why != "" {
+		fmt.Printf("critical: %s\n", why)
+		t.Fail()
+	}
+
+	// too far off
+	s.p.Forecast.Slope = 1.0 / 86400
+	if why := s.forecastSev(argus.WARNING); why != "" {
+		fmt.Printf("slow: %s\n", why)
+		t.Fail()
+	}
+
+	// heading down
+	s.p.Forecast.Slope = -10.0 / 86400
+	if why := s.forecastSev(argus.CRITICAL); !strings.HasPrefix(why, "TEST forecast to drop below min in 5d") {
+		fmt.Printf("down: %s\n", why)
+		t.Fail()
+	}
+}
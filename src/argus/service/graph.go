@@ -8,6 +8,8 @@ package service
 import (
 	"expvar"
 	"fmt"
+	"path"
+	"strings"
 
 	"argus.domain/argus/api"
 	"argus.domain/argus/argus"
@@ -15,6 +17,7 @@ import (
 	"argus.domain/argus/configure"
 	"argus.domain/argus/darp"
 	"argus.domain/argus/graph"
+	"argus.domain/argus/monel"
 )
 
 const (
@@ -29,6 +32,7 @@ var darpGraphQueueDrop = expvar.NewInt("darpgraphdrops")
 
 func init() {
 	api.Add(true, "graphdata", apiAddGraphData)
+	api.Add(true, "graphresize", apiGraphResize)
 }
 
 func GraphConfig(cf *configure.CF) {
@@ -38,23 +42,50 @@ func GraphConfig(cf *configure.CF) {
 	// start worker
 
 	if !graphIsLocal {
+		graph.SetLocal(darpGrapher{})
 		go darpGraphWorker()
 	}
 }
 
+// retention + resolution
+func (s *Service) graphConfig() error {
+
+	if s.Cf.Graph_Resolution < 1 {
+		return fmt.Errorf("invalid graph_resolution")
+	}
+	if !graph.ValidConsol(s.Cf.Graph_Consolidate) {
+		return fmt.Errorf("invalid graph_consolidate '%s'", s.Cf.Graph_Consolidate)
+	}
+
+	// 0 => the default size
+	s.graphLay = graph.Layout{
+		Samples:    int(s.Cf.Graph_Keep_Samples / s.Cf.Graph_Resolution),
+		Hours:      int(s.Cf.Graph_Keep_Hours / 3600),
+		Days:       int(s.Cf.Graph_Keep_Days / 86400),
+		Resolution: int(s.Cf.Graph_Resolution),
+		Consol:     s.Cf.Graph_Consolidate,
+	}
+
+	return nil
+}
+
+func (s *Service) GraphLayout() *graph.Layout {
+	return &s.graphLay
+}
+
 func (s *Service) recordMyGraphData(val float64) {
 
 	now := clock.Unix()
-	if s.p.Lastgraph+graphMinTime > now {
+	// allow for a bit of scheduling jitter
+	if s.p.Lastgraph+s.Cf.Graph_Resolution-s.Cf.Graph_Resolution/10 > now {
 		return
 	}
 	s.p.Lastgraph = now
 
 	var yn, dn float64
 
-	if s.p.Hwab != nil {
-		yn = float64(s.p.Hwab.yn)
-		dn = float64(s.p.Hwab.dn)
+	if s.detector != nil {
+		yn, dn = s.detector.Band()
 	}
 
 	if s.Cf.Gr_what == "elapsed" {
@@ -62,22 +93,40 @@ func (s *Service) recordMyGraphData(val float64) {
 	}
 
 	if s.mon.Cf.Graph {
-		// darpid = "" for backwards compat
 		s.Debug("graph")
 
-		if graphIsLocal {
-			graph.Add(s.mon.Pathname("", ""), now, s.mon.P.OvStatus, val, yn, dn)
-		} else {
-			darpGraphAdd(s.mon.Pathname(darp.MyId+":", ""), now, s.mon.P.OvStatus, val, yn, dn)
-		}
+		// external graphers get data directly from each server
+		graph.Add(&graph.Sample{
+			Object: s.mon.Cf.Unique,
+			Path:   s.mon.Pathname("", ""), // darpid = "" for backwards compat
+			Tags:   s.mon.Cf.Tags,
+			Darp:   darp.MyId,
+			When:   now,
+			Status: s.mon.P.OvStatus,
+			Value:  val,
+			Yn:     yn,
+			Dn:     dn,
+			Layout: &s.graphLay,
+		})
 	}
 }
 
 // ################################################################
 
-func darpGraphAdd(file string, when int64, status argus.Status, val, yn, dn float64) {
+// in place of graphd - the darp master keeps the files
+type darpGrapher struct{}
+
+func (darpGrapher) Add(s *graph.Sample) {
+
+	dir, file := path.Split(s.Path)
+	darpGraphAdd(dir+s.Darp+":"+file, s)
+}
 
-	l := fmt.Sprintf("%s %d %d %f %f %f", file, when, status, val, yn, dn)
+func darpGraphAdd(file string, s *graph.Sample) {
+
+	lay := s.Layout
+	l := fmt.Sprintf("%s %d %d %f %f %f %d %d %d %d %s", file, s.When, s.Status, s.Value, s.Yn, s.Dn,
+		lay.Samples, lay.Hours, lay.Days, lay.Resolution, lay.Consol)
 
 	darpGraphQueueLen.Set(int64(len(darpGraphChan)))
 
@@ -138,14 +187,81 @@ func apiAddGraphData(ctx *api.Context) {
 		var when int64
 		var status int
 		var val, yn, dn float64
-		fmt.Sscan(l, &file, &when, &status, &val, &yn, &dn)
+		var lay graph.Layout
+		// older versions do not send the layout
+		fmt.Sscan(l, &file, &when, &status, &val, &yn, &dn, &lay.Samples, &lay.Hours, &lay.Days, &lay.Resolution, &lay.Consol)
+		if lay.Consol == "" {
+			lay.Consol = "average"
+		}
 
-		graph.Add(file, when, argus.Status(status), val, yn, dn)
+		// the sending server has already sent it to the external graphers
+		graph.AddLocal(&graph.Sample{
+			Path:   file,
+			When:   when,
+			Status: argus.Status(status),
+			Value:  val,
+			Yn:     yn,
+			Dn:     dn,
+			Layout: &lay,
+		})
+		n++
 	}
 
 	ctx.SendOKFinal()
 }
 
+// resize existing graph files to match the current config
+// argusctl graphresize obj=Top:Foo
+func apiGraphResize(ctx *api.Context) {
+
+	uid := ctx.Args["obj"]
+	if uid == "" || Find(uid) == nil && monel.Find(uid) == nil {
+		ctx.Send404()
+		return
+	}
+	if !graphIsLocal {
+		ctx.SendResponseFinal(400, "graphs are kept on the darp master")
+		return
+	}
+
+	var todo []*Service
+
+	lock.RLock()
+	for id, s := range allService {
+		if (id == uid || strings.HasPrefix(id, uid+":")) && s.mon.Cf.Graph {
+			todo = append(todo, s)
+		}
+	}
+	lock.RUnlock()
+
+	ctx.SendOK()
+
+	for _, s := range todo {
+		// the darp master has one file per server
+		files := []string{s.mon.Pathname("", "")}
+		for id := range darp.GetStatuses() {
+			if id != "" {
+				files = append(files, s.mon.Pathname(id+":", ""))
+			}
+		}
+
+		for _, file := range files {
+			did, err := graph.Resize(file, &s.graphLay)
+			switch {
+			case err != nil:
+				dl.Problem("resize %s: %v", file, err)
+				ctx.SendKVP(file, "error: "+err.Error())
+			case did:
+				ctx.SendKVP(file, "resized")
+			default:
+				ctx.SendKVP(file, "ok")
+			}
+		}
+	}
+
+	ctx.SendFinal()
+}
+
 // ################################################################
 
 // obj, tags, label, ...
@@ -162,15 +278,19 @@ func (s *Service) GraphList(label string, gl []interface{}) []interface{} {
 	}
 
 	info := struct {
-		Obj   string
-		Label string
-		Hwab  bool
-		Tags  []string
+		Obj      string
+		Label    string
+		Hwab     bool // any detector, the ui only knows hwab
+		Detector string
+		Tags     []string
 	}{
 		Obj:   s.mon.Cf.Unique,
 		Label: label,
 		Tags:  tags,
-		Hwab:  s.p.Hwab != nil,
+		Hwab:  s.detector != nil,
+	}
+	if s.detector != nil {
+		info.Detector = s.detector.Name()
 	}
 
 	return append(gl, info)
@@ -0,0 +1,120 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 11:05 (EDT)
+// Function: hysteresis - separate trigger + clear thresholds, and must fail for a while
+
+package service
+
+import (
+	"fmt"
+	"math"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+)
+
+// per severity. NB - mapstructure cannot array, it can slice...
+type hyst struct {
+	Tripped []bool
+	Count   []int   // consecutive failed tests
+	Since   []int64 // first failed test
+}
+
+func (h *hyst) init() {
+
+	if len(h.Tripped) == int(argus.CRITICAL)+1 && len(h.Count) == len(h.Tripped) && len(h.Since) == len(h.Tripped) {
+		return
+	}
+
+	h.Tripped = make([]bool, argus.CRITICAL+1)
+	h.Count = make([]int, argus.CRITICAL+1)
+	h.Since = make([]int64, argus.CRITICAL+1)
+}
+
+func (s *Service) hystTripped(sev argus.Status) bool {
+
+	s.p.Hyst.init()
+	return s.p.Hyst.Tripped[sev]
+}
+
+// has the test at this severity failed long enough?
+func (s *Service) hystCheck(sev argus.Status, fail bool) bool {
+
+	h := &s.p.Hyst
+	h.init()
+
+	if !fail {
+		if h.Tripped[sev] {
+			s.Debug("TEST %s cleared", sev)
+		}
+		h.Tripped[sev] = false
+		h.Count[sev] = 0
+		h.Since[sev] = 0
+		return false
+	}
+
+	if h.Tripped[sev] {
+		return true
+	}
+
+	now := clock.Unix()
+	h.Count[sev]++
+	if h.Since[sev] == 0 {
+		h.Since[sev] = now
+	}
+
+	count, tlen := s.triggerConf(sev)
+
+	switch {
+	case count == 0 && tlen == 0:
+		h.Tripped[sev] = true
+	case count != 0 && h.Count[sev] >= count:
+		h.Tripped[sev] = true
+	case tlen != 0 && now-h.Since[sev] >= tlen:
+		h.Tripped[sev] = true
+	default:
+		s.Debug("TEST %s failed %d times, over %d sec. waiting", sev, h.Count[sev], now-h.Since[sev])
+	}
+
+	return h.Tripped[sev]
+}
+
+// trigger_count + trigger_time - the unadorned value is the default for all severities
+func (s *Service) triggerConf(sev argus.Status) (int, int64) {
+
+	count := s.Cf.Trigger_Count[sev]
+	tlen := s.Cf.Trigger_Time[sev]
+
+	if count == 0 && tlen == 0 {
+		count = s.Cf.Trigger_Count[argus.UNKNOWN]
+		tlen = s.Cf.Trigger_Time[argus.UNKNOWN]
+	}
+
+	return count, tlen
+}
+
+// for the about page
+func (s *Service) dumpHyst(dx argus.Dumper) {
+
+	for sev := argus.UNKNOWN; sev <= argus.CRITICAL; sev++ {
+		if sev == argus.CLEAR {
+			continue
+		}
+
+		name := "default"
+		if sev != argus.UNKNOWN {
+			name = sev.String()
+		}
+
+		count, tlen := s.triggerConf(sev)
+		cf := &s.Cf
+
+		if math.IsNaN(cf.Minvalue[sev]) && math.IsNaN(cf.Maxvalue[sev]) && cf.Trigger_Count[sev] == 0 && cf.Trigger_Time[sev] == 0 {
+			continue
+		}
+
+		dx.Dump("service/Hysteresis/"+name, fmt.Sprintf("min %v clear %v; max %v clear %v; trigger %d tests or %d sec; tripped %v",
+			cf.Minvalue[sev], cf.Minclear[sev], cf.Maxvalue[sev], cf.Maxclear[sev],
+			count, tlen, s.hystTripped(sev)))
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 11:40 (EDT)
+// Function:
+
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/monel"
+)
+
+func hystTest(t *testing.T, s *Service, vals []float64, exp []argus.Status) {
+
+	for i, v := range vals {
+		got, _ := s.testAndCompare("", v, "")
+		if got != exp[i] {
+			fmt.Printf("value %f (#%d) -> %s != %s\n", v, i, got, exp[i])
+			t.Fail()
+		}
+	}
+}
+
+func TestHysteresis(t *testing.T) {
+
+	s := &Service{mon: &monel.M{}, Cf: defaults}
+
+	// warn above 80, clear below 70
+	s.Cf.Maxvalue[argus.WARNING] = 80
+	s.Cf.Maxclear[argus.WARNING] = 70
+
+	hystTest(t, s,
+		[]float64{75, 81, 75, 71, 69, 75},
+		[]argus.Status{argus.CLEAR, argus.WARNING, argus.WARNING, argus.WARNING, argus.CLEAR, argus.CLEAR})
+
+	// critical above 90, 3 times in a row
+	s.Cf.Maxvalue[argus.CRITICAL] = 90
+	s.Cf.Trigger_Count[argus.CRITICAL] = 3
+
+	hystTest(t, s,
+		[]float64{95, 95, 85, 95, 95, 95, 50},
+		[]argus.Status{argus.WARNING, argus.WARNING, argus.WARNING, argus.WARNING, argus.WARNING, argus.CRITICAL, argus.CLEAR})
+}
@@ -68,14 +68,27 @@ func (s *Service) Config(conf *configure.CF) error {
 	s.calcmask = calcMask(s.Cf.Calc)
 	s.Cf.DARP_Tags = strings.ToLower(s.Cf.DARP_Tags)
 
-	hwab := false
+	detect := false
 	for i := argus.UNKNOWN; i <= argus.CRITICAL; i++ {
 		if !math.IsNaN(s.Cf.Maxdeviation[i]) {
-			hwab = true
+			detect = true
 		}
 	}
-	if hwab {
-		s.HwabConfig(conf)
+	if detect {
+		err = s.DetectorConfig(conf)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.forecastConfig()
+	if err != nil {
+		return err
+	}
+
+	err = s.graphConfig()
+	if err != nil {
+		return err
 	}
 
 	err = s.check.Config(conf, s)
@@ -94,8 +107,8 @@ func (s *Service) Config(conf *configure.CF) error {
 
 func (s *Service) Init() error {
 
-	if s.p.Hwab != nil {
-		s.p.Hwab.Init(s)
+	if s.detector != nil {
+		s.detector.Init(s)
 	}
 
 	err := s.check.Init()
@@ -0,0 +1,209 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-20 13:15 (EDT)
+// Function: multi-season percentile band anomaly detector
+
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+)
+
+type PctlConf struct {
+	Seasons   string // eg. "1d 7d"
+	Cycles    int    // how many previous cycles of each season to compare against
+	Low       float64
+	High      float64
+	Min_Count int
+}
+
+// compare with the same time in previous days, weeks, ...
+// values are averaged into TWIN sized buckets
+type SeasonPctl struct {
+	cf      PctlConf
+	seasons []int64
+	lock    sync.RWMutex
+	Hist    []float32 // ring buffer
+	Slot    []int32   // which time slot is in the ring - detects stale data
+	cslot   int64
+	ctotal  float64
+	ccount  int
+	Center  float64
+	Width   float64
+}
+
+var pctldefaults = PctlConf{
+	Seasons:   "1d 7d",
+	Cycles:    3,
+	Low:       5,
+	High:      95,
+	Min_Count: 3,
+}
+
+func (p *SeasonPctl) make() error {
+
+	var max int64
+
+	p.seasons = nil
+	for _, s := range strings.Fields(p.cf.Seasons) {
+		t, err := argus.Timespec(s, 1)
+		if err != nil || t < TWIN || t > PERIOD_MAX {
+			return fmt.Errorf("invalid pctl_seasons '%s'", p.cf.Seasons)
+		}
+		p.seasons = append(p.seasons, t)
+		if t > max {
+			max = t
+		}
+	}
+
+	if len(p.seasons) == 0 {
+		return fmt.Errorf("invalid pctl_seasons '%s'", p.cf.Seasons)
+	}
+	if p.cf.Cycles < 1 {
+		p.cf.Cycles = 1
+	}
+	if p.cf.Low < 0 || p.cf.High > 100 || p.cf.Low >= p.cf.High {
+		return fmt.Errorf("invalid pctl_low/pctl_high")
+	}
+
+	size := int(max/TWIN) * p.cf.Cycles
+	p.Hist = make([]float32, size)
+	p.Slot = make([]int32, size)
+	return nil
+}
+
+func (p *SeasonPctl) Name() string {
+	return "percentile"
+}
+
+// after persisted data is loaded
+func (p *SeasonPctl) Init(s *Service) error {
+
+	if len(p.Hist) != len(p.Slot) || len(p.Hist) == 0 {
+		return p.make()
+	}
+
+	// seasons or cycles were changed
+	max := int64(0)
+	for _, s := range p.seasons {
+		if s > max {
+			max = s
+		}
+	}
+	if size := int(max/TWIN) * p.cf.Cycles; size != len(p.Hist) {
+		dl.Debug("percentile history resized, resetting")
+		return p.make()
+	}
+
+	return nil
+}
+
+func (p *SeasonPctl) Add(val float64) {
+	p.AddAt(val, clock.Unix())
+}
+
+func (p *SeasonPctl) AddAt(val float64, now int64) {
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	slot := now / TWIN
+
+	if slot != p.cslot {
+		if p.ccount != 0 {
+			p.store(p.cslot, p.ctotal/float64(p.ccount))
+		}
+		p.cslot = slot
+		p.ctotal = 0
+		p.ccount = 0
+		p.predict(slot)
+	}
+
+	p.ctotal += val
+	p.ccount++
+}
+
+// lock is already held
+func (p *SeasonPctl) store(slot int64, val float64) {
+
+	i := int(slot % int64(len(p.Hist)))
+	p.Hist[i] = float32(val)
+	p.Slot[i] = int32(slot)
+}
+
+// lock is already held
+func (p *SeasonPctl) predict(slot int64) {
+
+	var vals []float64
+
+	for _, season := range p.seasons {
+		ss := season / TWIN
+		for c := int64(1); c <= int64(p.cf.Cycles); c++ {
+			// and the neighboring buckets, to smooth things
+			for d := int64(-1); d <= 1; d++ {
+				s := slot - c*ss + d
+				if s < 0 {
+					continue
+				}
+				i := int(s % int64(len(p.Hist)))
+				if int64(p.Slot[i]) == s {
+					vals = append(vals, float64(p.Hist[i]))
+				}
+			}
+		}
+	}
+
+	if len(vals) < p.cf.Min_Count {
+		p.Center = 0
+		p.Width = 0
+		return
+	}
+
+	lo := percentile(vals, p.cf.Low)
+	hi := percentile(vals, p.cf.High)
+
+	p.Center = median(vals)
+	p.Width = (hi - lo) / 2
+}
+
+func (p *SeasonPctl) Deviation(val float64) (float64, bool) {
+
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if p.Width == 0 {
+		return 0, false
+	}
+
+	return math.Abs(val-p.Center) / p.Width, true
+}
+
+func (p *SeasonPctl) Band() (float64, float64) {
+
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.Center, p.Width
+}
+
+func (p *SeasonPctl) Reset() {
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for i := range p.Hist {
+		p.Hist[i] = 0
+		p.Slot[i] = 0
+	}
+	p.Center = 0
+	p.Width = 0
+}
+
+func (p *SeasonPctl) Format(f fmt.State, c rune) {
+	fmt.Fprintf(f, "{seasons: %v, size: %d, prediction: %v +/- %v}", p.seasons, len(p.Hist), p.Center, p.Width)
+}
@@ -20,6 +20,7 @@ import (
 	"argus.domain/argus/config"
 	"argus.domain/argus/configure"
 	"argus.domain/argus/darp"
+	"argus.domain/argus/graph"
 	"argus.domain/argus/monel"
 	"argus.domain/argus/sched"
 )
@@ -58,6 +59,7 @@ type Conf struct {
 	JPath         string
 	XPath         string
 	Gr_what       string // only 'elapsed' is supported
+	Detector      string // hwab, ewma, mad, percentile
 	Testing       *argus.Schedule
 	Checking      *argus.Schedule
 	Expect        [argus.CRITICAL + 1]string  `cfconv:"dotsev"`
@@ -67,8 +69,24 @@ type Conf struct {
 	Eqvalue       [argus.CRITICAL + 1]float64 `cfconv:"dotsev"`
 	Nevalue       [argus.CRITICAL + 1]float64 `cfconv:"dotsev"`
 	Maxdeviation  [argus.CRITICAL + 1]float64 `cfconv:"dotsev"`
-	// graph,
+	Minclear      [argus.CRITICAL + 1]float64 `cfconv:"dotsev"` // hysteresis - once below minvalue, stay until above minclear
+	Maxclear      [argus.CRITICAL + 1]float64 `cfconv:"dotsev"`
+	Trigger_Count [argus.CRITICAL + 1]int     `cfconv:"dotsev"`          // must fail N consecutive tests
+	Trigger_Time  [argus.CRITICAL + 1]int64   `cfconv:"dotsev,timespec"` // or for this long
 
+	Forecast_Horizon int64  `cfconv:"timespec"` // alert if a threshold will be crossed within
+	Forecast_Window  int64  `cfconv:"timespec"` // fit the trend over
+	Forecast_Fit     string // linear, robust
+	Forecast_Data    string // hours, days
+
+	DARP_Gravity_Down [argus.CRITICAL + 1]float64 `cfconv:"dotsev"` // darp_gravity percent
+	DARP_Gravity_Up   [argus.CRITICAL + 1]float64 `cfconv:"dotsev"`
+
+	Graph_Resolution   int64  `cfconv:"timespec"` // at most one sample per
+	Graph_Keep_Samples int64  `cfconv:"timespec"` // how long to keep samples
+	Graph_Keep_Hours   int64  `cfconv:"timespec"` // hourly summaries
+	Graph_Keep_Days    int64  `cfconv:"timespec"` // daily summaries
+	Graph_Consolidate  string // average, min, max, last
 }
 
 var defaults = Conf{
@@ -79,6 +97,13 @@ var defaults = Conf{
 	DARP_Gravity: argus.GRAV_IETF,
 	Severity:     argus.CRITICAL,
 	Alpha:        1,
+
+	Forecast_Window: 14 * 86400,
+	Forecast_Fit:    "linear",
+	Forecast_Data:   "hours",
+
+	Graph_Resolution:  graphMinTime,
+	Graph_Consolidate: "average",
 }
 
 func init() {
@@ -89,6 +114,10 @@ func init() {
 		defaults.Eqvalue[i] = math.NaN()
 		defaults.Nevalue[i] = math.NaN()
 		defaults.Maxdeviation[i] = math.NaN()
+		defaults.Minclear[i] = math.NaN()
+		defaults.Maxclear[i] = math.NaN()
+		defaults.DARP_Gravity_Down[i] = math.NaN()
+		defaults.DARP_Gravity_Up[i] = math.NaN()
 	}
 }
 
@@ -100,6 +129,11 @@ type Persist struct {
 	Reason    string
 	Calc      calc
 	Hwab      *HWAB
+	Ewma      *EWMA
+	Mad       *MAD
+	Pctl      *SeasonPctl
+	Hyst      hyst
+	Forecast  forecast
 }
 
 type Service struct {
@@ -117,6 +151,8 @@ type Service struct {
 	alsoRun  []*Service
 	calcmask uint32
 	expr     []string
+	detector Detector
+	graphLay graph.Layout
 }
 
 var dl = diag.Logger("service")
@@ -351,7 +387,8 @@ func (s *Service) setResultForL(id string, status argus.Status, result string, r
 
 	s.p.Statuses[id] = status
 
-	return darp.AggrStatus(s.Cf.DARP_Gravity, status, s.p.Statuses)
+	pct := &argus.GravityPct{Down: s.Cf.DARP_Gravity_Down[:], Up: s.Cf.DARP_Gravity_Up[:]}
+	return darp.AggrStatus(s.Cf.DARP_Gravity, pct, status, s.p.Statuses)
 }
 
 // ################################################################
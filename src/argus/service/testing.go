@@ -72,6 +72,16 @@ func (s *Service) CheckValue(val string, valtype string) {
 	s.SetResult(status, val, reason)
 }
 
+// the monitor determined the status itself
+func (s *Service) CheckStatus(status argus.Status, val string, reason string) {
+
+	s.Elapsed = clock.Nano() - s.Started
+	s.ready = true
+
+	s.mon.Debug("value '%s' -> status %s (%s)", limitString(val, 16), status, reason)
+	s.SetResult(status, val, reason)
+}
+
 func limitString(s string, limit int) string {
 
 	if len(s) <= limit {
@@ -89,6 +99,12 @@ func (s *Service) testAndCompare(val string, fval float64, valtype string) (argu
 		fmt.Sscan(val, &fval)
 	}
 
+	status := argus.CLEAR
+	reason := ""
+
+	s.forecastUpdate()
+
+	// test every severity, so the hysteresis state stays current
 	for sev := argus.CRITICAL; sev >= argus.UNKNOWN; sev-- {
 
 		rsev := sev
@@ -99,45 +115,70 @@ func (s *Service) testAndCompare(val string, fval float64, valtype string) (argu
 			rsev = s.Cf.Severity
 		}
 
-		if s.Cf.Expect[sev] != "" {
-			if !testMatch(s.Cf.Expect[sev], val) {
-				return rsev, "TEST did not match expected regex"
-			}
+		why := s.testSev(sev, val, fval)
+		if !s.hystCheck(sev, why != "") {
+			continue
+		}
+
+		if status == argus.CLEAR {
+			status, reason = rsev, why
+		}
+	}
+
+	return status, reason
+}
+
+func (s *Service) testSev(sev argus.Status, val string, fval float64) string {
+
+	tripped := s.hystTripped(sev)
+
+	if s.Cf.Expect[sev] != "" {
+		if !testMatch(s.Cf.Expect[sev], val) {
+			return "TEST did not match expected regex"
+		}
+	}
+	if s.Cf.Nexpect[sev] != "" {
+		if testMatch(s.Cf.Nexpect[sev], val) {
+			return "TEST did matched unexpected regex"
 		}
-		if s.Cf.Nexpect[sev] != "" {
-			if testMatch(s.Cf.Nexpect[sev], val) {
-				return rsev, "TEST did matched unexpected regex"
-			}
+	}
+	if lim := s.Cf.Minvalue[sev]; !math.IsNaN(lim) {
+		if tripped && !math.IsNaN(s.Cf.Minclear[sev]) {
+			lim = s.Cf.Minclear[sev]
 		}
-		if !math.IsNaN(s.Cf.Minvalue[sev]) {
-			if fval < s.Cf.Minvalue[sev] {
-				return rsev, "TEST less than min"
-			}
+		if fval < lim {
+			return "TEST less than min"
 		}
-		if !math.IsNaN(s.Cf.Maxvalue[sev]) {
-			if fval > s.Cf.Maxvalue[sev] {
-				return rsev, "TEST more than max"
-			}
+	}
+	if lim := s.Cf.Maxvalue[sev]; !math.IsNaN(lim) {
+		if tripped && !math.IsNaN(s.Cf.Maxclear[sev]) {
+			lim = s.Cf.Maxclear[sev]
 		}
-		if !math.IsNaN(s.Cf.Eqvalue[sev]) {
-			if fval != s.Cf.Eqvalue[sev] {
-				return rsev, "TEST not equal"
-			}
+		if fval > lim {
+			return "TEST more than max"
 		}
-		if !math.IsNaN(s.Cf.Nevalue[sev]) {
-			if fval == s.Cf.Nevalue[sev] {
-				return rsev, "TEST equal"
-			}
+	}
+	if why := s.forecastSev(sev); why != "" {
+		return why
+	}
+	if !math.IsNaN(s.Cf.Eqvalue[sev]) {
+		if fval != s.Cf.Eqvalue[sev] {
+			return "TEST not equal"
 		}
-		if s.p.Hwab != nil && !math.IsNaN(s.Cf.Maxdeviation[sev]) {
-			dev, ok := s.p.Hwab.Deviation(fval)
-			if ok && dev > s.Cf.Maxdeviation[sev] {
-				return rsev, "TEST outside of predicted range"
-			}
+	}
+	if !math.IsNaN(s.Cf.Nevalue[sev]) {
+		if fval == s.Cf.Nevalue[sev] {
+			return "TEST equal"
+		}
+	}
+	if s.detector != nil && !math.IsNaN(s.Cf.Maxdeviation[sev]) {
+		dev, ok := s.detector.Deviation(fval)
+		if ok && dev > s.Cf.Maxdeviation[sev] {
+			return "TEST outside of predicted range"
 		}
 	}
 
-	return argus.CLEAR, ""
+	return ""
 }
 
 func (s *Service) getValue(val string, valtype string) (string, float64, string) {
@@ -182,7 +223,7 @@ func (s *Service) getValue(val string, valtype string) (string, float64, string)
 		s.Debug("unpack => value %f", fval)
 	}
 
-	if s.Cf.Scale != 0 || s.calcmask != 0 || s.Cf.Expr != "" || s.p.Hwab != nil {
+	if s.Cf.Scale != 0 || s.calcmask != 0 || s.Cf.Expr != "" || s.detector != nil {
 		if valtype != "" {
 			// convert string -> float
 			fmt.Sscan(val, &fval)
@@ -247,8 +288,8 @@ func (s *Service) getValue(val string, valtype string) (string, float64, string)
 		}
 	}
 
-	if s.p.Hwab != nil {
-		s.p.Hwab.Add(fval)
+	if s.detector != nil {
+		s.detector.Add(fval)
 	}
 
 	return val, fval, valtype
@@ -33,6 +33,7 @@ func (s *Service) WebJson(md map[string]interface{}) {
 	testinfo := make(map[string]interface{})
 	s.check.WebJson(testinfo)
 	md["testinfo"] = testinfo
+	s.forecastJson(md)
 }
 
 func (s *Service) WebMeta(md map[string]interface{}) {
@@ -0,0 +1,136 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-19 17:02 (EDT)
+// Function: user contact profiles
+
+package users
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"argus.domain/argus/argus"
+)
+
+// Expand converts 'user:name' or 'group:name' into the users' permitted contacts at the time.
+// other destinations are returned unchanged
+func Expand(dst string, sev argus.Status, now time.Time) []string {
+
+	switch {
+	case strings.HasPrefix(dst, "user:"):
+		u := Get(dst[5:])
+		if u == nil {
+			dl.Problem("unknown user '%s'", dst[5:])
+			return nil
+		}
+		return u.Contacts(sev, now)
+
+	case strings.HasPrefix(dst, "group:"):
+		var res []string
+		for _, u := range InGroup(dst[6:]) {
+			res = append(res, u.Contacts(sev, now)...)
+		}
+		return res
+	}
+
+	return []string{dst}
+}
+
+// InGroup returns the users in the group, sorted by name
+func InGroup(group string) []*User {
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if len(allusers) == 0 {
+		load()
+	}
+
+	var res []*User
+	for _, u := range allusers {
+		if argus.IncludesTag(u.Groups, group, false) {
+			res = append(res, u)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// Contacts returns the user's destinations, unless it is quiet time.
+// quiet hours do not apply to critical
+func (user *User) Contacts(sev argus.Status, now time.Time) []string {
+
+	if sev != argus.CRITICAL && user.IsQuiet(now) {
+		dl.Debug("user %s is in quiet hours", user.Name)
+		return nil
+	}
+
+	var res []string
+
+	if user.Email != "" {
+		res = append(res, "mail:"+user.Email)
+	}
+	if user.Pager != "" {
+		res = append(res, "qpage:"+user.Pager)
+	}
+	if user.Webhook != "" {
+		res = append(res, "webhook:"+user.Webhook)
+	}
+
+	return res
+}
+
+// quiet hours are: hhmm-hhmm, in the user's time zone
+func (user *User) IsQuiet(now time.Time) bool {
+
+	if user.Quiet_Hours == "" {
+		return false
+	}
+
+	start, end, err := parseQuiet(user.Quiet_Hours)
+	if err != nil {
+		dl.Problem("user %s: %v", user.Name, err)
+		return false
+	}
+
+	if user.TZ != "" {
+		loc, err := time.LoadLocation(user.TZ)
+		if err == nil {
+			now = now.In(loc)
+		} else {
+			dl.Problem("user %s: invalid time zone '%s'", user.Name, user.TZ)
+		}
+	}
+
+	hrs, min, _ := now.Clock()
+	tim := hrs*100 + min
+
+	if start <= end {
+		return tim >= start && tim < end
+	}
+	// overnight
+	return tim >= start || tim < end
+}
+
+func parseQuiet(q string) (int, int, error) {
+
+	f := strings.Split(strings.Replace(q, " ", "", -1), "-")
+	if len(f) != 2 {
+		return 0, 0, fmt.Errorf("invalid quiet hours '%s'", q)
+	}
+
+	start, err1 := strconv.Atoi(f[0])
+	end, err2 := strconv.Atoi(f[1])
+
+	valid := func(t int) bool { return t >= 0 && t/100 < 24 && t%100 < 60 }
+
+	if err1 != nil || err2 != nil || !valid(start) || !valid(end) {
+		return 0, 0, fmt.Errorf("invalid quiet hours '%s'", q)
+	}
+
+	return start, end, nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-24 14:20 (EDT)
+// Function:
+
+package users
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestParseQuiet(t *testing.T) {
+
+	for _, x := range []struct {
+		q          string
+		start, end int
+		ok         bool
+	}{
+		{"2200-0700", 2200, 700, true},
+		{"0900 - 1730", 900, 1730, true},
+		{"0000-2359", 0, 2359, true},
+		{"2400-0600", 0, 0, false},
+		{"2200-0760", 0, 0, false},
+		{"2575-0600", 0, 0, false},
+		{"-100-0600", 0, 0, false},
+		{"2200", 0, 0, false},
+		{"ten-six", 0, 0, false},
+	} {
+		s, e, err := parseQuiet(x.q)
+		if (err == nil) != x.ok || s != x.start || e != x.end {
+			fmt.Printf("%s: %d %d %v\n", x.q, s, e, err)
+			t.Fail()
+		}
+	}
+}
+
+func TestIsQuiet(t *testing.T) {
+
+	at := func(h, m int) time.Time { return time.Date(2026, 10, 24, h, m, 0, 0, time.UTC) }
+
+	for _, x := range []struct {
+		quiet string
+		tz    string
+		now   time.Time
+		exp   bool
+	}{
+		{"", "", at(3, 0), false},
+		{"0900-1700", "", at(12, 0), true},
+		{"0900-1700", "", at(17, 0), false},
+		{"0900-1700", "", at(8, 59), false},
+		// overnight
+		{"2200-0700", "", at(23, 30), true},
+		{"2200-0700", "", at(6, 59), true},
+		{"2200-0700", "", at(7, 0), false},
+		{"2200-0700", "", at(12, 0), false},
+		// 03:00 UTC is 23:00 EDT
+		{"2200-0700", "America/New_York", at(3, 0), true},
+		{"2200-0700", "America/New_York", at(12, 0), false},
+		// invalid => not quiet
+		{"2200-0790", "", at(23, 0), false},
+	} {
+		u := &User{Name: "alice", Quiet_Hours: x.quiet, TZ: x.tz}
+		if u.IsQuiet(x.now) != x.exp {
+			fmt.Printf("%s %s %s: expected %v\n", x.quiet, x.tz, x.now.Format("15:04"), x.exp)
+			t.Fail()
+		}
+	}
+}
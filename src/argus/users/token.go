@@ -0,0 +1,321 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-23 14:10 (EDT)
+// Function: api bearer tokens
+
+package users
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"argus.domain/argus/api"
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/config"
+)
+
+// tokens look like: ID.SECRET
+// only a hash of the secret is kept
+type Token struct {
+	Id       string
+	User     string
+	Hash     string
+	Scope    string // read, write, admin
+	Label    string
+	Created  int64
+	Expires  int64
+	LastUsed int64
+}
+
+const (
+	TOKENIDLEN     = 8
+	TOKENSECRETLEN = 24
+	LASTUSEDSAVE   = 3600 // don't save on every use
+)
+
+var scopeRank = map[string]int{
+	"read":  1,
+	"write": 2,
+	"admin": 3,
+}
+
+var tlock sync.Mutex
+var alltokens map[string]*Token
+
+func init() {
+	api.Add(true, "newtoken", apiNewToken)
+	api.Add(true, "listtokens", apiListTokens)
+	api.Add(true, "revoketoken", apiRevokeToken)
+}
+
+// ScopePermits - does a token with this scope permit that?
+func ScopePermits(have string, need string) bool {
+	return scopeRank[have] != 0 && scopeRank[have] >= scopeRank[need]
+}
+
+func ValidScope(scope string) bool {
+	return scopeRank[scope] != 0
+}
+
+// NewToken creates a new token for the user. the full token is only available now
+func NewToken(user string, scope string, label string, expires int64) (string, *Token, error) {
+
+	if Get(user) == nil {
+		return "", nil, fmt.Errorf("no such user '%s'", user)
+	}
+	if !ValidScope(scope) {
+		return "", nil, fmt.Errorf("invalid scope '%s'", scope)
+	}
+
+	id := make([]byte, TOKENIDLEN)
+	secret := make([]byte, TOKENSECRETLEN)
+	if _, err := rand.Read(id); err != nil {
+		return "", nil, err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, err
+	}
+
+	t := &Token{
+		Id:      hex.EncodeToString(id),
+		User:    user,
+		Hash:    tokenHash(argus.Encode64Url(string(secret))),
+		Scope:   scope,
+		Label:   label,
+		Created: clock.Unix(),
+		Expires: expires,
+	}
+
+	tlock.Lock()
+	defer tlock.Unlock()
+
+	loadTokens()
+	alltokens[t.Id] = t
+	saveTokens()
+
+	tc := *t
+	return t.Id + "." + argus.Encode64Url(string(secret)), &tc, nil
+}
+
+// CheckToken returns the user + token, if the token is valid
+func CheckToken(tok string) (*User, *Token) {
+
+	id, secret, ok := strings.Cut(tok, ".")
+	if !ok {
+		return nil, nil
+	}
+
+	tlock.Lock()
+	loadTokens()
+	t := alltokens[id]
+	if t == nil || subtle.ConstantTimeCompare([]byte(t.Hash), []byte(tokenHash(secret))) != 1 {
+		tlock.Unlock()
+		return nil, nil
+	}
+
+	now := clock.Unix()
+	if t.Expires != 0 && t.Expires < now {
+		tlock.Unlock()
+		return nil, nil
+	}
+	if now-t.LastUsed > LASTUSEDSAVE {
+		t.LastUsed = now
+		saveTokens()
+	}
+	tc := *t
+	tlock.Unlock()
+
+	u := Get(tc.User)
+	if u == nil {
+		return nil, nil
+	}
+	return u, &tc
+}
+
+// RevokeToken removes the token. the user must match, unless empty
+func RevokeToken(id string, user string) bool {
+
+	tlock.Lock()
+	defer tlock.Unlock()
+
+	loadTokens()
+	t := alltokens[id]
+	if t == nil || (user != "" && t.User != user) {
+		return false
+	}
+	delete(alltokens, id)
+	saveTokens()
+	return true
+}
+
+// RevokeUserTokens removes all of the user's tokens
+func RevokeUserTokens(user string) int {
+
+	tlock.Lock()
+	defer tlock.Unlock()
+
+	loadTokens()
+	n := 0
+	for id, t := range alltokens {
+		if t.User == user {
+			delete(alltokens, id)
+			n++
+		}
+	}
+	if n != 0 {
+		saveTokens()
+	}
+	return n
+}
+
+// Tokens returns the user's tokens (or everyone's), oldest first
+func Tokens(user string) []*Token {
+
+	tlock.Lock()
+	defer tlock.Unlock()
+
+	loadTokens()
+	var res []*Token
+	for _, t := range alltokens {
+		if user == "" || t.User == user {
+			tc := *t
+			res = append(res, &tc)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Created == res[j].Created {
+			return res[i].Id < res[j].Id
+		}
+		return res[i].Created < res[j].Created
+	})
+	return res
+}
+
+func tokenHash(secret string) string {
+	h := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(h[:])
+}
+
+// ################################################################
+
+// tlock is already held
+func loadTokens() {
+
+	if alltokens != nil {
+		return
+	}
+	alltokens = make(map[string]*Token)
+
+	cf := config.Cf()
+	if cf.Datadir == "" {
+		return
+	}
+
+	err := argus.Load(cf.Datadir+"/tokens", &alltokens)
+	if err != nil {
+		dl.Verbose("cannot load tokens: %v", err)
+	}
+}
+
+// tlock is already held
+func saveTokens() {
+
+	cf := config.Cf()
+	if cf.Datadir == "" {
+		dl.Debug("datadir not configured. cannot save tokens")
+		return
+	}
+
+	err := argus.Save(cf.Datadir+"/tokens", alltokens)
+	if err != nil {
+		dl.Problem("cannot save tokens: %v", err)
+	}
+}
+
+// ################################################################
+
+// argusctl newtoken user=NAME scope=read|write|admin [label=TEXT] [expires=90d]
+func apiNewToken(ctx *api.Context) {
+
+	var expires int64
+
+	if e := ctx.Args["expires"]; e != "" {
+		dur, err := argus.Timespec(e, 24*3600)
+		if err != nil || dur <= 0 {
+			ctx.SendResponseFinal(500, "invalid expires")
+			return
+		}
+		expires = clock.Unix() + dur
+	}
+
+	scope := ctx.Args["scope"]
+	if scope == "" {
+		scope = "read"
+	}
+
+	tok, t, err := NewToken(ctx.Args["user"], scope, ctx.Args["label"], expires)
+	if err != nil {
+		ctx.SendResponseFinal(500, err.Error())
+		return
+	}
+
+	ctx.SendOK()
+	ctx.SendKVP("id", t.Id)
+	ctx.SendKVP("token", tok)
+	ctx.SendFinal()
+}
+
+// argusctl listtokens [user=NAME]
+func apiListTokens(ctx *api.Context) {
+
+	ctx.SendOK()
+	for _, t := range Tokens(ctx.Args["user"]) {
+		ctx.Send(t.String() + "\n")
+	}
+	ctx.SendFinal()
+}
+
+// argusctl revoketoken id=ID | user=NAME
+func apiRevokeToken(ctx *api.Context) {
+
+	if id := ctx.Args["id"]; id != "" {
+		if !RevokeToken(id, "") {
+			ctx.Send404()
+			return
+		}
+		ctx.SendOKFinal()
+		return
+	}
+
+	if user := ctx.Args["user"]; user != "" {
+		n := RevokeUserTokens(user)
+		ctx.SendOK()
+		ctx.SendKVP("revoked", fmt.Sprintf("%d", n))
+		ctx.SendFinal()
+		return
+	}
+
+	ctx.SendResponseFinal(500, "must specify id or user")
+}
+
+func (t *Token) String() string {
+
+	date := func(v int64) string {
+		if v == 0 {
+			return "-"
+		}
+		return time.Unix(v, 0).Format("2006-01-02")
+	}
+
+	return fmt.Sprintf("%s %-10s %-5s created %s expires %s used %s %s", t.Id, t.User, t.Scope,
+		date(t.Created), date(t.Expires), date(t.LastUsed), t.Label)
+}
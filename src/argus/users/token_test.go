@@ -0,0 +1,81 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-23 19:10 (EDT)
+// Function:
+
+package users
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"argus.domain/argus/clock"
+	"argus.domain/argus/config"
+)
+
+func TestToken(t *testing.T) {
+
+	dir, err := os.MkdirTemp("", "users")
+	if err != nil {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+	config.Cf().Datadir = dir
+
+	(&User{Name: "alice", Groups: "staff"}).Update()
+
+	if _, _, err := NewToken("nobody", "read", "", 0); err == nil {
+		fmt.Printf("token for unknown user\n")
+		t.Fail()
+	}
+	if _, _, err := NewToken("alice", "superuser", "", 0); err == nil {
+		fmt.Printf("invalid scope accepted\n")
+		t.Fail()
+	}
+
+	tok, tk, err := NewToken("alice", "write", "ci", 0)
+	if err != nil {
+		t.FailNow()
+	}
+
+	u, ct := CheckToken(tok)
+	if u == nil || u.Name != "alice" || ct.Scope != "write" {
+		fmt.Printf("check failed\n")
+		t.Fail()
+	}
+	if u, _ := CheckToken(tk.Id + ".wrong"); u != nil {
+		fmt.Printf("bad secret accepted\n")
+		t.Fail()
+	}
+
+	// reload from disk
+	alltokens = nil
+	if u, _ := CheckToken(tok); u == nil {
+		fmt.Printf("not persisted\n")
+		t.Fail()
+	}
+
+	old, _, _ := NewToken("alice", "read", "old", clock.Unix()-10)
+	if u, _ := CheckToken(old); u != nil {
+		fmt.Printf("expired token accepted\n")
+		t.Fail()
+	}
+
+	if RevokeToken(tk.Id, "bob") {
+		fmt.Printf("revoked another user's token\n")
+		t.Fail()
+	}
+	if !RevokeToken(tk.Id, "alice") {
+		t.Fail()
+	}
+	if u, _ := CheckToken(tok); u != nil {
+		fmt.Printf("revoked token accepted\n")
+		t.Fail()
+	}
+
+	if !ScopePermits("admin", "write") || ScopePermits("read", "write") || ScopePermits("", "read") {
+		fmt.Printf("scopes\n")
+		t.Fail()
+	}
+}
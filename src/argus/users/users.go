@@ -7,6 +7,7 @@ package users
 
 import (
 	"sync"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
@@ -17,10 +18,15 @@ import (
 )
 
 type User struct {
-	Name    string
-	EPasswd string
-	Home    string
-	Groups  string
+	Name        string
+	EPasswd     string
+	Home        string
+	Groups      string
+	Email       string
+	Pager       string
+	Webhook     string
+	Quiet_Hours string // hhmm-hhmm
+	TZ          string
 }
 
 const BCRYPTCOST = 10
@@ -29,6 +35,9 @@ var lock sync.Mutex
 var allusers = make(map[string]*User)
 var dl = diag.Logger("users")
 
+// the rules live in notify, which sends to them. set from there
+var ValidWebhook = func(string) error { return nil }
+
 func init() {
 	api.Add(true, "setuser", apiSetUser)
 	api.Add(true, "getuser", apiGetUser)
@@ -147,6 +156,7 @@ func save() {
 // ################################################################
 
 // argusctl setuser name=NAME [passwd=PLAINTEXT] [home=HOME] [groups=GROUPS]
+//     [email=ADDR] [pager=ADDR] [webhook=URL] [quiet=HHMM-HHMM] [tz=ZONE]
 
 func apiSetUser(ctx *api.Context) {
 
@@ -176,10 +186,48 @@ func apiSetUser(ctx *api.Context) {
 		user.Groups = g
 	}
 
+	// contacts. use "-" to clear
+	setContact(&user.Email, ctx.Args["email"])
+	setContact(&user.Pager, ctx.Args["pager"])
+	if w := ctx.Args["webhook"]; w != "" && w != "-" {
+		if err := ValidWebhook(w); err != nil {
+			ctx.SendResponseFinal(500, err.Error())
+			return
+		}
+	}
+	setContact(&user.Webhook, ctx.Args["webhook"])
+
+	if q := ctx.Args["quiet"]; q != "" {
+		if _, _, err := parseQuiet(q); q != "-" && err != nil {
+			ctx.SendResponseFinal(500, err.Error())
+			return
+		}
+		setContact(&user.Quiet_Hours, q)
+	}
+	if tz := ctx.Args["tz"]; tz != "" {
+		if _, err := time.LoadLocation(tz); tz != "-" && err != nil {
+			ctx.SendResponseFinal(500, "invalid time zone")
+			return
+		}
+		setContact(&user.TZ, tz)
+	}
+
 	user.Update()
 	ctx.SendOKFinal()
 }
 
+func setContact(dst *string, val string) {
+
+	switch val {
+	case "":
+		return
+	case "-":
+		*dst = ""
+	default:
+		*dst = val
+	}
+}
+
 func apiGetUser(ctx *api.Context) {
 
 	name := ctx.Args["user"]
@@ -213,6 +261,7 @@ func apiDelUser(ctx *api.Context) {
 
 	if user != nil {
 		user.remove()
+		RevokeUserTokens(name)
 	}
 
 	ctx.SendOKFinal()
@@ -0,0 +1,141 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-23 16:20 (EDT)
+// Function: openapi description of the rest api, built from the routes
+
+package web
+
+import (
+	"strings"
+
+	"argus.domain/argus/argus"
+)
+
+func init() {
+	AddREST(&RESTRoute{
+		Method:  "GET",
+		Path:    "/openapi.json",
+		Tag:     "meta",
+		Summary: "this description of the api",
+		F:       restOpenAPI,
+	})
+}
+
+func restOpenAPI(ctx *Context) {
+	ctx.SendJSON(200, openAPIDoc(RESTList()))
+}
+
+type oaMap map[string]interface{}
+
+func openAPIDoc(routes []*RESTRoute) oaMap {
+
+	paths := oaMap{}
+
+	for _, rt := range routes {
+		p, _ := paths[APIV1+rt.Path].(oaMap)
+		if p == nil {
+			p = oaMap{}
+			paths[APIV1+rt.Path] = p
+		}
+		p[strings.ToLower(rt.Method)] = openAPIOp(rt)
+	}
+
+	return oaMap{
+		"openapi": "3.0.3",
+		"info": oaMap{
+			"title":       "argus",
+			"version":     "1",
+			"description": "argus " + argus.Version + " rest api. authenticate with a bearer token (see: argusctl newtoken)",
+		},
+		"paths": paths,
+		"components": oaMap{
+			"securitySchemes": oaMap{
+				"token": oaMap{"type": "http", "scheme": "bearer"},
+			},
+			"schemas": oaMap{
+				"Error": oaMap{
+					"type":       "object",
+					"properties": oaMap{"error": oaMap{"type": "string"}},
+				},
+			},
+		},
+	}
+}
+
+func openAPIOp(rt *RESTRoute) oaMap {
+
+	errResp := func(desc string) oaMap {
+		return oaMap{
+			"description": desc,
+			"content": oaMap{"application/json": oaMap{
+				"schema": oaMap{"$ref": "#/components/schemas/Error"},
+			}},
+		}
+	}
+
+	resp := oaMap{
+		"200": oaMap{
+			"description": "ok",
+			"content":     oaMap{"application/json": oaMap{"schema": oaMap{"type": "object"}}},
+		},
+		"400": errResp("invalid request"),
+		"404": errResp("not found"),
+	}
+
+	op := oaMap{
+		"summary":     rt.Summary,
+		"operationId": strings.ToLower(rt.Method) + strings.NewReplacer("/", "_", ".", "_").Replace(rt.Path),
+		"responses":   resp,
+	}
+	if rt.Tag != "" {
+		op["tags"] = []string{rt.Tag}
+	}
+
+	if rt.Scope != "" {
+		op["security"] = []oaMap{{"token": []string{}}}
+		op["description"] = "requires a token with " + rt.Scope + " scope"
+		resp["401"] = errResp("missing or invalid token")
+		resp["403"] = errResp("not permitted")
+	}
+
+	if len(rt.Params) == 0 {
+		return op
+	}
+
+	if rt.Method == "POST" || rt.Method == "PUT" {
+		// request body, json or form
+		props := oaMap{}
+		var reqd []string
+		for _, p := range rt.Params {
+			props[p.Name] = oaMap{"type": p.Type, "description": p.Desc}
+			if p.Required {
+				reqd = append(reqd, p.Name)
+			}
+		}
+		schema := oaMap{"type": "object", "properties": props}
+		if len(reqd) != 0 {
+			schema["required"] = reqd
+		}
+		op["requestBody"] = oaMap{
+			"content": oaMap{
+				"application/json":                  oaMap{"schema": schema},
+				"application/x-www-form-urlencoded": oaMap{"schema": schema},
+			},
+		}
+		return op
+	}
+
+	var params []oaMap
+	for _, p := range rt.Params {
+		params = append(params, oaMap{
+			"name":        p.Name,
+			"in":          "query",
+			"required":    p.Required,
+			"description": p.Desc,
+			"schema":      oaMap{"type": p.Type},
+		})
+	}
+	op["parameters"] = params
+
+	return op
+}
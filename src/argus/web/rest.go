@@ -0,0 +1,205 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-23 15:00 (EDT)
+// Function: versioned rest api, authenticated by bearer tokens
+
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"argus.domain/argus/users"
+)
+
+const (
+	APIV1       = "/api/v1"
+	MAXRESTBODY = 1024 * 1024
+)
+
+// a route is described, so the openapi doc can be generated
+type RESTRoute struct {
+	Method  string
+	Path    string // under /api/v1
+	Scope   string // read, write, admin. "" => public
+	Tag     string
+	Summary string
+	Params  []RESTParam
+	F       WebHandlerFunc
+}
+
+type RESTParam struct {
+	Name     string
+	Type     string // string, integer, boolean
+	Required bool
+	Desc     string
+}
+
+var restRoutes = make(map[string]map[string]*RESTRoute)
+
+func init() {
+	Mux.HandleFunc(APIV1+"/", restDispatch)
+}
+
+// AddREST adds a route to the rest api
+func AddREST(rt *RESTRoute) {
+
+	if restRoutes[rt.Path] == nil {
+		restRoutes[rt.Path] = make(map[string]*RESTRoute)
+		Mux.HandleFunc(APIV1+rt.Path, restDispatch)
+	}
+	restRoutes[rt.Path][rt.Method] = rt
+}
+
+// RESTList returns the routes, sorted by path + method
+func RESTList() []*RESTRoute {
+
+	var res []*RESTRoute
+	for _, ms := range restRoutes {
+		for _, rt := range ms {
+			res = append(res, rt)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Path == res[j].Path {
+			return res[i].Method < res[j].Method
+		}
+		return res[i].Path < res[j].Path
+	})
+	return res
+}
+
+// ################################################################
+
+func restDispatch(w http.ResponseWriter, r *http.Request) {
+
+	rw := &responseWriter{w: w, status: 200}
+	ctx := &Context{W: rw, R: r}
+
+	defer func() {
+		if x := recover(); x != nil {
+			dl.Bug("http panic: %v", x)
+		}
+
+		user := "[nouser]"
+		if ctx.User != nil {
+			user = ctx.User.Name
+		}
+		dl.Verbose("access: %s %s %d %d %s %s",
+			user, r.RemoteAddr, rw.status, rw.size, r.Method, r.RequestURI)
+	}()
+
+	ms := restRoutes[strings.TrimPrefix(r.URL.Path, APIV1)]
+	if ms == nil {
+		ctx.SendError(404, "not found")
+		return
+	}
+
+	rt := ms[r.Method]
+	if rt == nil {
+		var allow []string
+		for m := range ms {
+			allow = append(allow, m)
+		}
+		sort.Strings(allow)
+		ctx.W.Header().Set("Allow", strings.Join(allow, ", "))
+		ctx.SendError(405, "method not allowed")
+		return
+	}
+
+	if err := ctx.parseRESTForm(); err != nil {
+		ctx.SendError(400, err.Error())
+		return
+	}
+
+	if rt.Scope != "" {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "Bearer ") {
+			ctx.W.Header().Set("WWW-Authenticate", `Bearer realm="argus"`)
+			ctx.SendError(401, "token required")
+			return
+		}
+
+		u, t := users.CheckToken(strings.TrimSpace(auth[7:]))
+		if u == nil {
+			ctx.W.Header().Set("WWW-Authenticate", `Bearer realm="argus", error="invalid_token"`)
+			ctx.SendError(401, "invalid token")
+			return
+		}
+		if t.Scope == "admin" && !adminScopePermitted(u) {
+			// no longer in the admin acl
+			t.Scope = "write"
+		}
+		if !users.ScopePermits(t.Scope, rt.Scope) {
+			ctx.SendError(403, "token scope does not permit this")
+			return
+		}
+		ctx.User = u
+		ctx.Token = t
+	}
+
+	rt.F(ctx)
+}
+
+// query params, plus a form or json body. json values must be scalars
+func (ctx *Context) parseRESTForm() error {
+
+	r := ctx.R
+	r.Body = http.MaxBytesReader(ctx.W, r.Body, MAXRESTBODY)
+
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := r.ParseForm(); err != nil {
+			return fmt.Errorf("invalid request body")
+		}
+		return nil
+	}
+
+	r.ParseForm()
+
+	var body map[string]interface{}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("invalid json body")
+	}
+
+	for k, v := range body {
+		switch x := v.(type) {
+		case string:
+			r.Form.Set(k, x)
+		case float64:
+			r.Form.Set(k, strconv.FormatFloat(x, 'f', -1, 64))
+		case bool:
+			r.Form.Set(k, strconv.FormatBool(x))
+		case nil:
+			r.Form.Set(k, "")
+		default:
+			return fmt.Errorf("invalid value for '%s'", k)
+		}
+	}
+
+	return nil
+}
+
+// CanScope - does the request's token permit the scope?
+func (ctx *Context) CanScope(scope string) bool {
+	return ctx.Token != nil && users.ScopePermits(ctx.Token.Scope, scope)
+}
+
+func (ctx *Context) SendJSON(code int, v interface{}) {
+
+	js, _ := json.MarshalIndent(v, "", "  ")
+	ctx.W.Header().Set("Content-Type", "application/json; charset=utf-8")
+	ctx.W.WriteHeader(code)
+	ctx.W.Write(js)
+	ctx.W.Write([]byte("\n"))
+}
+
+func (ctx *Context) SendError(code int, msg string) {
+	ctx.SendJSON(code, map[string]string{"error": msg})
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-23 19:30 (EDT)
+// Function:
+
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"argus.domain/argus/clock"
+	"argus.domain/argus/config"
+	"argus.domain/argus/users"
+)
+
+func TestREST(t *testing.T) {
+
+	dir, err := os.MkdirTemp("", "web")
+	if err != nil {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+	config.Cf().Datadir = dir
+
+	(&users.User{Name: "alice", Groups: "staff"}).Update()
+	rtok, _, _ := users.NewToken("alice", "read", "", 0)
+	atok, _, _ := users.NewToken("alice", "admin", "", 0) // alice is not root
+
+	var got string
+	AddREST(&RESTRoute{Method: "POST", Path: "/test", Scope: "write",
+		Params: []RESTParam{{Name: "n", Type: "integer"}},
+		F: func(ctx *Context) {
+			got = ctx.Get("n")
+			ctx.SendJSON(200, map[string]string{"user": ctx.User.Name})
+		}})
+
+	try := func(name, method, path, tok, body string, code int) {
+		r := httptest.NewRequest(method, APIV1+path, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		if tok != "" {
+			r.Header.Set("Authorization", "Bearer "+tok)
+		}
+		w := httptest.NewRecorder()
+		Mux.ServeHTTP(w, r)
+		if w.Code != code {
+			fmt.Printf("%s: got %d, expected %d\n", name, w.Code, code)
+			t.Fail()
+		}
+	}
+
+	try("notoken", "POST", "/test", "", "", 401)
+	try("badtoken", "POST", "/test", "xyz.abc", "", 401)
+	try("scope", "POST", "/test", rtok, "", 403)
+	try("method", "GET", "/test", rtok, "", 405)
+	try("notfound", "GET", "/nothing", rtok, "", 404)
+	try("badjson", "POST", "/test", atok, "{", 400)
+	try("ok", "POST", "/test", atok, `{"n": 42}`, 200)
+	if got != "42" {
+		fmt.Printf("json body: %s\n", got)
+		t.Fail()
+	}
+
+	// admin scope is reduced, alice is not permitted
+	r := httptest.NewRequest("GET", APIV1+"/tokens?user=*", nil)
+	r.Header.Set("Authorization", "Bearer "+atok)
+	w := httptest.NewRecorder()
+	Mux.ServeHTTP(w, r)
+	if w.Code != 403 {
+		fmt.Printf("admin acl: %d\n", w.Code)
+		t.Fail()
+	}
+
+	// a short lived token cannot make a longer lived one
+	stok, st, _ := users.NewToken("alice", "write", "", clock.Unix()+3600)
+	for _, exp := range []string{"", "90d"} {
+		r = httptest.NewRequest("POST", APIV1+"/tokens", strings.NewReader(`{"scope": "read", "expires": "`+exp+`"}`))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("Authorization", "Bearer "+stok)
+		w = httptest.NewRecorder()
+		Mux.ServeHTTP(w, r)
+
+		var nt tokenInfo
+		if w.Code != 200 || json.Unmarshal(w.Body.Bytes(), &nt) != nil || nt.Expires != st.Expires {
+			fmt.Printf("outlived: %d %d != %d\n", w.Code, nt.Expires, st.Expires)
+			t.Fail()
+		}
+	}
+
+	// public
+	r = httptest.NewRequest("GET", APIV1+"/openapi.json", nil)
+	w = httptest.NewRecorder()
+	Mux.ServeHTTP(w, r)
+
+	var doc struct {
+		Paths map[string]map[string]interface{}
+	}
+	if w.Code != 200 || json.Unmarshal(w.Body.Bytes(), &doc) != nil || doc.Paths[APIV1+"/test"]["post"] == nil {
+		fmt.Printf("openapi: %d\n", w.Code)
+		t.Fail()
+	}
+}
@@ -27,9 +27,12 @@ type WebConf struct {
 	Header_Branding string
 	Footer          string
 	Login_Notice    string
+	API_Admin_ACL   string // who may have admin scope api tokens
 }
 
-var webConf = WebConf{}
+var webConf = WebConf{
+	API_Admin_ACL: "root",
+}
 
 // load config from config file
 func Configure(cf *configure.CF) {
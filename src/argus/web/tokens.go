@@ -0,0 +1,202 @@
+// Copyright (c) 2026
+// Author: Jeff Weisberg <jaw @ tcp4me.com>
+// Created: 2026-Oct-23 16:50 (EDT)
+// Function: manage api tokens - from the web ui + the rest api
+
+package web
+
+import (
+	"strings"
+
+	"argus.domain/argus/argus"
+	"argus.domain/argus/clock"
+	"argus.domain/argus/users"
+)
+
+// what we show. never the hash
+type tokenInfo struct {
+	Id       string
+	User     string
+	Scope    string
+	Label    string
+	Created  int64
+	Expires  int64
+	LastUsed int64
+}
+
+func init() {
+	// web ui, for the logged in user
+	Add(PRIVATE, "/api/tokens", webTokens)
+	Add(WRITE, "/api/tokennew", webTokenNew)
+	Add(WRITE, "/api/tokenrevoke", webTokenRevoke)
+
+	AddREST(&RESTRoute{
+		Method:  "GET",
+		Path:    "/tokens",
+		Scope:   "read",
+		Tag:     "tokens",
+		Summary: "list api tokens",
+		Params: []RESTParam{
+			{Name: "user", Type: "string", Desc: "another user's tokens (admin), or * for all"},
+		},
+		F: restTokens,
+	})
+	AddREST(&RESTRoute{
+		Method:  "POST",
+		Path:    "/tokens",
+		Scope:   "write",
+		Tag:     "tokens",
+		Summary: "create an api token. the token is only returned now",
+		Params: []RESTParam{
+			{Name: "scope", Type: "string", Required: true, Desc: "read, write, or admin. no more than the requesting token's"},
+			{Name: "label", Type: "string", Desc: "what it is for"},
+			{Name: "expires", Type: "string", Desc: "lifetime, eg. 90d. default: never. no later than the requesting token's"},
+			{Name: "user", Type: "string", Desc: "for another user (admin)"},
+		},
+		F: restTokenNew,
+	})
+	AddREST(&RESTRoute{
+		Method:  "DELETE",
+		Path:    "/tokens",
+		Scope:   "write",
+		Tag:     "tokens",
+		Summary: "revoke an api token",
+		Params: []RESTParam{
+			{Name: "id", Type: "string", Required: true, Desc: "token id"},
+		},
+		F: restTokenRevoke,
+	})
+}
+
+// may this user have admin scope tokens?
+func adminScopePermitted(u *users.User) bool {
+	return argus.ACLPermitsUser(webConf.API_Admin_ACL, strings.Fields(u.Groups))
+}
+
+func tokenList(user string) []*tokenInfo {
+
+	res := []*tokenInfo{}
+	for _, t := range users.Tokens(user) {
+		res = append(res, &tokenInfo{t.Id, t.User, t.Scope, t.Label, t.Created, t.Expires, t.LastUsed})
+	}
+	return res
+}
+
+// scope, label, expires => new token
+// limit - the latest it may expire, 0 => no limit
+func newTokenFromArgs(ctx *Context, user *users.User, limit int64) {
+
+	scope := ctx.Get("scope")
+	if !users.ValidScope(scope) {
+		ctx.SendError(400, "invalid scope")
+		return
+	}
+	if scope == "admin" && !adminScopePermitted(user) {
+		ctx.SendError(403, "admin scope not permitted")
+		return
+	}
+
+	var expires int64
+	if e := ctx.Get("expires"); e != "" {
+		dur, err := argus.Timespec(e, 24*3600)
+		if err != nil || dur <= 0 {
+			ctx.SendError(400, "invalid expires")
+			return
+		}
+		expires = clock.Unix() + dur
+	}
+	if limit != 0 && (expires == 0 || expires > limit) {
+		expires = limit
+	}
+
+	tok, t, err := users.NewToken(user.Name, scope, ctx.Get("label"), expires)
+	if err != nil {
+		ctx.SendError(400, err.Error())
+		return
+	}
+
+	dl.Verbose("new %s token %s for %s", scope, t.Id, user.Name)
+	ctx.SendJSON(200, struct {
+		tokenInfo
+		Token string
+	}{tokenInfo{t.Id, t.User, t.Scope, t.Label, t.Created, t.Expires, t.LastUsed}, tok})
+}
+
+// ################################################################
+
+func webTokens(ctx *Context) {
+
+	ctx.SendJSON(200, map[string]interface{}{
+		"list":     tokenList(ctx.User.Name),
+		"canAdmin": adminScopePermitted(ctx.User),
+	})
+}
+
+func webTokenNew(ctx *Context) {
+	newTokenFromArgs(ctx, ctx.User, 0)
+}
+
+func webTokenRevoke(ctx *Context) {
+
+	if !users.RevokeToken(ctx.Get("id"), ctx.User.Name) {
+		ctx.SendError(404, "no such token")
+		return
+	}
+	ctx.SendJSON(200, map[string]interface{}{"list": tokenList(ctx.User.Name)})
+}
+
+// ################################################################
+
+func restTokens(ctx *Context) {
+
+	user := ctx.Get("user")
+	switch {
+	case user == "" || user == ctx.User.Name:
+		user = ctx.User.Name
+	case !ctx.CanScope("admin"):
+		ctx.SendError(403, "admin scope required")
+		return
+	case user == "*":
+		user = ""
+	}
+
+	ctx.SendJSON(200, map[string]interface{}{"list": tokenList(user)})
+}
+
+func restTokenNew(ctx *Context) {
+
+	if !ctx.CanScope(ctx.Get("scope")) {
+		ctx.SendError(403, "token scope does not permit this")
+		return
+	}
+
+	user := ctx.User
+	if name := ctx.Get("user"); name != "" && name != user.Name {
+		if !ctx.CanScope("admin") {
+			ctx.SendError(403, "admin scope required")
+			return
+		}
+		user = users.Get(name)
+		if user == nil {
+			ctx.SendError(404, "no such user")
+			return
+		}
+	}
+
+	// a token cannot outlive the one that made it
+	newTokenFromArgs(ctx, user, ctx.Token.Expires)
+}
+
+func restTokenRevoke(ctx *Context) {
+
+	owner := ctx.User.Name
+	if ctx.CanScope("admin") {
+		owner = ""
+	}
+
+	if !users.RevokeToken(ctx.Get("id"), owner) {
+		ctx.SendError(404, "no such token")
+		return
+	}
+	ctx.SendJSON(200, map[string]interface{}{"revoked": ctx.Get("id")})
+}
@@ -33,6 +33,7 @@ type Context struct {
 	Hush      int64
 	W         http.ResponseWriter
 	R         *http.Request
+	Token     *users.Token // rest api requests
 }
 
 type Server struct {
@@ -45,15 +46,22 @@ type WebHandlerFunc func(*Context)
 var dl = diag.Logger("web")
 var server *Server
 var Mux = http.NewServeMux()
+var stopping = make(chan struct{})
 
 func Init() {
 	load() // load sessions
 	server = Start()
 }
 func Stop() {
+	close(stopping)
 	server.Shutdown()
 }
 
+// Stopping is closed at shutdown, so long running (streaming) requests can finish
+func Stopping() <-chan struct{} {
+	return stopping
+}
+
 func Start() *Server {
 
 	cf := config.Cf()
@@ -221,6 +229,13 @@ func (w *responseWriter) WriteHeader(s int) {
 	w.w.WriteHeader(s)
 }
 
+// for streaming responses
+func (w *responseWriter) Flush() {
+	if f, ok := w.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 // ################################################################
 
 func webLog(ctx *Context) {
@@ -8,6 +8,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -24,10 +25,11 @@ type KVP struct {
 
 func main() {
 
-	var rawoutput bool
+	var rawoutput, quiet bool
 	var controlsock string
 	flag.StringVar(&controlsock, "s", argus.ControlSocket, "control socket")
 	flag.BoolVar(&rawoutput, "r", false, "raw output")
+	flag.BoolVar(&quiet, "q", false, "raw output, without the status line")
 	flag.Parse()
 
 	c, err := client.New("unix", controlsock, TIMEOUT)
@@ -48,12 +50,28 @@ func main() {
 		}
 	}
 
+	if method == "tail" {
+		tail(c, args)
+		return
+	}
+
 	resp, err := c.Get(method, args, TIMEOUT)
 	if err != nil {
 		fmt.Printf("error: %v", err)
 		return
 	}
 
+	if quiet {
+		if resp.Code != 200 {
+			fmt.Fprintf(os.Stderr, "%d %s\n", resp.Code, resp.Msg)
+			os.Exit(1)
+		}
+		for _, l := range resp.Lines {
+			fmt.Printf("%s\n", l)
+		}
+		return
+	}
+
 	fmt.Printf("%d %s\n", resp.Code, resp.Msg)
 
 	var kvp []KVP
@@ -83,3 +101,27 @@ func main() {
 		fmt.Printf("%-*s  %s\n", maxlen+1, kv.k+":", kv.v)
 	}
 }
+
+// argusctl tail obj=Top:Foo type=transition,override
+// print events as they happen, until interrupted
+// the final lastid goes to stderr, use it to resume: argusctl tail lastid=...
+func tail(c *client.Conn, args map[string]string) {
+
+	resp, err := c.Stream("tail", args, func(l string) bool {
+		if strings.HasPrefix(l, "lastid: ") {
+			fmt.Fprintf(os.Stderr, "%s\n", l)
+			return true
+		}
+		fmt.Printf("%s\n", l)
+		return true
+	})
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		return
+	}
+
+	if resp.Code != 200 {
+		fmt.Fprintf(os.Stderr, "%d %s\n", resp.Code, resp.Msg)
+		os.Exit(1)
+	}
+}
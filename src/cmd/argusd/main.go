@@ -28,6 +28,8 @@ import (
 	"argus.domain/argus/construct"
 	"argus.domain/argus/darp"
 	"argus.domain/argus/graph/graphd"
+	"argus.domain/argus/history"
+	"argus.domain/argus/maint"
 	"argus.domain/argus/monel"
 	_ "argus.domain/argus/monitor"
 	"argus.domain/argus/monitor/ping"
@@ -121,18 +123,25 @@ func main() {
 	// init stats dir, etal
 	createStatsDirs()
 	createGdataDirs()
+	createAvailDirs()
 	createNotifyDirs()
+	createHistoryDirs()
 	initCleanDirs()
 
 	// read large config
 	if cf.Monitor_config != "" {
 		files := construct.ReadConfig(cf.Monitor_config)
+		history.Add(&history.Event{Type: "config", Msg: fmt.Sprintf("loaded %s (%d files), version %s",
+			cf.Monitor_config, len(files), argus.Version)})
 
 		if cf.Auto_Reload && !foreground {
 			go watchFiles(files)
 		}
 	}
 
+	// after config is loaded
+	maint.Init()
+
 	if cf.DevMode {
 		api.Add(true, "trace", apiTrace)
 	}
@@ -158,6 +167,7 @@ func main() {
 	status = "shutting down"
 	notify.Stop()
 	monel.Stop()
+	history.Stop()
 	diag.Verbose("stopped")
 	argus.Loggit("", "Argus exiting")
 	os.Exit(exitvalue)
@@ -225,8 +235,10 @@ func sigHandle() {
 				continue
 			case syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT:
 				exitvalue = daemon.ExitFinished
+				history.Add(&history.Event{Type: "config", Msg: "stopping"})
 			case syscall.SIGHUP:
 				exitvalue = daemon.ExitRestart
+				history.Add(&history.Event{Type: "config", Msg: "restarting to reload config"})
 			default:
 				exitvalue = daemon.ExitRestart
 			}
@@ -250,6 +262,7 @@ func watchFiles(files []string) {
 
 			if t.After(start) {
 				dl.Verbose("config file '%s' changed - restarting", f)
+				history.Add(&history.Event{Type: "config", Msg: fmt.Sprintf("config file '%s' changed", f)})
 				sigchan <- syscall.SIGHUP
 				return
 			}
@@ -267,6 +280,23 @@ func createGdataDirs() {
 	createDirs("gdata")
 }
 
+func createAvailDirs() {
+	createDirs("avail")
+}
+
+func createHistoryDirs() {
+
+	dir := history.Dir()
+	if dir == "" {
+		return
+	}
+
+	err := mkdir(dir)
+	if err != nil {
+		dl.Fatal("cannot create '%s': %v", dir, err)
+	}
+}
+
 func createNotifyDirs() {
 
 	cf := config.Cf()
@@ -392,6 +422,7 @@ func apiStop(ctx *api.Context) {
 
 	ctx.SendOKFinal()
 	exitvalue = 0
+	history.Add(&history.Event{Type: "config", Msg: "stopping"})
 	sched.Stop()
 }
 